@@ -4,105 +4,395 @@ package main
 import (
   "context"
   "flag"
+  "fmt"
+  "io"
   "log"
+  "os"
   "os/signal"
+  "strconv"
   "strings"
   "syscall"
   "time"
+  _ "time/tzdata" // fixing schedules name IANA zones; don't depend on the host's zoneinfo
 
   "github.com/binaridigital/price-engine/pkg/aggregate"
-  "github.com/binaridigital/price-engine/pkg/common"
+  "github.com/binaridigital/price-engine/pkg/alert"
+  "github.com/binaridigital/price-engine/pkg/auth"
+  "github.com/binaridigital/price-engine/pkg/calendar"
+  "github.com/binaridigital/price-engine/pkg/engine"
+  "github.com/binaridigital/price-engine/pkg/fixing"
   "github.com/binaridigital/price-engine/pkg/grpcapi"
+  "github.com/binaridigital/price-engine/pkg/indicator"
   "github.com/binaridigital/price-engine/pkg/ingest"
+  "github.com/binaridigital/price-engine/pkg/metering"
+  "github.com/binaridigital/price-engine/pkg/profile"
+  "github.com/binaridigital/price-engine/pkg/synth"
   pkafka "github.com/binaridigital/price-engine/pkg/kafka"
 )
 
 func main() {
-  grpcAddr   := flag.String("grpc-addr", ":8080", "gRPC listen address")
+  grpcAddr   := flag.String("grpc-addr", ":8080", "listen address for gRPC, gRPC-Web and Connect")
+  historySize := flag.Int("history-size", grpcapi.DefaultHistory, "closed windows kept in memory per symbol for GetCandles and /v1/candles (0 = none)")
+  corsOrigins := flag.String("cors-origins", "", "comma-separated origins allowed to call gRPC-Web/Connect from a browser (* = any)")
+  // Connections and probes
+  keepaliveTime    := flag.Duration("keepalive-time", grpcapi.DefaultKeepaliveTime, "ping HTTP/2 connections idle for this long (0 disables)")
+  keepaliveTimeout := flag.Duration("keepalive-timeout", grpcapi.DefaultKeepaliveTimeout, "close connections whose ping is not answered within this")
+  keepaliveMinTime := flag.Duration("keepalive-min-time", grpcapi.DefaultKeepaliveMinTime, "disconnect gRPC clients that ping more often than this")
+  keepaliveIdle    := flag.Bool("keepalive-permit-without-stream", true, "let gRPC clients ping connections without open streams")
+  maxConnAge       := flag.Duration("max-connection-age", 0, "ask clients to reconnect after this long, e.g. to rebalance behind a load balancer (0 = never)")
+  maxConnAgeGrace  := flag.Duration("max-connection-age-grace", 0, "close connections this long after --max-connection-age, ending open streams (0 = never)")
+  sendTimeout      := flag.Duration("send-timeout", grpcapi.DefaultSendTimeout, "fail a stream whose client does not accept a write within this (0 disables)")
+  readyMaxLag      := flag.Duration("ready-max-lag", grpcapi.DefaultMaxLag, "report not ready when no candle was published for this long while a market is open")
+  // Stream heartbeats and market status
+  heartbeat    := flag.Duration("heartbeat-interval", grpcapi.DefaultHeartbeat, "default time a StreamAggregateEvents stream stays silent before a heartbeat")
+  calendarFile := flag.String("calendar-file", "", "JSON trading calendars, symbol assignments and halts; reloaded when it changes (default: built-in fx and crypto)")
+  // Security
+  tlsCert     := flag.String("tls-cert", "", "PEM server certificate; enables TLS together with --tls-key")
+  tlsKey      := flag.String("tls-key", "", "PEM server private key")
+  tlsClientCA := flag.String("tls-client-ca", "", "PEM CA bundle for client certificates (mTLS)")
+  authConfig  := flag.String("auth-config", "", "JSON file of API clients, keys, JWT settings and entitlements; enables authentication")
+  auditLog    := flag.String("audit-log", "", "file that denied requests are appended to as JSON lines (default stderr)")
+  // Usage metering (optional)
+  meterEnable := flag.Bool("metering", false, "meter subscriptions, messages, bytes and stream time per client and symbol, and enforce quotas")
+  meterFile   := flag.String("metering-file", "usage.json", "file usage is persisted to and restored from (empty keeps it in memory)")
+  meterFlush  := flag.Duration("metering-flush", time.Minute, "how often usage is written to --metering-file")
+  meterKeep   := flag.Int("metering-retention-days", metering.DefaultRetention, "UTC days of usage kept, today included (0 keeps everything)")
   symbolsCSV := flag.String("symbols", "BTCUSDT", "comma-separated symbols (e.g., BTCUSDT,EURUSD)")
   exchanges  := flag.String("exchanges", "binance", "comma-separated connectors: binance,tradermade,twelvedata")
   interval   := flag.Duration("interval", time.Second, "aggregation window (e.g., 1s)")
+  // Redundancy
+  standby     := flag.String("standby", "", "comma-separated connectors to run a hot-standby duplicate connection for (e.g., binance)")
+  dedupWindow := flag.Duration("dedup-window", ingest.DefaultDedupWindow, "how long venue trade IDs are remembered to drop duplicates")
+  // Stale-feed detection
+  staleFactor := flag.Float64("stale-factor", ingest.DefaultStaleConfig.Factor, "inactivity timeout as a multiple of a stream's average message gap")
+  staleMin    := flag.Duration("stale-min", ingest.DefaultStaleConfig.Min, "lower bound of the adaptive inactivity timeout")
+  staleMax    := flag.Duration("stale-max", ingest.DefaultStaleConfig.Max, "upper bound of the adaptive inactivity timeout")
+  wsPing      := flag.Duration("ws-ping-interval", ingest.DefaultStaleConfig.PingInterval, "websocket keepalive ping interval (0 disables)")
+  // Bad-tick filter
+  tickFilter    := flag.Bool("tick-filter", false, "reject non-positive prices, future timestamps and outliers before aggregation")
+  filterWindow  := flag.Int("tick-filter-window", ingest.DefaultFilterConfig.Window, "accepted prices per symbol the outlier band is computed from")
+  filterMaxZ    := flag.Float64("tick-filter-max-z", ingest.DefaultFilterConfig.MaxZ, "max robust z-score (median/MAD) before a trade is rejected")
+  filterMinBand := flag.Float64("tick-filter-min-band-bps", ingest.DefaultFilterConfig.MinBandBps, "floor on the outlier band scale (bps of price)")
+  filterFuture  := flag.Duration("tick-filter-max-future", ingest.DefaultFilterConfig.MaxFuture, "reject trades stamped further ahead of the local clock")
+  // Composite price across venues (optional)
+  priceMode      := flag.String("price-mode", "mixed", "multi-venue price mode: mixed (all trades), median or vwap consensus")
+  consensusDev   := flag.Float64("consensus-max-deviation-bps", aggregate.DefaultMaxDeviationBps, "exclude venues deviating more than this from the cross-venue median (bps)")
+  consensusStale := flag.Duration("consensus-stale-after", aggregate.DefaultStaleAfter, "exclude venues without trades for this long from the consensus")
+  // Order flow
+  sizeBuckets := flag.String("size-buckets", formatFloats(aggregate.DefaultSizeBuckets), "positive, strictly ascending trade notional bounds for the trade size distribution")
+  // Synthetic cross rates (optional)
+  syntheticCSV := flag.String("synthetic", "", "comma-separated cross pairs to triangulate from ingested symbols (e.g., EURJPY,BTCEUR)")
+  synthLegAge  := flag.Duration("synthetic-max-leg-age", synth.DefaultMaxLegAge, "max age of a leg price used for a synthetic cross")
+  // L1 quotes (optional)
+  quoteSymbols := flag.String("quote-symbols", "", "comma-separated Binance symbols to ingest best bid/offer (@bookTicker) for")
+  // L2 order books (optional)
+  depthSymbols := flag.String("depth-symbols", "", "comma-separated Binance symbols to maintain L2 order books for (e.g., BTCUSDT)")
+  depthLevels  := flag.Int("depth-levels", ingest.DefaultBookLevels, "levels per side kept in published book snapshots")
+  // Volume profile / footprint (optional)
+  profileEnable   := flag.Bool("profile", false, "build per-window volume profiles (volume at price, POC, value area)")
+  profileInterval := flag.Duration("profile-interval", time.Minute, "volume profile window")
+  profileTicks    := flag.String("profile-ticks", "", "per-symbol price tick for profile levels (e.g., BTCUSDT:10,EURUSD:0.0001); others use ~price/1000")
+  profileVA       := flag.Float64("profile-value-area", profile.DefaultValueArea, "share of volume covered by the value area")
+  // Technical indicators on final candles (optional)
+  indicatorsCSV    := flag.String("indicators", "", "indicators on final candles for every symbol (e.g., sma:20,ema:50,rsi:14,bb:20:2,atr:14)")
+  symbolIndicators := flag.String("symbol-indicators", "", "per-symbol indicator overrides (e.g., BTCUSDT=rsi:14,bb:20:2;EURUSD=sma:10)")
+  // Reference price fixings (optional)
+  fixingsSpec := flag.String("fixings", "", "daily fixing schedules separated by ';' (e.g., \"name=LDN4PM symbols=EURUSD,GBPUSD at=16:00 tz=Europe/London window=5m method=twap\")")
+  fixingGrace := flag.Duration("fixing-grace", fixing.DefaultGrace, "wait this long after a fixing window closes for late trades")
+  // Alerts (optional)
+  alertsEnable := flag.Bool("alerts", false, "evaluate alert rules on final candles (rules managed via CreateAlertRule/DeleteAlertRule)")
+  alertRules   := flag.String("alert-rules-file", "alert-rules.json", "file alert rules are persisted to")
+  alertWebhook := flag.String("alert-webhook-url", "", "POST alerts of rules with webhook=true to this local URL (localhost or private IP)")
   // Kafka (optional)
   kafkaEnable  := flag.Bool("kafka-enable", false, "publish to Kafka")
   kafkaBrokers := flag.String("kafka-brokers", "localhost:9092", "kafka brokers (comma)")
   kafkaTopic   := flag.String("kafka-topic", "agg.candles.v1", "kafka topic")
+  kafkaProfileTopic := flag.String("kafka-profile-topic", "agg.profiles.v1", "kafka topic for volume profiles")
+  kafkaFixingTopic  := flag.String("kafka-fixing-topic", "agg.fixings.v1", "kafka topic for fixings")
+  kafkaQuarantineTopic := flag.String("kafka-quarantine-topic", "agg.quarantine.v1", "kafka topic for trades rejected by the tick filter")
+  kafkaAcks         := flag.String("kafka-acks", "all", "required acks per write: none, one or all")
+  kafkaBatchSize    := flag.Int("kafka-batch-size", pkafka.DefaultConfig.BatchSize, "max messages per produce batch")
+  kafkaBatchTimeout := flag.Duration("kafka-batch-timeout", pkafka.DefaultConfig.BatchTimeout, "max time a message waits for its batch")
+  kafkaBuffer       := flag.Int("kafka-buffer", pkafka.DefaultConfig.Buffer, "messages buffered in memory per topic before spilling to the spool")
+  kafkaRetries      := flag.Int("kafka-retries", pkafka.DefaultConfig.MaxRetries, "retries per batch before it is spooled")
+  kafkaSpoolDir     := flag.String("kafka-spool-dir", "kafka-spool", "directory undeliverable messages are spooled to and replayed from (empty disables)")
   flag.Parse()
+  if *heartbeat < time.Second {
+    log.Fatal("--heartbeat-interval must be at least 1s")
+  }
 
   ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
   defer cancel()
 
-  // Build connectors
-  var conns []ingest.Connector
+  // Connectors
+  standbyFor := make(map[string]bool)
+  for _, name := range strings.Split(*standby, ",") {
+    standbyFor[strings.TrimSpace(strings.ToLower(name))] = true
+  }
+  var conns, standbys []ingest.Connector
   for _, name := range strings.Split(*exchanges, ",") {
     name = strings.TrimSpace(strings.ToLower(name))
-    switch name {
-    case "binance":
-      conns = append(conns, ingest.NewBinance())
-    case "tradermade":
-      conns = append(conns, ingest.NewTraderMade())
-    case "twelvedata":
-      conns = append(conns, ingest.NewTwelveData())
-    case "", "none":
-      // skip
-    default:
-      log.Printf("unknown connector: %s (skipped)", name)
-    }
-  }
-  if len(conns) == 0 {
-    log.Fatal("no connectors configured")
+    if name == "" || name == "none" {
+      continue
+    }
+    c, err := engine.NewConnector(name)
+    if err != nil {
+      log.Printf("%v (skipped)", err)
+      continue
+    }
+    conns = append(conns, c)
+    if standbyFor[name] {
+      // hot standby: a second, independent connection; duplicates are removed by the deduper
+      sb, _ := engine.NewConnector(name)
+      standbys = append(standbys, sb)
+    }
   }
 
-  // Start ingestion
-  var tradeChans []<-chan common.Trade
-  var errChans []<-chan error
-  syms := strings.Split(*symbolsCSV, ",")
-  for _, c := range conns {
-    for _, s := range syms {
-      tc, ec := c.Start(ctx, strings.TrimSpace(s))
-      tradeChans = append(tradeChans, tc)
-      errChans = append(errChans, ec)
-    }
-  }
-  // Error logger
-  for _, ec := range errChans {
-    go func(ch <-chan error) {
-      for e := range ch {
-        if e != nil { log.Printf("ingest error: %v", e) }
+  staleCfg := ingest.DefaultStaleConfig
+  staleCfg.Factor, staleCfg.Min, staleCfg.Max, staleCfg.PingInterval = *staleFactor, *staleMin, *staleMax, *wsPing
+  opts := []engine.Option{
+    engine.WithSymbols(strings.Split(*symbolsCSV, ",")...),
+    engine.WithInterval(*interval),
+    engine.WithConnectors(conns...),
+    engine.WithStandby(standbys...),
+    engine.WithStaleConfig(staleCfg),
+    engine.WithDedupWindow(*dedupWindow),
+    engine.WithAddr(*grpcAddr),
+    engine.WithHistory(*historySize),
+    engine.WithServerOptions(grpcapi.WithMaxLag(*readyMaxLag), grpcapi.WithHeartbeat(*heartbeat)),
+  }
+  if *tickFilter {
+    opts = append(opts, engine.WithTickFilter(ingest.FilterConfig{
+      Window:     *filterWindow,
+      MaxZ:       *filterMaxZ,
+      MinBandBps: *filterMinBand,
+      MaxFuture:  *filterFuture,
+    }))
+  }
+  if *syntheticCSV != "" {
+    opts = append(opts, engine.WithSynthetic(*synthLegAge, strings.Split(*syntheticCSV, ",")...))
+  }
+  mode, err := aggregate.ParseMode(*priceMode)
+  if err != nil {
+    log.Fatal(err)
+  }
+  bounds, err := parseFloats(*sizeBuckets)
+  if err == nil {
+    err = aggregate.ValidateSizeBuckets(bounds)
+  }
+  if err != nil {
+    log.Fatalf("size-buckets: %v", err)
+  }
+  opts = append(opts, engine.WithAggregateOptions(aggregate.WithSizeBuckets(bounds)))
+  if mode != aggregate.ModeMixed {
+    opts = append(opts, engine.WithAggregateOptions(aggregate.WithConsensus(mode, *consensusDev, *consensusStale)))
+  }
+  if *calendarFile != "" {
+    calendars, err := calendar.Load(*calendarFile)
+    if err != nil {
+      log.Fatalf("calendar: %v", err)
+    }
+    opts = append(opts, engine.WithCalendars(calendars))
+  }
+  if *quoteSymbols != "" {
+    opts = append(opts, engine.WithQuotes(strings.Split(*quoteSymbols, ",")...))
+  }
+  if *depthSymbols != "" {
+    opts = append(opts, engine.WithDepth(*depthLevels, strings.Split(*depthSymbols, ",")...))
+  }
+  if *profileEnable {
+    ticks, err := parseTicks(*profileTicks)
+    if err != nil {
+      log.Fatalf("profile-ticks: %v", err)
+    }
+    opts = append(opts, engine.WithProfiles(profile.Config{Interval: *profileInterval, TickSizes: ticks, ValueArea: *profileVA}))
+  }
+  schedules, err := fixing.ParseSchedules(*fixingsSpec)
+  if err != nil {
+    log.Fatalf("fixings: %v", err)
+  }
+  if len(schedules) > 0 {
+    opts = append(opts, engine.WithFixings(*fixingGrace, schedules...))
+  }
+  indCfg := indicator.Config{}
+  if indCfg.Default, err = indicator.ParseSpecs(*indicatorsCSV); err != nil {
+    log.Fatalf("indicators: %v", err)
+  }
+  if indCfg.PerSymbol, err = indicator.ParsePerSymbol(*symbolIndicators); err != nil {
+    log.Fatalf("symbol-indicators: %v", err)
+  }
+  opts = append(opts, engine.WithIndicators(indCfg))
+  if *alertsEnable {
+    alerts, err := alert.NewEngine(*alertRules, *alertWebhook)
+    if err != nil {
+      log.Fatalf("alerts: %v", err)
+    }
+    opts = append(opts, engine.WithAlerts(alerts))
+    log.Printf("alerts enabled: %d rules loaded from %s", len(alerts.List("")), *alertRules)
+  }
+  if *meterEnable {
+    meter, err := metering.New(*meterFile, metering.WithRetention(*meterKeep))
+    if err != nil {
+      log.Fatalf("metering: %v", err)
+    }
+    opts = append(opts, engine.WithMeter(meter, *meterFlush))
+  }
+
+  // Listener: connection limits, CORS, auth, TLS
+  serveOpts := []grpcapi.ServeOption{
+    grpcapi.WithKeepalive(*keepaliveTime, *keepaliveTimeout),
+    grpcapi.WithKeepalivePolicy(*keepaliveMinTime, *keepaliveIdle),
+    grpcapi.WithMaxConnectionAge(*maxConnAge, *maxConnAgeGrace),
+    grpcapi.WithSendTimeout(*sendTimeout),
+  }
+  if *corsOrigins != "" {
+    serveOpts = append(serveOpts, grpcapi.WithCORS(strings.Split(*corsOrigins, ",")))
+  }
+  if *authConfig != "" {
+    var audit io.Writer
+    if *auditLog != "" {
+      f, err := os.OpenFile(*auditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+      if err != nil {
+        log.Fatalf("audit log: %v", err)
       }
-    }(ec)
+      defer f.Close()
+      audit = f
+    }
+    authn, err := auth.Load(*authConfig, audit)
+    if err != nil {
+      log.Fatalf("auth: %v", err)
+    }
+    serveOpts = append(serveOpts, grpcapi.WithAuth(authn))
   }
+  if *tlsCert != "" || *tlsKey != "" {
+    // with an auth config, a client certificate is one credential among others
+    mode := auth.RequireClientCert
+    if *authConfig != "" {
+      mode = auth.OptionalClientCert
+    }
+    if *tlsClientCA == "" {
+      mode = auth.NoClientCert
+    }
+    tlsCfg, err := auth.LoadTLS(*tlsCert, *tlsKey, *tlsClientCA, mode)
+    if err != nil {
+      log.Fatal(err)
+    }
+    serveOpts = append(serveOpts, grpcapi.WithTLS(tlsCfg))
+  } else if *tlsClientCA != "" {
+    log.Fatal("--tls-client-ca requires --tls-cert and --tls-key")
+  }
+  opts = append(opts, engine.WithServeOptions(serveOpts...))
 
-  // Merge + aggregate
-  merged := ingest.MergeTrades(ctx, tradeChans...)
-  candles := aggregate.Run(ctx, merged, *interval)
+  // Kafka (optional): async batching publishers, one per topic; the engine
+  // closes them once the pipeline stopped, flushing or spooling their buffers
+  var publishers []*pkafka.Publisher
+  if *kafkaEnable {
+    acks, err := pkafka.ParseAcks(*kafkaAcks)
+    if err != nil {
+      log.Fatalf("kafka-acks: %v", err)
+    }
+    newPublisher := func(topic string) *pkafka.Publisher {
+      pub, err := pkafka.NewPublisher(pkafka.Config{
+        Brokers:      strings.Split(*kafkaBrokers, ","),
+        Topic:        topic,
+        Acks:         acks,
+        BatchSize:    *kafkaBatchSize,
+        BatchTimeout: *kafkaBatchTimeout,
+        Buffer:       *kafkaBuffer,
+        MaxRetries:   *kafkaRetries,
+        SpoolDir:     *kafkaSpoolDir,
+      })
+      if err != nil {
+        log.Fatalf("kafka %s: %v", topic, err)
+      }
+      publishers = append(publishers, pub)
+      return pub
+    }
+    opts = append(opts, engine.WithSink(newPublisher(*kafkaTopic)))
+    log.Printf("Kafka enabled -> topic=%s brokers=%s acks=%s", *kafkaTopic, *kafkaBrokers, *kafkaAcks)
+    if *profileEnable {
+      opts = append(opts, engine.WithProfileSink(newPublisher(*kafkaProfileTopic)))
+    }
+    if *tickFilter {
+      opts = append(opts, engine.WithQuarantineSink(newPublisher(*kafkaQuarantineTopic)))
+    }
+    if len(schedules) > 0 {
+      opts = append(opts, engine.WithFixingSink(newPublisher(*kafkaFixingTopic)))
+    }
+    go func() {
+      t := time.NewTicker(time.Minute)
+      defer t.Stop()
+      for {
+        select {
+        case <-ctx.Done():
+          return
+        case <-t.C:
+          for _, pub := range publishers {
+            log.Printf("kafka: %s", pub.Stats())
+          }
+        }
+      }
+    }()
+  }
 
-  // Hub + gRPC
-  hub := grpcapi.NewHub()
+  eng, err := engine.New(opts...)
+  if err != nil {
+    log.Fatal(err)
+  }
+  if err := eng.Start(ctx); err != nil {
+    log.Fatal(err)
+  }
   go func() {
-    log.Printf("gRPC listening on %s", *grpcAddr)
-    if err := grpcapi.Serve(*grpcAddr, grpcapi.NewServer(hub, *interval)); err != nil {
-      log.Fatalf("grpc serve: %v", err)
+    t := time.NewTicker(time.Minute)
+    defer t.Stop()
+    for {
+      select {
+      case <-eng.Done():
+        return
+      case <-t.C:
+        if st := eng.Stats(); st != (engine.Stats{}) {
+          log.Printf("engine: %s", st)
+        }
+      }
     }
   }()
+  <-eng.Done()
+  if err := eng.Stop(); err != nil {
+    log.Fatal(err)
+  }
+}
 
-  // Kafka (optional)
-  var producer *pkafka.Producer
-  if *kafkaEnable {
-    producer = pkafka.NewProducer(strings.Split(*kafkaBrokers, ","), *kafkaTopic)
-    defer producer.Close()
-    log.Printf("Kafka enabled -> topic=%s brokers=%s", *kafkaTopic, *kafkaBrokers)
+func parseFloats(csv string) ([]float64, error) {
+  var out []float64
+  for _, f := range strings.Split(csv, ",") {
+    f = strings.TrimSpace(f)
+    if f == "" { continue }
+    v, err := strconv.ParseFloat(f, 64)
+    if err != nil { return nil, err }
+    out = append(out, v)
   }
+  return out, nil
+}
 
-  for {
-    select {
-    case <-ctx.Done():
-      return
-    case c, ok := <-candles:
-      if !ok { return }
-      hub.Publish(c)
-      if producer != nil {
-        _ = producer.Publish(ctx, c)
-      }
-    }
+func formatFloats(vs []float64) string {
+  out := make([]string, len(vs))
+  for i, v := range vs {
+    out[i] = strconv.FormatFloat(v, 'f', -1, 64)
+  }
+  return strings.Join(out, ",")
+}
+
+// parseTicks reads "SYM:tick,SYM:tick".
+func parseTicks(csv string) (map[string]float64, error) {
+  out := make(map[string]float64)
+  for _, kv := range strings.Split(csv, ",") {
+    kv = strings.TrimSpace(kv)
+    if kv == "" { continue }
+    sym, tick, ok := strings.Cut(kv, ":")
+    if !ok { return nil, fmt.Errorf("%q: want SYMBOL:tick", kv) }
+    v, err := strconv.ParseFloat(tick, 64)
+    if err != nil { return nil, err }
+    out[strings.ToUpper(strings.TrimSpace(sym))] = v
   }
+  return out, nil
 }
@@ -0,0 +1,135 @@
+// Command usage prints the per-client usage report of an engine started
+// with --metering.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "engine gRPC address")
+	apiKey := flag.String("api-key", "", "API key (x-api-key)")
+	token := flag.String("token", "", "JWT bearer token")
+	useTLS := flag.Bool("tls", false, "connect with TLS")
+	caFile := flag.String("cacert", "", "PEM CA bundle to verify the server with (implies --tls)")
+	certFile := flag.String("cert", "", "PEM client certificate for mTLS (implies --tls)")
+	keyFile := flag.String("key", "", "PEM client private key")
+	client := flag.String("client", "", "only this client (admins only; others always see their own)")
+	symbol := flag.String("symbol", "", "only this symbol")
+	from := flag.String("from", "", "first day, YYYY-MM-DD (UTC)")
+	to := flag.String("to", "", "last day, YYYY-MM-DD (UTC)")
+	format := flag.String("format", "table", "output format: table or csv")
+	timeout := flag.Duration("timeout", 10*time.Second, "request timeout")
+	flag.Parse()
+	if *format != "table" && *format != "csv" {
+		log.Fatalf("unknown format %q", *format)
+	}
+
+	creds := insecure.NewCredentials()
+	if *useTLS || *caFile != "" || *certFile != "" {
+		cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+		if *caFile != "" {
+			pem, err := os.ReadFile(*caFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			cfg.RootCAs = x509.NewCertPool()
+			if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+				log.Fatalf("%s: no certificates found", *caFile)
+			}
+		}
+		if *certFile != "" {
+			cert, err := tls.LoadX509KeyPair(*certFile, *keyFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			cfg.Certificates = []tls.Certificate{cert}
+		}
+		creds = credentials.NewTLS(cfg)
+	}
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	if *apiKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", *apiKey)
+	}
+	if *token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+*token)
+	}
+	resp, err := pricev1.NewPriceStreamClient(conn).GetUsageReport(ctx, &pricev1.UsageReportRequest{
+		Client:  *client,
+		Symbol:  *symbol,
+		FromDay: *from,
+		ToDay:   *to,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	total := &pricev1.UsageRow{Day: "TOTAL"}
+	for _, r := range resp.GetRows() {
+		total.Subscriptions += r.GetSubscriptions()
+		total.Messages += r.GetMessages()
+		total.Bytes += r.GetBytes()
+		total.StreamSeconds += r.GetStreamSeconds()
+	}
+	rows := append(resp.GetRows(), total)
+	header := []string{"DAY", "CLIENT", "SYMBOL", "SUBSCRIPTIONS", "MESSAGES", "BYTES", "STREAM_SECONDS"}
+	record := func(r *pricev1.UsageRow) []string {
+		return []string{
+			r.GetDay(), r.GetClient(), r.GetSymbol(),
+			strconv.FormatUint(r.GetSubscriptions(), 10),
+			strconv.FormatUint(r.GetMessages(), 10),
+			strconv.FormatUint(r.GetBytes(), 10),
+			strconv.FormatFloat(r.GetStreamSeconds(), 'f', 1, 64),
+		}
+	}
+
+	switch *format {
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write(header)
+		for _, r := range rows {
+			w.Write(record(r))
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+		line := func(f []string) {
+			for _, s := range f {
+				fmt.Fprint(w, s, "\t")
+			}
+			fmt.Fprintln(w)
+		}
+		line(header)
+		for _, r := range rows {
+			line(record(r))
+		}
+		w.Flush()
+	}
+}
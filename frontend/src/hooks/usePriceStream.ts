@@ -50,6 +50,11 @@ export function usePriceStream({
         setError(err);
         setIsConnected(false);
         setIsLoading(false);
+      },
+      () => {
+        // the stream starts with the market status, candles or not
+        setIsLoading(false);
+        setIsConnected(true);
       }
     );
 
@@ -3,7 +3,7 @@
  */
 
 import { PriceStreamClient } from '@/types/price_grpc_web_pb';
-import { SubscribeRequest, Candle } from '@/types/price_pb';
+import { SubscribeRequest, Candle, AggregateEvent, StreamStatus } from '@/types/price_pb';
 
 // gRPC-Web client instance
 let client: PriceStreamClient | null = null;
@@ -32,23 +32,29 @@ export function getGrpcClient(): PriceStreamClient {
 }
 
 /**
- * Subscribe to price stream
+ * Subscribe to price stream. Candles go to onData, market and source
+ * status events to onStatus; heartbeats only keep the stream alive.
  */
 export function subscribePriceStream(
   symbol: string,
   intervalMs: number,
   onData: (candle: Candle) => void,
-  onError: (error: Error) => void
+  onError: (error: Error) => void,
+  onStatus?: (status: StreamStatus) => void
 ): () => void {
   const grpcClient = getGrpcClient();
   const request = new SubscribeRequest();
   request.setSymbol(symbol);
   request.setIntervalMs(intervalMs);
 
-  const stream = grpcClient.streamAggregates(request, {});
+  const stream = grpcClient.streamAggregateEvents(request, {});
   
-  stream.on('data', (candle: Candle) => {
-    onData(candle);
+  stream.on('data', (event: AggregateEvent) => {
+    if (event.hasCandle()) {
+      onData(event.getCandle()!);
+    } else if (event.hasStatus() && onStatus) {
+      onStatus(event.getStatus()!);
+    }
   });
   
   stream.on('error', (error: Error) => {
@@ -5,16 +5,23 @@ go 1.24.0
 toolchain go1.24.9
 
 require (
+	connectrpc.com/connect v1.19.1
+	github.com/golang-jwt/jwt/v5 v5.3.1
+	github.com/improbable-eng/grpc-web v0.15.0
 	github.com/segmentio/kafka-go v0.4.49
+	github.com/soheilhy/cmux v0.1.5
+	golang.org/x/net v0.42.0
 	google.golang.org/grpc v1.76.0
 	google.golang.org/protobuf v1.36.10
 	nhooyr.io/websocket v1.8.17
 )
 
 require (
+	github.com/cenkalti/backoff/v4 v4.1.1 // indirect
+	github.com/desertbit/timer v0.0.0-20180107155436-c41aec40b27f // indirect
 	github.com/klauspost/compress v1.15.9 // indirect
 	github.com/pierrec/lz4/v4 v4.1.15 // indirect
-	golang.org/x/net v0.42.0 // indirect
+	github.com/rs/cors v1.7.0 // indirect
 	golang.org/x/sys v0.34.0 // indirect
 	golang.org/x/text v0.27.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250804133106-a7a43d27e69b // indirect
@@ -23,46 +23,73 @@ type window struct {
   count   uint64
   lastTs  int64
   init    bool
+  rev     uint32 // updates emitted so far
+
+  synthetic bool
+  legs      []common.SourceLeg
+
+  venues map[string]*venueWindow // per-venue split, consensus modes only
+  flow   flowWindow
 }
 
 // TradeAlias: keep compile shields when importing in main
 type TradeAlias = common.Trade
 
-func Run(ctx context.Context, trades <-chan common.Trade, interval time.Duration) <-chan *pricev1.Candle {
+func Run(ctx context.Context, trades <-chan common.Trade, interval time.Duration, opts ...Option) <-chan *pricev1.Candle {
+  cfg := defaultConfig()
+  for _, o := range opts { o(&cfg) }
+
   out := make(chan *pricev1.Candle, 2048)
   windows := make(map[string]*window)
+  lastSeen := make(map[string]map[string]venueLast) // symbol -> venue -> last price
+  spreads := make(map[string]*spreadState)
+  cumDelta := make(map[string]float64) // delta of all final windows so far
   var mu sync.Mutex
 
   flush := func(sym string, w *window, final bool) {
     if w == nil || !w.init { return }
+    open, high, low, cls := w.open, w.high, w.low, w.close
+    vol, sumPV, count := w.vol, w.sumPV, w.count
+    var cs consensus
+    if cfg.mode != ModeMixed {
+      cs = computeConsensus(cfg, w, lastSeen[sym])
+      open, high, low, cls, vol, sumPV, count = rebuild(w, cs.contributing, open, high, low, cls, vol, sumPV, count)
+    }
     vwap := 0.0
-    if w.sumV > 0 { vwap = w.sumPV / w.sumV }
-
-    // Infer instrument & ISO ccy split
-    inst := pricev1.InstrumentType_IT_CRYPTO_SPOT
-    base, quote, ok := common.SplitFX(sym)
-    if ok { inst = pricev1.InstrumentType_IT_FX_SPOT }
-
-    c := &pricev1.Candle{
-      Symbol:        sym,
-      WindowStartMs: w.startMs,
-      WindowEndMs:   w.endMs,
-      Open:          w.open,
-      High:          w.high,
-      Low:           w.low,
-      Close:         w.close,
-      Volume:        w.vol,
-      Vwap:          vwap,
-      IsFinal:       final,
-      Exchange:      "agg",
-      LastTradeTs:   w.lastTs,
-      TradeCount:    w.count,
-
-      InstrumentType: inst,
-      PriceType:      pricev1.PriceType_PT_UNSPECIFIED, // trade vs bid/ask/mid if upstream annotates later
-      BaseCcy:        base,
-      QuoteCcy:       quote,
+    if vol > 0 { vwap = sumPV / vol }
+    uptoMs := w.endMs
+    if !final { uptoMs = time.Now().UnixMilli() }
+    spreadAvg, spreadMax, spreadTwa, quoteCount := spreads[sym].stats(w.startMs, uptoMs)
+    cum := cumDelta[sym] + w.flow.delta()
+    if final { cumDelta[sym] = cum }
+    var stale []string
+    if cfg.staleSources != nil {
+      stale = cfg.staleSources(sym)
+      for _, l := range w.legs {
+        stale = append(stale, cfg.staleSources(l.Symbol)...)
+      }
     }
+
+    c := w.candle(sym, final, cfg.sizeBuckets)
+    c.Open, c.High, c.Low, c.Close = open, high, low, cls
+    c.Volume, c.Vwap, c.TradeCount = vol, vwap, count
+    c.CumulativeDelta = cum
+    w.rev++
+    c.Revision = w.rev
+
+    c.ConsensusPrice = cs.price
+    c.ContributingVenues = cs.contributing
+    c.ExcludedVenues = cs.excluded
+    c.Confidence = cs.confidence
+
+    c.Stale = len(stale) > 0
+    c.StaleSources = stale
+
+    c.SpreadAvg = spreadAvg
+    c.SpreadMax = spreadMax
+    c.SpreadTwa = spreadTwa
+    c.QuoteCount = quoteCount
+
     select {
     case out <- c:
     default:
@@ -96,10 +123,25 @@ func Run(ctx context.Context, trades <-chan common.Trade, interval time.Duration
 
   go func() {
     defer close(out)
+    quotes := cfg.quotes
     for {
       select {
       case <-ctx.Done():
         return
+      case q, ok := <-quotes:
+        if !ok {
+          quotes = nil
+          continue
+        }
+        qStart := q.TS.Truncate(interval)
+        mu.Lock()
+        st := spreads[q.Symbol]
+        if st == nil {
+          st = &spreadState{}
+          spreads[q.Symbol] = st
+        }
+        st.add(q.Spread(), q.TS.UnixMilli(), qStart.UnixMilli(), qStart.Add(interval).UnixMilli())
+        mu.Unlock()
       case t, ok := <-trades:
         if !ok { return }
 
@@ -113,20 +155,18 @@ func Run(ctx context.Context, trades <-chan common.Trade, interval time.Duration
           w = &window{startMs: winStart, endMs: winEnd}
           windows[t.Symbol] = w
         }
-        if !w.init {
-          w.open = t.Price
-          w.high = t.Price
-          w.low  = t.Price
-          w.init = true
+        w.add(t, cfg.sizeBuckets)
+        if cfg.mode != ModeMixed {
+          if w.venues == nil { w.venues = make(map[string]*venueWindow) }
+          vw := w.venues[t.Exchange]
+          if vw == nil {
+            vw = &venueWindow{}
+            w.venues[t.Exchange] = vw
+          }
+          vw.add(t.Price, t.Qty, w.lastTs)
+          if lastSeen[t.Symbol] == nil { lastSeen[t.Symbol] = make(map[string]venueLast) }
+          lastSeen[t.Symbol][t.Exchange] = venueLast{price: vw.price(), ts: w.lastTs}
         }
-        if t.Price > w.high { w.high = t.Price }
-        if t.Price < w.low  { w.low  = t.Price }
-        w.close = t.Price
-        w.vol  += t.Qty
-        w.sumPV += t.Price * t.Qty
-        w.sumV  += t.Qty
-        w.count++
-        w.lastTs = t.TS.UnixMilli()
 
         flush(t.Symbol, w, false)
         mu.Unlock()
@@ -136,3 +176,105 @@ func Run(ctx context.Context, trades <-chan common.Trade, interval time.Duration
 
   return out
 }
+
+func (w *window) add(t common.Trade, sizeBuckets []float64) {
+  if !w.init {
+    w.open = t.Price
+    w.high = t.Price
+    w.low  = t.Price
+    w.init = true
+  }
+  if t.Price > w.high { w.high = t.Price }
+  if t.Price < w.low  { w.low  = t.Price }
+  w.close = t.Price
+  w.vol  += t.Qty
+  w.sumPV += t.Price * t.Qty
+  w.sumV  += t.Qty
+  w.count++
+  w.lastTs = t.TS.UnixMilli()
+  w.flow.add(t, sizeBuckets)
+  if t.Synthetic {
+    w.synthetic = true
+    w.legs = t.Legs
+  }
+}
+
+// candle renders the window's own trades; engine-level annotations
+// (consensus, spreads, staleness) are filled in by the caller.
+func (w *window) candle(sym string, final bool, sizeBuckets []float64) *pricev1.Candle {
+  vwap := 0.0
+  if w.sumV > 0 { vwap = w.sumPV / w.sumV }
+
+  // Infer instrument & ISO ccy split
+  inst := pricev1.InstrumentType_IT_CRYPTO_SPOT
+  base, quote, ok := common.SplitFX(sym)
+  if ok { inst = pricev1.InstrumentType_IT_FX_SPOT }
+
+  return &pricev1.Candle{
+    Symbol:        sym,
+    WindowStartMs: w.startMs,
+    WindowEndMs:   w.endMs,
+    Open:          w.open,
+    High:          w.high,
+    Low:           w.low,
+    Close:         w.close,
+    Volume:        w.vol,
+    Vwap:          vwap,
+    IsFinal:       final,
+    Exchange:      "agg",
+    LastTradeTs:   w.lastTs,
+    TradeCount:    w.count,
+
+    InstrumentType: inst,
+    PriceType:      pricev1.PriceType_PT_UNSPECIFIED, // trade vs bid/ask/mid if upstream annotates later
+    BaseCcy:        base,
+    QuoteCcy:       quote,
+
+    Synthetic:  w.synthetic,
+    SourceLegs: sourceLegs(w.legs),
+
+    BuyVolume:       w.flow.buyVol,
+    SellVolume:      w.flow.sellVol,
+    BuyCount:        w.flow.buyCount,
+    SellCount:       w.flow.sellCount,
+    VolumeDelta:     w.flow.delta(),
+    CumulativeDelta: w.flow.delta(),
+    SizeBuckets:     w.flow.buckets(sizeBuckets),
+  }
+}
+
+func sourceLegs(legs []common.SourceLeg) []*pricev1.SourceLeg {
+  if len(legs) == 0 { return nil }
+  out := make([]*pricev1.SourceLeg, len(legs))
+  for i, l := range legs {
+    out[i] = &pricev1.SourceLeg{
+      Symbol:   l.Symbol,
+      Exchange: l.Exchange,
+      Price:    l.Price,
+      Inverted: l.Inverted,
+      Ts:       l.TS.UnixMilli(),
+    }
+  }
+  return out
+}
+
+// rebuild recomputes OHLCV from the contributing venues only, keeping the
+// mixed values when none of them traded inside the window.
+func rebuild(w *window, venues []string, open, high, low, cls, vol, sumPV float64, count uint64) (float64, float64, float64, float64, float64, float64, uint64) {
+  var first, last *venueWindow
+  var h, l, v, pv float64
+  var n uint64
+  for _, name := range venues {
+    vw := w.venues[name]
+    if vw == nil || vw.count == 0 { continue }
+    if first == nil || vw.firstTs < first.firstTs { first = vw }
+    if last == nil || vw.lastTs > last.lastTs { last = vw }
+    if n == 0 || vw.high > h { h = vw.high }
+    if n == 0 || vw.low < l { l = vw.low }
+    v += vw.vol
+    pv += vw.sumPV
+    n += vw.count
+  }
+  if n == 0 { return open, high, low, cls, vol, sumPV, count }
+  return first.open, h, l, last.close, v, pv, n
+}
@@ -0,0 +1,70 @@
+// path: pkg/aggregate/bars.go
+package aggregate
+
+import (
+  "fmt"
+  "math"
+
+  "github.com/binaridigital/price-engine/pkg/common"
+  pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// BarBuilder builds activity-based bars for one symbol: a bar closes on
+// the trade that brings its trade count (tick), traded quantity (volume)
+// or notional (dollar) to the threshold. The closing trade stays in the
+// bar; nothing is split across bars. Not safe for concurrent use.
+type BarBuilder struct {
+  symbol      string
+  typ         pricev1.BarType
+  size        float64
+  sizeBuckets []float64
+
+  cur      *window
+  progress float64
+  cumDelta float64
+}
+
+func NewBarBuilder(symbol string, typ pricev1.BarType, size float64) (*BarBuilder, error) {
+  switch typ {
+  case pricev1.BarType_BAR_TICK, pricev1.BarType_BAR_VOLUME, pricev1.BarType_BAR_DOLLAR:
+  default:
+    return nil, fmt.Errorf("bar type %v is not activity-based", typ)
+  }
+  if !(size > 0) || math.IsInf(size, 1) {
+    return nil, fmt.Errorf("bar size must be a finite number > 0 for %v bars", typ)
+  }
+  return &BarBuilder{symbol: symbol, typ: typ, size: size, sizeBuckets: DefaultSizeBuckets}, nil
+}
+
+// Add folds t into the open bar and returns its updated candle; IsFinal is
+// set when t closed the bar, and the next trade opens a new one.
+func (b *BarBuilder) Add(t common.Trade) *pricev1.Candle {
+  if b.cur == nil {
+    ts := t.TS.UnixMilli()
+    b.cur = &window{startMs: ts, endMs: ts}
+    b.progress = 0
+  }
+  w := b.cur
+  w.add(t, b.sizeBuckets)
+  w.endMs = w.lastTs
+
+  switch b.typ {
+  case pricev1.BarType_BAR_TICK:
+    b.progress++
+  case pricev1.BarType_BAR_VOLUME:
+    b.progress += t.Qty
+  case pricev1.BarType_BAR_DOLLAR:
+    b.progress += t.Price * t.Qty
+  }
+  final := b.progress >= b.size
+
+  c := w.candle(b.symbol, final, b.sizeBuckets)
+  c.BarType = b.typ
+  c.BarSize = b.size
+  c.CumulativeDelta = b.cumDelta + w.flow.delta()
+  if final {
+    b.cumDelta = c.CumulativeDelta
+    b.cur = nil
+  }
+  return c
+}
@@ -0,0 +1,133 @@
+package aggregate
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/common"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+func TestBarBuilder(t *testing.T) {
+	t0 := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	trade := func(sec int, price, qty float64, side common.Side) common.Trade {
+		return common.Trade{Symbol: "BTCUSDT", Exchange: "binance", TS: t0.Add(time.Duration(sec) * time.Second), Price: price, Qty: qty, Side: side}
+	}
+	type bar struct {
+		final         bool
+		startMs       int64
+		open, close   float64
+		volume        float64
+		trades        uint64
+		delta, cumDel float64
+	}
+	ms := func(sec int) int64 { return t0.Add(time.Duration(sec) * time.Second).UnixMilli() }
+
+	tests := []struct {
+		name   string
+		typ    pricev1.BarType
+		size   float64
+		trades []common.Trade
+		want   []bar // the candle returned by each Add
+	}{
+		{
+			name: "tick",
+			typ:  pricev1.BarType_BAR_TICK,
+			size: 3,
+			trades: []common.Trade{
+				trade(0, 100, 1, common.SideBuy), trade(1, 101, 1, common.SideBuy), trade(2, 102, 1, common.SideSell),
+				trade(3, 103, 1, common.SideSell),
+			},
+			want: []bar{
+				{false, ms(0), 100, 100, 1, 1, 1, 1},
+				{false, ms(0), 100, 101, 2, 2, 2, 2},
+				{true, ms(0), 100, 102, 3, 3, 1, 1},
+				{false, ms(3), 103, 103, 1, 1, -1, 0},
+			},
+		},
+		{
+			// the closing trade brings 12 against a threshold of 10 and
+			// stays whole in the bar
+			name: "volume, closing trade overshoots",
+			typ:  pricev1.BarType_BAR_VOLUME,
+			size: 10,
+			trades: []common.Trade{
+				trade(0, 100, 4, common.SideBuy), trade(1, 101, 5, common.SideSell), trade(2, 102, 3, common.SideBuy),
+				trade(3, 103, 2, common.SideBuy),
+			},
+			want: []bar{
+				{false, ms(0), 100, 100, 4, 1, 4, 4},
+				{false, ms(0), 100, 101, 9, 2, -1, -1},
+				{true, ms(0), 100, 102, 12, 3, 2, 2},
+				{false, ms(3), 103, 103, 2, 1, 2, 4},
+			},
+		},
+		{
+			name: "volume, exactly at the threshold",
+			typ:  pricev1.BarType_BAR_VOLUME,
+			size: 10,
+			trades: []common.Trade{
+				trade(0, 100, 6, common.SideBuy), trade(1, 100, 4, common.SideBuy), trade(2, 100, 10, common.SideSell),
+			},
+			want: []bar{
+				{false, ms(0), 100, 100, 6, 1, 6, 6},
+				{true, ms(0), 100, 100, 10, 2, 10, 10},
+				{true, ms(2), 100, 100, 10, 1, -10, 0},
+			},
+		},
+		{
+			// notional 400, 950, then 1070 against 1000
+			name: "dollar, closing trade overshoots",
+			typ:  pricev1.BarType_BAR_DOLLAR,
+			size: 1000,
+			trades: []common.Trade{
+				trade(0, 100, 4, common.SideUnknown), trade(1, 110, 5, common.SideUnknown), trade(2, 120, 1, common.SideUnknown),
+				trade(3, 100, 1, common.SideUnknown),
+			},
+			want: []bar{
+				{false, ms(0), 100, 100, 4, 1, 0, 0},
+				{false, ms(0), 100, 110, 9, 2, 0, 0},
+				{true, ms(0), 100, 120, 10, 3, 0, 0},
+				{false, ms(3), 100, 100, 1, 1, 0, 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewBarBuilder("BTCUSDT", tt.typ, tt.size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, tr := range tt.trades {
+				c := b.Add(tr)
+				w := tt.want[i]
+				got := bar{c.GetIsFinal(), c.GetWindowStartMs(), c.GetOpen(), c.GetClose(), c.GetVolume(), c.GetTradeCount(), c.GetVolumeDelta(), c.GetCumulativeDelta()}
+				if got != w {
+					t.Errorf("trade %d: %+v, want %+v", i, got, w)
+				}
+				if c.GetWindowEndMs() != tr.TS.UnixMilli() || c.GetBarType() != tt.typ || c.GetBarSize() != tt.size {
+					t.Errorf("trade %d: window end %d, bar %v %v", i, c.GetWindowEndMs(), c.GetBarType(), c.GetBarSize())
+				}
+			}
+		})
+	}
+}
+
+func TestNewBarBuilder(t *testing.T) {
+	tests := []struct {
+		typ  pricev1.BarType
+		size float64
+	}{
+		{pricev1.BarType_BAR_TIME, 10},
+		{pricev1.BarType_BAR_TICK, 0},
+		{pricev1.BarType_BAR_VOLUME, -1},
+		{pricev1.BarType_BAR_DOLLAR, math.NaN()},
+		{pricev1.BarType_BAR_DOLLAR, math.Inf(1)},
+	}
+	for _, tt := range tests {
+		if _, err := NewBarBuilder("BTCUSDT", tt.typ, tt.size); err == nil {
+			t.Errorf("NewBarBuilder(%v, %v) accepted", tt.typ, tt.size)
+		}
+	}
+}
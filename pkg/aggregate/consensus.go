@@ -0,0 +1,157 @@
+// path: pkg/aggregate/consensus.go
+package aggregate
+
+import (
+  "fmt"
+  "math"
+  "sort"
+  "strings"
+  "time"
+)
+
+// Mode selects how trades from several venues become one candle.
+type Mode int
+
+const (
+  ModeMixed  Mode = iota // all trades mixed into one window (no venue checks)
+  ModeMedian             // consensus = median of contributing venue prices
+  ModeVWAP               // consensus = volume-weighted venue prices
+)
+
+const (
+  DefaultMaxDeviationBps = 50.0
+  DefaultStaleAfter      = 10 * time.Second
+)
+
+func ParseMode(s string) (Mode, error) {
+  switch strings.ToLower(strings.TrimSpace(s)) {
+  case "", "mixed":
+    return ModeMixed, nil
+  case "median":
+    return ModeMedian, nil
+  case "vwap":
+    return ModeVWAP, nil
+  }
+  return ModeMixed, fmt.Errorf("unknown price mode %q (mixed|median|vwap)", s)
+}
+
+// venueWindow is one venue's share of a symbol window.
+type venueWindow struct {
+  open, high, low, close float64
+  vol, sumPV             float64
+  count                  uint64
+  firstTs, lastTs        int64
+}
+
+func (v *venueWindow) add(price, qty float64, ts int64) {
+  if v.count == 0 {
+    v.open, v.high, v.low = price, price, price
+    v.firstTs = ts
+  }
+  if price > v.high { v.high = price }
+  if price < v.low  { v.low  = price }
+  v.close = price
+  v.vol += qty
+  v.sumPV += price * qty
+  v.count++
+  v.lastTs = ts
+}
+
+func (v *venueWindow) price() float64 {
+  if v.vol > 0 { return v.sumPV / v.vol }
+  return v.close
+}
+
+// venueLast is a venue's last known price, carried across windows so a
+// slower venue still votes until it goes stale.
+type venueLast struct {
+  price float64
+  ts    int64
+}
+
+type consensus struct {
+  price        float64
+  contributing []string
+  excluded     []string
+  confidence   float64
+}
+
+type vote struct {
+  venue  string
+  price  float64
+  weight float64
+}
+
+// computeConsensus votes over the venues that traded in w plus carried
+// prices no older than cfg.staleAfter relative to the window's last trade.
+func computeConsensus(cfg config, w *window, last map[string]venueLast) consensus {
+  var res consensus
+  var votes []vote
+  for venue, lv := range last {
+    if vw, ok := w.venues[venue]; ok && vw.count > 0 {
+      votes = append(votes, vote{venue, vw.price(), vw.vol})
+      continue
+    }
+    if w.lastTs-lv.ts > cfg.staleAfter.Milliseconds() {
+      res.excluded = append(res.excluded, venue)
+      continue
+    }
+    votes = append(votes, vote{venue, lv.price, 0})
+  }
+  total := len(votes) + len(res.excluded)
+  if len(votes) == 0 {
+    sort.Strings(res.excluded)
+    return res
+  }
+
+  med := median(votes)
+  stale := len(res.excluded)
+  kept := votes[:0:0]
+  for _, v := range votes {
+    if med > 0 && math.Abs(v.price-med)/med*1e4 > cfg.maxDevBps {
+      res.excluded = append(res.excluded, v.venue)
+      continue
+    }
+    kept = append(kept, v)
+  }
+  if len(kept) == 0 {
+    // no majority: everything deviates from the median; fall back to it
+    kept = votes
+    res.excluded = res.excluded[:stale]
+  }
+
+  res.price = median(kept)
+  if cfg.mode == ModeVWAP {
+    var sumPW, sumW float64
+    for _, v := range kept {
+      sumPW += v.price * v.weight
+      sumW += v.weight
+    }
+    if sumW > 0 { res.price = sumPW / sumW }
+  }
+
+  // confidence = coverage (share of known venues contributing) scaled by
+  // agreement (mean deviation of contributors relative to the threshold)
+  var dev float64
+  for _, v := range kept {
+    res.contributing = append(res.contributing, v.venue)
+    if res.price > 0 { dev += math.Abs(v.price-res.price) / res.price * 1e4 }
+  }
+  dev /= float64(len(kept))
+  coverage := float64(len(kept)) / float64(total)
+  agreement := 1 - math.Min(1, dev/cfg.maxDevBps)
+  res.confidence = coverage * agreement
+
+  sort.Strings(res.contributing)
+  sort.Strings(res.excluded)
+  return res
+}
+
+func median(votes []vote) float64 {
+  ps := make([]float64, len(votes))
+  for i, v := range votes { ps[i] = v.price }
+  sort.Float64s(ps)
+  n := len(ps)
+  if n%2 == 1 { return ps[n/2] }
+  return (ps[n/2-1] + ps[n/2]) / 2
+}
@@ -0,0 +1,118 @@
+package aggregate
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestComputeConsensus(t *testing.T) {
+	type trade struct {
+		venue      string
+		price, qty float64
+	}
+	tests := []struct {
+		name         string
+		mode         Mode
+		trades       []trade
+		carried      map[string]venueLast // venues that did not trade in the window
+		price        float64
+		contributing []string
+		excluded     []string
+		confidence   float64
+	}{
+		{
+			name:         "median of agreeing venues",
+			mode:         ModeMedian,
+			trades:       []trade{{"a", 100, 1}, {"b", 100.1, 1}, {"c", 100.2, 1}},
+			price:        100.1,
+			contributing: []string{"a", "b", "c"},
+			confidence:   1 - (0.1/100.1*1e4*2/3)/50,
+		},
+		{
+			name:         "outlier excluded",
+			mode:         ModeMedian,
+			trades:       []trade{{"a", 100, 1}, {"b", 100, 1}, {"c", 110, 1}},
+			price:        100,
+			contributing: []string{"a", "b"},
+			excluded:     []string{"c"},
+			confidence:   2.0 / 3,
+		},
+		{
+			name:         "vwap weights by volume",
+			mode:         ModeVWAP,
+			trades:       []trade{{"a", 100, 3}, {"b", 100.2, 1}},
+			price:        100.05,
+			contributing: []string{"a", "b"},
+			confidence:   1 - ((0.05+0.15)/100.05*1e4/2)/50,
+		},
+		{
+			name:         "fresh carried price votes",
+			mode:         ModeMedian,
+			trades:       []trade{{"a", 100, 1}},
+			carried:      map[string]venueLast{"b": {price: 100, ts: 9_000}},
+			price:        100,
+			contributing: []string{"a", "b"},
+			confidence:   1,
+		},
+		{
+			name:         "stale carried price excluded",
+			mode:         ModeMedian,
+			trades:       []trade{{"a", 100, 1}},
+			carried:      map[string]venueLast{"b": {price: 100, ts: 1_000}},
+			price:        100,
+			contributing: []string{"a"},
+			excluded:     []string{"b"},
+			confidence:   0.5,
+		},
+		{
+			name:         "no majority falls back to all venues",
+			mode:         ModeMedian,
+			trades:       []trade{{"a", 100, 1}, {"b", 120, 1}},
+			price:        110,
+			contributing: []string{"a", "b"},
+			confidence:   0,
+		},
+		{
+			name:     "all stale",
+			mode:     ModeMedian,
+			carried:  map[string]venueLast{"a": {price: 100, ts: 0}},
+			excluded: []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.mode, cfg.maxDevBps, cfg.staleAfter = tt.mode, 50, 5*time.Second
+			w := &window{lastTs: 10_000, venues: make(map[string]*venueWindow)}
+			last := make(map[string]venueLast)
+			for _, tr := range tt.trades {
+				vw := w.venues[tr.venue]
+				if vw == nil {
+					vw = &venueWindow{}
+					w.venues[tr.venue] = vw
+				}
+				vw.add(tr.price, tr.qty, w.lastTs)
+				last[tr.venue] = venueLast{price: tr.price, ts: w.lastTs}
+			}
+			for v, lv := range tt.carried {
+				last[v] = lv
+			}
+
+			got := computeConsensus(cfg, w, last)
+			if math.Abs(got.price-tt.price) > 1e-9 {
+				t.Errorf("price = %v, want %v", got.price, tt.price)
+			}
+			if !slices.Equal(got.contributing, tt.contributing) {
+				t.Errorf("contributing = %v, want %v", got.contributing, tt.contributing)
+			}
+			if !slices.Equal(got.excluded, tt.excluded) {
+				t.Errorf("excluded = %v, want %v", got.excluded, tt.excluded)
+			}
+			if math.Abs(got.confidence-tt.confidence) > 1e-9 {
+				t.Errorf("confidence = %v, want %v", got.confidence, tt.confidence)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// path: pkg/aggregate/flow.go
+package aggregate
+
+import (
+  "math"
+
+  "github.com/binaridigital/price-engine/pkg/common"
+  pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// DefaultSizeBuckets are trade notional (price x qty) upper bounds for the
+// trade size distribution; a final open-ended bucket catches the rest.
+var DefaultSizeBuckets = []float64{1_000, 10_000, 100_000, 1_000_000}
+
+// flowWindow is the order flow of one window split by aggressor side.
+type flowWindow struct {
+  buyVol, sellVol       float64
+  buyCount, sellCount   uint64
+  bucketCount           []uint64
+  bucketVol             []float64
+}
+
+func (f *flowWindow) add(t common.Trade, bounds []float64) {
+  switch t.Side {
+  case common.SideBuy:
+    f.buyVol += t.Qty
+    f.buyCount++
+  case common.SideSell:
+    f.sellVol += t.Qty
+    f.sellCount++
+  }
+  if f.bucketCount == nil {
+    f.bucketCount = make([]uint64, len(bounds)+1)
+    f.bucketVol = make([]float64, len(bounds)+1)
+  }
+  notional := t.Price * t.Qty
+  i := 0
+  for i < len(bounds) && notional > bounds[i] { i++ }
+  f.bucketCount[i]++
+  f.bucketVol[i] += t.Qty
+}
+
+func (f *flowWindow) delta() float64 { return f.buyVol - f.sellVol }
+
+func (f *flowWindow) buckets(bounds []float64) []*pricev1.TradeSizeBucket {
+  if f.bucketCount == nil { return nil }
+  out := make([]*pricev1.TradeSizeBucket, len(f.bucketCount))
+  for i := range f.bucketCount {
+    ub := math.Inf(1)
+    if i < len(bounds) { ub = bounds[i] }
+    out[i] = &pricev1.TradeSizeBucket{UpperNotional: ub, Count: f.bucketCount[i], Volume: f.bucketVol[i]}
+  }
+  return out
+}
@@ -0,0 +1,93 @@
+// path: pkg/aggregate/options.go
+package aggregate
+
+import (
+  "fmt"
+  "time"
+
+  "github.com/binaridigital/price-engine/pkg/common"
+)
+
+// Option configures Run.
+type Option func(*config)
+
+type config struct {
+  mode         Mode
+  maxDevBps    float64
+  staleAfter   time.Duration
+  staleSources func(symbol string) []string
+  quotes       <-chan common.Quote
+  sizeBuckets  []float64
+  err          error // first invalid option
+}
+
+func defaultConfig() config {
+  return config{
+    mode:        ModeMixed,
+    maxDevBps:   DefaultMaxDeviationBps,
+    staleAfter:  DefaultStaleAfter,
+    sizeBuckets: DefaultSizeBuckets,
+  }
+}
+
+// WithConsensus switches Run to a composite price across venues: each
+// venue's window price is compared to the cross-venue median, venues
+// deviating by more than maxDeviationBps or silent for longer than
+// staleAfter are excluded, and OHLCV is rebuilt from the rest.
+func WithConsensus(mode Mode, maxDeviationBps float64, staleAfter time.Duration) Option {
+  return func(c *config) {
+    c.mode = mode
+    if maxDeviationBps > 0 { c.maxDevBps = maxDeviationBps }
+    if staleAfter > 0 { c.staleAfter = staleAfter }
+  }
+}
+
+// WithStaleCheck marks candles as stale while fn reports stale sources for
+// the symbol (or, for synthetic crosses, for any of its legs).
+func WithStaleCheck(fn func(symbol string) []string) Option {
+  return func(c *config) { c.staleSources = fn }
+}
+
+// WithQuotes adds per-window bid/ask spread statistics (average, maximum
+// and time-weighted) from top-of-book quotes to the candles.
+func WithQuotes(quotes <-chan common.Quote) Option {
+  return func(c *config) { c.quotes = quotes }
+}
+
+// WithSizeBuckets sets the ascending notional upper bounds of the trade size
+// distribution (DefaultSizeBuckets otherwise). Bounds that
+// ValidateSizeBuckets rejects are ignored by Run and reported by
+// ValidateOptions.
+func WithSizeBuckets(bounds []float64) Option {
+  err := ValidateSizeBuckets(bounds)
+  return func(c *config) {
+    switch {
+    case err != nil:
+      if c.err == nil { c.err = err }
+    case len(bounds) > 0:
+      c.sizeBuckets = bounds
+    }
+  }
+}
+
+// ValidateOptions returns the first error among opts, so callers can
+// refuse a configuration before passing it to Run.
+func ValidateOptions(opts ...Option) error {
+  cfg := defaultConfig()
+  for _, o := range opts { o(&cfg) }
+  return cfg.err
+}
+
+// ValidateSizeBuckets checks that bounds are positive and strictly
+// ascending.
+func ValidateSizeBuckets(bounds []float64) error {
+  for i, b := range bounds {
+    if !(b > 0) {
+      return fmt.Errorf("aggregate: size bucket bound %v is not positive", b)
+    }
+    if i > 0 && b <= bounds[i-1] {
+      return fmt.Errorf("aggregate: size bucket bounds not strictly ascending: %v after %v", b, bounds[i-1])
+    }
+  }
+  return nil
+}
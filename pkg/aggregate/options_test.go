@@ -0,0 +1,42 @@
+package aggregate
+
+import (
+	"math"
+	"testing"
+)
+
+func TestValidateSizeBuckets(t *testing.T) {
+	tests := []struct {
+		bounds []float64
+		ok     bool
+	}{
+		{nil, true},
+		{DefaultSizeBuckets, true},
+		{[]float64{5}, true},
+		{[]float64{0, 10}, false},
+		{[]float64{-1}, false},
+		{[]float64{math.NaN()}, false},
+		{[]float64{10, 10}, false},
+		{[]float64{100, 10}, false},
+	}
+	for _, tt := range tests {
+		if err := ValidateSizeBuckets(tt.bounds); (err == nil) != tt.ok {
+			t.Errorf("ValidateSizeBuckets(%v) = %v, want ok %v", tt.bounds, err, tt.ok)
+		}
+	}
+}
+
+func TestValidateOptions(t *testing.T) {
+	if err := ValidateOptions(WithSizeBuckets([]float64{100, 1000}), WithSizeBuckets(nil)); err != nil {
+		t.Errorf("valid buckets: %v", err)
+	}
+	if err := ValidateOptions(WithSizeBuckets([]float64{1000, 100}), WithSizeBuckets([]float64{100})); err == nil {
+		t.Error("descending buckets accepted")
+	}
+	// Run keeps the defaults for invalid bounds
+	cfg := defaultConfig()
+	WithSizeBuckets([]float64{-1})(&cfg)
+	if len(cfg.sizeBuckets) != len(DefaultSizeBuckets) {
+		t.Errorf("size buckets %v after invalid bounds", cfg.sizeBuckets)
+	}
+}
@@ -0,0 +1,81 @@
+// path: pkg/aggregate/spread.go
+package aggregate
+
+// spreadWindow accumulates bid/ask spread statistics for one symbol window.
+type spreadWindow struct {
+  startMs, endMs int64
+  n              uint64
+  sum, max       float64
+
+  // time weighting: the current spread holds from lastMs until the next quote
+  last    float64
+  has     bool
+  lastMs  int64
+  twStart int64 // first instant a spread was known in this window
+  twSum   float64
+}
+
+// spreadState keeps the current and previous window per symbol so a final
+// candle flushed just after the boundary still finds its statistics.
+type spreadState struct {
+  cur, prev *spreadWindow
+}
+
+func (st *spreadState) add(spread float64, tsMs, startMs, endMs int64) {
+  w := st.cur
+  if w != nil && startMs < w.startMs { return } // late quote for a closed window
+  if w == nil || w.startMs != startMs {
+    next := &spreadWindow{startMs: startMs, endMs: endMs}
+    if w != nil && w.has && w.startMs < startMs {
+      // close the old window at its end and carry the spread over
+      w.twSum += w.last * float64(w.endMs-w.lastMs)
+      w.lastMs = w.endMs
+      next.last, next.has = w.last, true
+      next.lastMs, next.twStart = startMs, startMs
+    }
+    st.prev, st.cur = w, next
+    w = next
+  }
+  if w.has {
+    w.twSum += w.last * float64(tsMs-w.lastMs)
+  } else {
+    w.twStart = tsMs
+  }
+  w.n++
+  w.sum += spread
+  if w.n == 1 || spread > w.max { w.max = spread }
+  w.last, w.has, w.lastMs = spread, true, tsMs
+}
+
+// stats returns avg, max and time-weighted spread for the window starting
+// at startMs, weighting the current spread up to uptoMs. A window without
+// quotes of its own reports the spread carried over from before as all
+// three, with n = 0.
+func (st *spreadState) stats(startMs, uptoMs int64) (avg, max, twa float64, n uint64) {
+  var w *spreadWindow
+  switch {
+  case st == nil:
+    return
+  case st.cur != nil && st.cur.startMs == startMs:
+    w = st.cur
+  case st.prev != nil && st.prev.startMs == startMs:
+    w = st.prev
+  case st.cur != nil && st.cur.has && st.cur.startMs < startMs:
+    // no quote since the window opened: the last spread still holds
+    return st.cur.last, st.cur.last, st.cur.last, 0
+  default:
+    return
+  }
+  if !w.has { return }
+  if uptoMs > w.endMs { uptoMs = w.endMs }
+  if uptoMs < w.lastMs { uptoMs = w.lastMs }
+  avg, max = w.last, w.last
+  if w.n > 0 { avg, max = w.sum / float64(w.n), w.max }
+  tw := w.twSum + w.last*float64(uptoMs-w.lastMs)
+  if span := uptoMs - w.twStart; span > 0 {
+    twa = tw / float64(span)
+  } else {
+    twa = w.last
+  }
+  return avg, max, twa, w.n
+}
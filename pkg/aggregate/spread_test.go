@@ -0,0 +1,55 @@
+package aggregate
+
+import "testing"
+
+func TestSpreadStats(t *testing.T) {
+	type stats struct {
+		avg, max, twa float64
+		n             uint64
+	}
+	get := func(st *spreadState, startMs, uptoMs int64) stats {
+		avg, max, twa, n := st.stats(startMs, uptoMs)
+		return stats{avg, max, twa, n}
+	}
+
+	var st spreadState
+	if got := get(&st, 0, 1000); got != (stats{}) {
+		t.Errorf("no quotes: %+v", got)
+	}
+	if got := get(nil, 0, 1000); got != (stats{}) {
+		t.Errorf("nil state: %+v", got)
+	}
+
+	// window [0, 1000): 1 from 200, 3 from 600
+	st.add(1, 200, 0, 1000)
+	st.add(3, 600, 0, 1000)
+	if got, want := get(&st, 0, 800), (stats{2, 3, 5.0 / 3, 2}); got != want {
+		t.Errorf("open window: %+v, want %+v", got, want)
+	}
+	if got, want := get(&st, 0, 1000), (stats{2, 3, 2, 2}); got != want {
+		t.Errorf("closed window: %+v, want %+v", got, want)
+	}
+
+	// [1000, 2000) has no quote yet: the spread of 3 carries over
+	if got, want := get(&st, 1000, 2000), (stats{3, 3, 3, 0}); got != want {
+		t.Errorf("carried over: %+v, want %+v", got, want)
+	}
+	if got, want := get(&st, 5000, 6000), (stats{3, 3, 3, 0}); got != want {
+		t.Errorf("carried over several windows: %+v, want %+v", got, want)
+	}
+
+	// a quote of 5 at 1500: 3 held for the first half
+	st.add(5, 1500, 1000, 2000)
+	if got, want := get(&st, 1000, 2000), (stats{5, 5, 4, 1}); got != want {
+		t.Errorf("after a quote: %+v, want %+v", got, want)
+	}
+	// the previous window is still there for a late final flush
+	if got, want := get(&st, 0, 1000), (stats{2, 3, 2, 2}); got != want {
+		t.Errorf("previous window: %+v, want %+v", got, want)
+	}
+	// late quotes for closed windows are ignored
+	st.add(100, 900, 0, 1000)
+	if got, want := get(&st, 0, 1000), (stats{2, 3, 2, 2}); got != want {
+		t.Errorf("after a late quote: %+v, want %+v", got, want)
+	}
+}
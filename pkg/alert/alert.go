@@ -0,0 +1,454 @@
+// path: pkg/alert/alert.go
+package alert
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// MaxWindows bounds the look-back of move and volume-spike rules.
+const MaxWindows = 1000
+
+// webhookQueue bounds the alerts waiting for the webhook; more are dropped.
+const webhookQueue = 64
+
+type rule struct {
+	r      *pricev1.AlertRule
+	active bool // condition held at the last evaluation; alerts fire on the rising edge
+}
+
+type history struct {
+	closes, volumes []float64 // final candles, oldest first, at most MaxWindows+1
+	lastSeen        time.Time
+}
+
+// Engine evaluates alert rules on final candles and fans alerts out to
+// subscribers and, for rules that ask for it, a webhook.
+type Engine struct {
+	path    string
+	webhook string
+	httpc   *http.Client
+
+	mu    sync.Mutex
+	rules map[string]*rule
+	hist  map[string]*history
+	subs  map[chan *pricev1.Alert]filter
+	posts chan *pricev1.Alert // to the webhook worker while Run is running
+}
+
+// filter selects the alerts a subscriber gets; empty fields match all.
+type filter struct {
+	symbol, owner string
+}
+
+// NewEngine loads persisted rules from path (created on the first change).
+// webhookURL, when set, must point at a loopback or private address.
+func NewEngine(path, webhookURL string) (*Engine, error) {
+	if webhookURL != "" {
+		if err := checkLocal(webhookURL); err != nil {
+			return nil, err
+		}
+	}
+	e := &Engine{
+		path:    path,
+		webhook: webhookURL,
+		httpc:   &http.Client{Timeout: 5 * time.Second},
+		rules:   make(map[string]*rule),
+		hist:    make(map[string]*history),
+		subs:    make(map[chan *pricev1.Alert]filter),
+	}
+	rules, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rules {
+		e.rules[r.GetId()] = &rule{r: r}
+	}
+	return e, nil
+}
+
+func checkLocal(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("alert webhook %q: want an http(s) URL", raw)
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return nil
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !(ip.IsLoopback() || ip.IsPrivate()) {
+		return fmt.Errorf("alert webhook %q: host must be localhost or a loopback/private IP", raw)
+	}
+	return nil
+}
+
+func validate(r *pricev1.AlertRule) error {
+	if r.GetSymbol() == "" {
+		return errors.New("symbol required")
+	}
+	switch r.GetKind() {
+	case pricev1.AlertKind_AK_PRICE_CROSS:
+		if r.GetLevel() <= 0 {
+			return errors.New("price cross needs level > 0")
+		}
+	case pricev1.AlertKind_AK_PCT_MOVE, pricev1.AlertKind_AK_VOLUME_SPIKE:
+		if r.GetThreshold() <= 0 {
+			return errors.New("threshold must be > 0")
+		}
+		if r.GetWindows() <= 0 || r.GetWindows() > MaxWindows {
+			return fmt.Errorf("windows must be in 1..%d", MaxWindows)
+		}
+	case pricev1.AlertKind_AK_SPREAD_ABOVE:
+		if r.GetThreshold() <= 0 {
+			return errors.New("threshold must be > 0")
+		}
+	case pricev1.AlertKind_AK_FEED_STALE:
+		if r.GetStaleAfterMs() < 0 {
+			return errors.New("stale_after_ms must be >= 0")
+		}
+	default:
+		return errors.New("unknown alert kind")
+	}
+	return nil
+}
+
+// Add validates r, assigns its id and persists it.
+func (e *Engine) Add(r *pricev1.AlertRule) (*pricev1.AlertRule, error) {
+	r = proto.Clone(r).(*pricev1.AlertRule)
+	r.Symbol = strings.ToUpper(r.GetSymbol())
+	if err := validate(r); err != nil {
+		return nil, err
+	}
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	r.Id = hex.EncodeToString(id[:])
+	r.CreatedTs = time.Now().UnixMilli()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules[r.Id] = &rule{r: r}
+	if err := e.persist(); err != nil {
+		delete(e.rules, r.Id)
+		return nil, err
+	}
+	return proto.Clone(r).(*pricev1.AlertRule), nil
+}
+
+// Remove deletes the rule and reports whether it existed.
+func (e *Engine) Remove(id string) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	old, ok := e.rules[id]
+	if !ok {
+		return false, nil
+	}
+	delete(e.rules, id)
+	if err := e.persist(); err != nil {
+		e.rules[id] = old
+		return false, err
+	}
+	return true, nil
+}
+
+// Get returns the rule with the given id.
+func (e *Engine) Get(id string) (*pricev1.AlertRule, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	r, ok := e.rules[id]
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(r.r).(*pricev1.AlertRule), true
+}
+
+// List returns the rules for symbol ("" = all), oldest first.
+func (e *Engine) List(symbol string) []*pricev1.AlertRule {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.list(strings.ToUpper(symbol))
+}
+
+func (e *Engine) list(symbol string) []*pricev1.AlertRule {
+	out := make([]*pricev1.AlertRule, 0, len(e.rules))
+	for _, r := range e.rules {
+		if symbol == "" || r.r.GetSymbol() == symbol {
+			out = append(out, proto.Clone(r.r).(*pricev1.AlertRule))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].GetCreatedTs() != out[j].GetCreatedTs() {
+			return out[i].GetCreatedTs() < out[j].GetCreatedTs()
+		}
+		return out[i].GetId() < out[j].GetId()
+	})
+	return out
+}
+
+// persist must be called with e.mu held.
+func (e *Engine) persist() error {
+	return save(e.path, e.list(""))
+}
+
+// Subscribe returns alerts for symbol ("" = all symbols) raised by the
+// rules of owner ("" = all owners).
+func (e *Engine) Subscribe(symbol, owner string) (<-chan *pricev1.Alert, func()) {
+	ch := make(chan *pricev1.Alert, 64)
+	e.mu.Lock()
+	e.subs[ch] = filter{symbol: strings.ToUpper(symbol), owner: owner}
+	e.mu.Unlock()
+	return ch, func() {
+		e.mu.Lock()
+		if _, ok := e.subs[ch]; ok {
+			delete(e.subs, ch)
+			close(ch)
+		}
+		e.mu.Unlock()
+	}
+}
+
+// Run evaluates rules on every final candle and checks feed-stale rules
+// once a second until ctx is done or candles is closed. It posts webhooks
+// from a single worker and returns after the worker did.
+func (e *Engine) Run(ctx context.Context, candles <-chan *pricev1.Candle) {
+	posts := make(chan *pricev1.Alert, webhookQueue)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		e.postAll(ctx, posts)
+	}()
+	e.mu.Lock()
+	e.posts = posts
+	e.mu.Unlock()
+	defer func() {
+		e.mu.Lock()
+		e.posts = nil
+		e.mu.Unlock()
+		close(posts)
+		wg.Wait()
+	}()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	start := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case c, ok := <-candles:
+			if !ok {
+				return
+			}
+			if c.GetIsFinal() {
+				e.onCandle(c)
+			}
+		case now := <-ticker.C:
+			e.onTick(now, start)
+		}
+	}
+}
+
+func (e *Engine) onCandle(c *pricev1.Candle) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	sym := c.GetSymbol()
+	h := e.hist[sym]
+	if h == nil {
+		h = &history{}
+		e.hist[sym] = h
+	}
+	h.lastSeen = time.Now()
+
+	for _, r := range e.rules {
+		if r.r.GetSymbol() != sym {
+			continue
+		}
+		hit, value, msg := evaluate(r.r, c, h)
+		if r.r.GetKind() == pricev1.AlertKind_AK_PRICE_CROSS {
+			// a cross is itself an edge
+			if hit {
+				e.fire(r.r, value, msg, c.GetWindowEndMs())
+			}
+			continue
+		}
+		if hit && !r.active {
+			e.fire(r.r, value, msg, c.GetWindowEndMs())
+		}
+		r.active = hit
+	}
+
+	h.closes = append(h.closes, c.GetClose())
+	h.volumes = append(h.volumes, c.GetVolume())
+	if len(h.closes) > MaxWindows+1 {
+		h.closes = h.closes[1:]
+		h.volumes = h.volumes[1:]
+	}
+}
+
+// evaluate checks r against the final candle c; h holds earlier windows.
+func evaluate(r *pricev1.AlertRule, c *pricev1.Candle, h *history) (bool, float64, string) {
+	cl := c.GetClose()
+	switch r.GetKind() {
+	case pricev1.AlertKind_AK_PRICE_CROSS:
+		if len(h.closes) == 0 {
+			return false, 0, ""
+		}
+		prev, lvl := h.closes[len(h.closes)-1], r.GetLevel()
+		if prev < lvl && cl >= lvl {
+			return true, cl, fmt.Sprintf("%s crossed above %g (close %g)", r.GetSymbol(), lvl, cl)
+		}
+		if prev > lvl && cl <= lvl {
+			return true, cl, fmt.Sprintf("%s crossed below %g (close %g)", r.GetSymbol(), lvl, cl)
+		}
+	case pricev1.AlertKind_AK_PCT_MOVE:
+		n := int(r.GetWindows())
+		if len(h.closes) < n {
+			return false, 0, ""
+		}
+		base := h.closes[len(h.closes)-n]
+		if base <= 0 {
+			return false, 0, ""
+		}
+		pct := (cl/base - 1) * 100
+		if math.Abs(pct) >= r.GetThreshold() {
+			return true, pct, fmt.Sprintf("%s moved %+.2f%% over %d windows (%g -> %g)", r.GetSymbol(), pct, n, base, cl)
+		}
+	case pricev1.AlertKind_AK_SPREAD_ABOVE:
+		if c.GetQuoteCount() > 0 && c.GetSpreadAvg() > r.GetThreshold() {
+			return true, c.GetSpreadAvg(), fmt.Sprintf("%s average spread %g above %g", r.GetSymbol(), c.GetSpreadAvg(), r.GetThreshold())
+		}
+	case pricev1.AlertKind_AK_VOLUME_SPIKE:
+		n := int(r.GetWindows())
+		if len(h.volumes) < n {
+			return false, 0, ""
+		}
+		var sum float64
+		for _, v := range h.volumes[len(h.volumes)-n:] {
+			sum += v
+		}
+		mean := sum / float64(n)
+		if mean > 0 && c.GetVolume() >= r.GetThreshold()*mean {
+			ratio := c.GetVolume() / mean
+			return true, ratio, fmt.Sprintf("%s volume %g is %.1fx the %d-window mean", r.GetSymbol(), c.GetVolume(), ratio, n)
+		}
+	case pricev1.AlertKind_AK_FEED_STALE:
+		if c.GetStale() {
+			return true, 0, fmt.Sprintf("%s stale sources: %s", r.GetSymbol(), strings.Join(c.GetStaleSources(), ","))
+		}
+	}
+	return false, 0, ""
+}
+
+// onTick raises feed-stale rules for symbols without a candle for their
+// stale_after_ms (measured from startup until the first candle).
+func (e *Engine) onTick(now, start time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range e.rules {
+		after := time.Duration(r.r.GetStaleAfterMs()) * time.Millisecond
+		if r.r.GetKind() != pricev1.AlertKind_AK_FEED_STALE || after <= 0 || r.active {
+			continue
+		}
+		last := start
+		if h := e.hist[r.r.GetSymbol()]; h != nil {
+			last = h.lastSeen
+		}
+		if idle := now.Sub(last); idle >= after {
+			r.active = true
+			e.fire(r.r, idle.Seconds(), fmt.Sprintf("%s: no candles for %s", r.r.GetSymbol(), idle.Truncate(time.Second)), 0)
+		}
+	}
+}
+
+// fire must be called with e.mu held.
+func (e *Engine) fire(r *pricev1.AlertRule, value float64, msg string, windowEndMs int64) {
+	threshold := r.GetThreshold()
+	if r.GetKind() == pricev1.AlertKind_AK_PRICE_CROSS {
+		threshold = r.GetLevel()
+	}
+	a := &pricev1.Alert{
+		RuleId:      r.GetId(),
+		Symbol:      r.GetSymbol(),
+		Kind:        r.GetKind(),
+		Message:     msg,
+		Value:       value,
+		Threshold:   threshold,
+		WindowEndMs: windowEndMs,
+		Ts:          time.Now().UnixMilli(),
+		Note:        r.GetNote(),
+	}
+	log.Printf("alert %s: %s", a.RuleId, a.Message)
+	for ch, f := range e.subs {
+		if f.symbol != "" && f.symbol != a.Symbol || f.owner != "" && f.owner != r.GetOwner() {
+			continue
+		}
+		select {
+		case ch <- a:
+		default:
+		}
+	}
+	if r.GetWebhook() && e.webhook != "" && e.posts != nil {
+		select {
+		case e.posts <- a:
+		default:
+			log.Printf("alert webhook: queue full; dropping alert %s", a.RuleId)
+		}
+	}
+}
+
+// postAll posts the alerts from posts until it is closed. Alerts still
+// queued when ctx ends are dropped.
+func (e *Engine) postAll(ctx context.Context, posts <-chan *pricev1.Alert) {
+	dropped := 0
+	for a := range posts {
+		if ctx.Err() != nil {
+			dropped++
+			continue
+		}
+		e.post(ctx, a)
+	}
+	if dropped > 0 {
+		log.Printf("alert webhook: dropped %d alerts at shutdown", dropped)
+	}
+}
+
+func (e *Engine) post(ctx context.Context, a *pricev1.Alert) {
+	body, err := protojson.Marshal(a)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.webhook, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("alert webhook: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.httpc.Do(req)
+	if err != nil {
+		log.Printf("alert webhook: %v", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Printf("alert webhook: %s", resp.Status)
+	}
+}
@@ -0,0 +1,284 @@
+package alert
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+func newEngine(t *testing.T, webhook string) *Engine {
+	t.Helper()
+	e, err := NewEngine(filepath.Join(t.TempDir(), "rules.json"), webhook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func add(t *testing.T, e *Engine, r *pricev1.AlertRule) string {
+	t.Helper()
+	r, err := e.Add(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r.GetId()
+}
+
+// final returns the final candle of window i.
+func final(close, volume float64, i int64) *pricev1.Candle {
+	return &pricev1.Candle{Symbol: "BTCUSDT", Close: close, Volume: volume, WindowEndMs: (i + 1) * 1000, IsFinal: true}
+}
+
+// received drains ch and returns the rule ids of the alerts in it,
+// sorted: rules are evaluated in map order.
+func received(ch <-chan *pricev1.Alert) []string {
+	var ids []string
+	for {
+		select {
+		case a := <-ch:
+			ids = append(ids, a.GetRuleId())
+		default:
+			slices.Sort(ids)
+			return ids
+		}
+	}
+}
+
+func sorted(ids ...string) []string {
+	slices.Sort(ids)
+	return ids
+}
+
+func TestEvaluate(t *testing.T) {
+	cross := &pricev1.AlertRule{Symbol: "BTCUSDT", Kind: pricev1.AlertKind_AK_PRICE_CROSS, Level: 100}
+	move := &pricev1.AlertRule{Symbol: "BTCUSDT", Kind: pricev1.AlertKind_AK_PCT_MOVE, Threshold: 5, Windows: 2}
+	spread := &pricev1.AlertRule{Symbol: "BTCUSDT", Kind: pricev1.AlertKind_AK_SPREAD_ABOVE, Threshold: 0.5}
+	spike := &pricev1.AlertRule{Symbol: "BTCUSDT", Kind: pricev1.AlertKind_AK_VOLUME_SPIKE, Threshold: 3, Windows: 2}
+	stale := &pricev1.AlertRule{Symbol: "BTCUSDT", Kind: pricev1.AlertKind_AK_FEED_STALE}
+
+	tests := []struct {
+		name   string
+		rule   *pricev1.AlertRule
+		closes []float64
+		vols   []float64
+		c      *pricev1.Candle
+		hit    bool
+		value  float64
+	}{
+		{"cross without history", cross, nil, nil, final(101, 0, 0), false, 0},
+		{"cross above", cross, []float64{99}, nil, final(100, 0, 0), true, 100},
+		{"cross below", cross, []float64{101}, nil, final(99.5, 0, 0), true, 99.5},
+		{"stays above", cross, []float64{101}, nil, final(102, 0, 0), false, 0},
+		{"move short history", move, []float64{100}, nil, final(200, 0, 0), false, 0},
+		{"move up", move, []float64{100, 150}, nil, final(106, 0, 0), true, 6},
+		{"move down", move, []float64{100, 150}, nil, final(94, 0, 0), true, -6},
+		{"move below threshold", move, []float64{100, 150}, nil, final(104, 0, 0), false, 0},
+		{"move from zero", move, []float64{0, 150}, nil, final(104, 0, 0), false, 0},
+		{"spread above", spread, nil, nil, &pricev1.Candle{SpreadAvg: 0.6, QuoteCount: 3}, true, 0.6},
+		{"spread without quotes", spread, nil, nil, &pricev1.Candle{SpreadAvg: 0.6}, false, 0},
+		{"spread below", spread, nil, nil, &pricev1.Candle{SpreadAvg: 0.4, QuoteCount: 3}, false, 0},
+		{"spike", spike, nil, []float64{5, 1, 3}, final(0, 6, 0), true, 3},
+		{"no spike", spike, nil, []float64{5, 1, 3}, final(0, 5, 0), false, 0},
+		{"spike short history", spike, nil, []float64{1}, final(0, 100, 0), false, 0},
+		{"spike zero mean", spike, nil, []float64{0, 0}, final(0, 100, 0), false, 0},
+		{"stale", stale, nil, nil, &pricev1.Candle{Stale: true, StaleSources: []string{"binance"}}, true, 0},
+		{"fresh", stale, nil, nil, &pricev1.Candle{}, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, value, msg := evaluate(tt.rule, tt.c, &history{closes: tt.closes, volumes: tt.vols})
+			if hit != tt.hit || math.Abs(value-tt.value) > 1e-9 {
+				t.Errorf("evaluate = %v, %g; want %v, %g", hit, value, tt.hit, tt.value)
+			}
+			if hit != (msg != "") {
+				t.Errorf("message %q with hit %v", msg, hit)
+			}
+		})
+	}
+}
+
+func TestEdge(t *testing.T) {
+	e := newEngine(t, "")
+	move := add(t, e, &pricev1.AlertRule{Symbol: "btcusdt", Kind: pricev1.AlertKind_AK_PCT_MOVE, Threshold: 1, Windows: 1})
+	cross := add(t, e, &pricev1.AlertRule{Symbol: "BTCUSDT", Kind: pricev1.AlertKind_AK_PRICE_CROSS, Level: 101})
+	add(t, e, &pricev1.AlertRule{Symbol: "ETHUSDT", Kind: pricev1.AlertKind_AK_PCT_MOVE, Threshold: 1, Windows: 1})
+	ch, unsub := e.Subscribe("btcusdt", "")
+	defer unsub()
+
+	tests := []struct {
+		close float64
+		want  []string
+	}{
+		{100, nil},
+		{102, sorted(move, cross)}, // +2%, crosses 101
+		{104.1, nil},               // +2.06%: the move rule is still active
+		{104.5, nil},               // +0.38% resets it
+		{106, []string{move}},      // +1.44%
+		{100.5, []string{cross}},   // -5.19%, still active; crosses back
+		{100.6, nil},               // +0.1% resets the move rule
+		{99, []string{move}},       // -1.59%
+	}
+	for i, tt := range tests {
+		e.onCandle(final(tt.close, 1, int64(i)))
+		if got := received(ch); !slices.Equal(got, tt.want) {
+			t.Errorf("close %g: alerts %v, want %v", tt.close, got, tt.want)
+		}
+	}
+}
+
+func TestSubscribeOwner(t *testing.T) {
+	e := newEngine(t, "")
+	alice := add(t, e, &pricev1.AlertRule{Symbol: "BTCUSDT", Kind: pricev1.AlertKind_AK_PRICE_CROSS, Level: 101, Owner: "alice"})
+	bob := add(t, e, &pricev1.AlertRule{Symbol: "BTCUSDT", Kind: pricev1.AlertKind_AK_PRICE_CROSS, Level: 101, Owner: "bob"})
+	aliceCh, unsub := e.Subscribe("", "alice")
+	defer unsub()
+	allCh, unsub := e.Subscribe("", "")
+	defer unsub()
+
+	e.onCandle(final(100, 1, 0))
+	e.onCandle(final(102, 1, 1))
+	if got := received(aliceCh); len(got) != 1 || got[0] != alice {
+		t.Errorf("alice got %v, want [%s]", got, alice)
+	}
+	if got := received(allCh); !slices.Equal(got, sorted(alice, bob)) {
+		t.Errorf("unfiltered subscriber got %v, want both", got)
+	}
+}
+
+func TestHistoryWindows(t *testing.T) {
+	e := newEngine(t, "")
+	id := add(t, e, &pricev1.AlertRule{Symbol: "BTCUSDT", Kind: pricev1.AlertKind_AK_PCT_MOVE, Threshold: 50, Windows: MaxWindows})
+	ch, unsub := e.Subscribe("", "")
+	defer unsub()
+
+	// 100 once, then MaxWindows candles at 120
+	e.onCandle(final(100, 1, 0))
+	for i := range MaxWindows {
+		e.onCandle(final(120, 1, int64(i+1)))
+	}
+	if n := len(e.hist["BTCUSDT"].closes); n != MaxWindows+1 {
+		t.Fatalf("%d closes kept, want %d", n, MaxWindows+1)
+	}
+	// 160 would be +60% on the 100, which is MaxWindows+1 back now;
+	// against the 120 MaxWindows back it is +33%
+	e.onCandle(final(160, 1, MaxWindows+1))
+	if got := received(ch); len(got) != 0 {
+		t.Errorf("alerts %v: the base must be %d windows back", got, MaxWindows)
+	}
+	h := e.hist["BTCUSDT"]
+	if n := len(h.closes); n != MaxWindows+1 || h.closes[0] != 120 {
+		t.Fatalf("%d closes from %g, want %d from 120", n, h.closes[0], MaxWindows+1)
+	}
+	e.onCandle(final(190, 1, MaxWindows+2))
+	if got := received(ch); len(got) != 1 || got[0] != id {
+		t.Errorf("alerts %v, want the move rule", got)
+	}
+}
+
+func TestOnTickStale(t *testing.T) {
+	e := newEngine(t, "")
+	id := add(t, e, &pricev1.AlertRule{Symbol: "BTCUSDT", Kind: pricev1.AlertKind_AK_FEED_STALE, StaleAfterMs: 1000})
+	add(t, e, &pricev1.AlertRule{Symbol: "BTCUSDT", Kind: pricev1.AlertKind_AK_FEED_STALE}) // candle flag only
+	ch, unsub := e.Subscribe("", "")
+	defer unsub()
+	start := time.Now()
+
+	steps := []struct {
+		name  string
+		do    func()
+		fires bool
+	}{
+		{"within stale_after from startup", func() { e.onTick(start.Add(999*time.Millisecond), start) }, false},
+		{"stale since startup", func() { e.onTick(start.Add(time.Second), start) }, true},
+		{"still stale", func() { e.onTick(start.Add(5*time.Second), start) }, false},
+		{"a fresh candle resets it", func() { e.onCandle(final(100, 1, 0)) }, false},
+		{"within stale_after of the candle", func() { e.onTick(time.Now().Add(500*time.Millisecond), start) }, false},
+		{"stale again", func() { e.onTick(time.Now().Add(2*time.Second), start) }, true},
+	}
+	for _, st := range steps {
+		st.do()
+		got := received(ch)
+		if st.fires && (len(got) != 1 || got[0] != id) || !st.fires && len(got) != 0 {
+			t.Errorf("%s: alerts %v, fires %v", st.name, got, st.fires)
+		}
+	}
+}
+
+func TestPersistReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	e, err := NewEngine(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keep := add(t, e, &pricev1.AlertRule{Symbol: "btcusdt", Kind: pricev1.AlertKind_AK_PRICE_CROSS, Level: 100, Owner: "alice", Note: "n"})
+	drop := add(t, e, &pricev1.AlertRule{Symbol: "ETHUSDT", Kind: pricev1.AlertKind_AK_VOLUME_SPIKE, Threshold: 3, Windows: 10})
+	if _, err := e.Add(&pricev1.AlertRule{Symbol: "BTCUSDT", Kind: pricev1.AlertKind_AK_PCT_MOVE, Threshold: 1}); err == nil {
+		t.Error("a move rule without windows was accepted")
+	}
+	if ok, err := e.Remove(drop); !ok || err != nil {
+		t.Fatalf("Remove = %v, %v", ok, err)
+	}
+
+	reloaded, err := NewEngine(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, want := reloaded.List(""), e.List("")
+	if len(got) != 1 || got[0].GetId() != keep || !proto.Equal(got[0], want[0]) {
+		t.Errorf("reloaded %v, want %v", got, want)
+	}
+	if got[0].GetSymbol() != "BTCUSDT" {
+		t.Errorf("symbol %q, want it upper-cased", got[0].GetSymbol())
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	posted := make(chan *pricev1.Alert, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		a := &pricev1.Alert{}
+		if err := protojson.Unmarshal(b, a); err != nil {
+			t.Errorf("webhook body %s: %v", b, err)
+		}
+		posted <- a
+	}))
+	defer srv.Close()
+	e := newEngine(t, srv.URL)
+	id := add(t, e, &pricev1.AlertRule{Symbol: "BTCUSDT", Kind: pricev1.AlertKind_AK_PRICE_CROSS, Level: 101, Webhook: true})
+	add(t, e, &pricev1.AlertRule{Symbol: "BTCUSDT", Kind: pricev1.AlertKind_AK_PRICE_CROSS, Level: 101})
+
+	candles := make(chan *pricev1.Candle, 2)
+	candles <- final(100, 1, 0)
+	candles <- final(102, 1, 1)
+	close(candles)
+	done := make(chan struct{})
+	go func() {
+		e.Run(context.Background(), candles)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return")
+	}
+	// Run returned after the worker: the post has been made
+	select {
+	case a := <-posted:
+		if a.GetRuleId() != id || a.GetValue() != 102 || a.GetThreshold() != 101 {
+			t.Errorf("webhook got %v", a)
+		}
+	default:
+		t.Fatal("no webhook before Run returned")
+	}
+}
@@ -0,0 +1,67 @@
+// path: pkg/alert/store.go
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// load reads rules saved by save; a missing file means no rules yet.
+func load(path string) ([]*pricev1.AlertRule, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("alert rules %s: %w", path, err)
+	}
+	rules := make([]*pricev1.AlertRule, 0, len(raw))
+	for _, r := range raw {
+		rule := &pricev1.AlertRule{}
+		if err := protojson.Unmarshal(r, rule); err != nil {
+			return nil, fmt.Errorf("alert rules %s: %w", path, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// save writes rules as a JSON array, replacing the file atomically.
+func save(path string, rules []*pricev1.AlertRule) error {
+	raw := make([]json.RawMessage, len(rules))
+	for i, r := range rules {
+		b, err := protojson.Marshal(r)
+		if err != nil {
+			return err
+		}
+		raw[i] = b
+	}
+	data, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".alert-rules-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,364 @@
+// path: pkg/auth/auth.go
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrUnauthenticated means no valid credentials were presented.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrTooManyStreams means the client is at its max_streams limit.
+	ErrTooManyStreams = errors.New("too many concurrent streams")
+)
+
+// Entitlements limit what a client may access. Empty lists allow everything.
+type Entitlements struct {
+	Symbols     []string `json:"symbols"`      // glob patterns, e.g. "BTC*"
+	IntervalsMs []int64  `json:"intervals_ms"` // time-bar intervals
+	Exchanges   []string `json:"exchanges"`    // venues whose data the client may receive
+	MaxStreams  int      `json:"max_streams"`  // concurrent streams, 0 = unlimited
+
+	Quota *Quota `json:"quota"` // daily usage limits, nil = none
+	Admin bool   `json:"admin"` // may read every client's usage report
+}
+
+// Quota limits a client's daily (UTC) usage as counted by the engine's
+// meter. Zero limits are unlimited.
+type Quota struct {
+	MessagesPerDay      uint64  `json:"messages_per_day"`
+	BytesPerDay         uint64  `json:"bytes_per_day"`
+	StreamSecondsPerDay float64 `json:"stream_seconds_per_day"`
+	OnExceed            string  `json:"on_exceed"` // degrade (default) or terminate
+}
+
+// Quota actions.
+const (
+	QuotaDegrade   = "degrade"
+	QuotaTerminate = "terminate"
+)
+
+func (q *Quota) validate() error {
+	switch q.OnExceed {
+	case "", QuotaDegrade, QuotaTerminate:
+		return nil
+	}
+	return fmt.Errorf("on_exceed must be %q or %q", QuotaDegrade, QuotaTerminate)
+}
+
+// Client is one API client in the auth config file.
+type Client struct {
+	ID           string   `json:"id"`
+	APIKeySHA256 []string `json:"api_key_sha256"` // hex SHA-256 of each accepted key
+	CertNames    []string `json:"cert_names"`     // client certificate CN or DNS SAN; defaults to ID
+	Entitlements
+}
+
+// JWTConfig verifies bearer tokens. The token subject must be a configured
+// client ID; its entitlements come from the config file.
+type JWTConfig struct {
+	HMACSecretFile string `json:"hmac_secret_file"` // HS256/384/512
+	PublicKeyFile  string `json:"public_key_file"`  // PEM RSA, ECDSA or Ed25519 key
+	Issuer         string `json:"issuer"`
+	Audience       string `json:"audience"`
+}
+
+// Config is the JSON auth config file.
+type Config struct {
+	Clients []Client   `json:"clients"`
+	JWT     *JWTConfig `json:"jwt"`
+}
+
+// Principal is an authenticated client.
+type Principal struct {
+	ID     string
+	Method string // api_key, jwt or mtls
+	Peer   string // remote address, set by the transport
+	Entitlements
+
+	a *Authenticator
+}
+
+// Deny writes an audit record for a request p was refused.
+func (p *Principal) Deny(method, symbol, reason string) {
+	p.a.Deny(Denial{Client: p.ID, Method: method, Symbol: symbol, Peer: p.Peer, Reason: reason})
+}
+
+// AcquireStream takes one of p's stream slots; see Authenticator.AcquireStream.
+func (p *Principal) AcquireStream() (release func(), err error) {
+	return p.a.AcquireStream(p)
+}
+
+// AllowSymbol reports whether the symbol matches one of the patterns.
+func (p *Principal) AllowSymbol(symbol string) bool {
+	if len(p.Symbols) == 0 {
+		return true
+	}
+	for _, pat := range p.Symbols {
+		if ok, _ := path.Match(strings.ToUpper(pat), strings.ToUpper(symbol)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Principal) AllowInterval(ms int64) bool {
+	return len(p.IntervalsMs) == 0 || slices.Contains(p.IntervalsMs, ms)
+}
+
+func (p *Principal) AllowExchange(exchange string) bool {
+	if len(p.Exchanges) == 0 {
+		return true
+	}
+	for _, e := range p.Exchanges {
+		if strings.EqualFold(e, exchange) {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the authenticated client; ok is false when auth is
+// disabled.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// Credentials are what a request presented.
+type Credentials struct {
+	APIKey string
+	Bearer string
+	Certs  []*x509.Certificate // verified client chain, leaf first
+}
+
+// Authenticator resolves credentials to clients, counts their streams and
+// writes the audit log.
+type Authenticator struct {
+	clients map[string]*Client
+	keys    map[[sha256.Size]byte]*Client
+	certs   map[string]*Client
+
+	jwtKey     any
+	jwtMethods []string
+	jwtOpts    []jwt.ParserOption
+
+	mu      sync.Mutex
+	streams map[string]int
+	audit   io.Writer
+}
+
+// Load reads the config file. Denials are written to audit as JSON lines
+// (os.Stderr if nil).
+func Load(file string, audit io.Writer) (*Authenticator, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("auth config %s: %w", file, err)
+	}
+	if audit == nil {
+		audit = os.Stderr
+	}
+	a := &Authenticator{
+		clients: make(map[string]*Client),
+		keys:    make(map[[sha256.Size]byte]*Client),
+		certs:   make(map[string]*Client),
+		streams: make(map[string]int),
+		audit:   audit,
+	}
+	for i := range cfg.Clients {
+		c := &cfg.Clients[i]
+		if c.ID == "" {
+			return nil, fmt.Errorf("auth config %s: client %d has no id", file, i)
+		}
+		if _, dup := a.clients[c.ID]; dup {
+			return nil, fmt.Errorf("auth config %s: duplicate client %q", file, c.ID)
+		}
+		a.clients[c.ID] = c
+		if c.Quota != nil {
+			if err := c.Quota.validate(); err != nil {
+				return nil, fmt.Errorf("auth config %s: client %q: quota: %w", file, c.ID, err)
+			}
+		}
+		for _, h := range c.APIKeySHA256 {
+			b, err := hex.DecodeString(h)
+			if err != nil || len(b) != sha256.Size {
+				return nil, fmt.Errorf("auth config %s: client %q: api_key_sha256 must be 64 hex characters", file, c.ID)
+			}
+			a.keys[[sha256.Size]byte(b)] = c
+		}
+		names := c.CertNames
+		if len(names) == 0 {
+			names = []string{c.ID}
+		}
+		for _, n := range names {
+			a.certs[n] = c
+		}
+	}
+	if cfg.JWT != nil {
+		if err := a.loadJWT(cfg.JWT); err != nil {
+			return nil, fmt.Errorf("auth config %s: jwt: %w", file, err)
+		}
+	}
+	return a, nil
+}
+
+func (a *Authenticator) loadJWT(cfg *JWTConfig) error {
+	switch {
+	case cfg.HMACSecretFile != "" && cfg.PublicKeyFile != "":
+		return errors.New("set either hmac_secret_file or public_key_file")
+	case cfg.HMACSecretFile != "":
+		secret, err := os.ReadFile(cfg.HMACSecretFile)
+		if err != nil {
+			return err
+		}
+		a.jwtKey = []byte(strings.TrimSpace(string(secret)))
+		a.jwtMethods = []string{"HS256", "HS384", "HS512"}
+	case cfg.PublicKeyFile != "":
+		data, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return err
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return errors.New("public_key_file is not PEM")
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return err
+		}
+		switch key.(type) {
+		case *rsa.PublicKey:
+			a.jwtMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+		case *ecdsa.PublicKey:
+			a.jwtMethods = []string{"ES256", "ES384", "ES512"}
+		case ed25519.PublicKey:
+			a.jwtMethods = []string{"EdDSA"}
+		default:
+			return fmt.Errorf("unsupported key type %T", key)
+		}
+		a.jwtKey = key
+	default:
+		return errors.New("hmac_secret_file or public_key_file required")
+	}
+	a.jwtOpts = []jwt.ParserOption{jwt.WithValidMethods(a.jwtMethods), jwt.WithExpirationRequired(), jwt.WithLeeway(30 * time.Second)}
+	if cfg.Issuer != "" {
+		a.jwtOpts = append(a.jwtOpts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		a.jwtOpts = append(a.jwtOpts, jwt.WithAudience(cfg.Audience))
+	}
+	return nil
+}
+
+// Authenticate tries the API key, then the bearer token, then the client
+// certificate.
+func (a *Authenticator) Authenticate(cr Credentials) (*Principal, error) {
+	switch {
+	case cr.APIKey != "":
+		// keys are looked up by hash, so lookup timing reveals nothing about them
+		if c := a.keys[sha256.Sum256([]byte(cr.APIKey))]; c != nil {
+			return &Principal{ID: c.ID, Method: "api_key", Entitlements: c.Entitlements, a: a}, nil
+		}
+		return nil, fmt.Errorf("%w: unknown api key", ErrUnauthenticated)
+	case cr.Bearer != "":
+		if a.jwtKey == nil {
+			return nil, fmt.Errorf("%w: bearer tokens not accepted", ErrUnauthenticated)
+		}
+		var claims jwt.RegisteredClaims
+		_, err := jwt.ParseWithClaims(cr.Bearer, &claims, func(*jwt.Token) (any, error) { return a.jwtKey, nil }, a.jwtOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+		}
+		c := a.clients[claims.Subject]
+		if c == nil {
+			return nil, fmt.Errorf("%w: unknown token subject %q", ErrUnauthenticated, claims.Subject)
+		}
+		return &Principal{ID: c.ID, Method: "jwt", Entitlements: c.Entitlements, a: a}, nil
+	case len(cr.Certs) > 0:
+		leaf := cr.Certs[0]
+		for _, n := range append([]string{leaf.Subject.CommonName}, leaf.DNSNames...) {
+			if c := a.certs[n]; c != nil && n != "" {
+				return &Principal{ID: c.ID, Method: "mtls", Entitlements: c.Entitlements, a: a}, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: certificate %q matches no client", ErrUnauthenticated, leaf.Subject.CommonName)
+	}
+	return nil, fmt.Errorf("%w: no credentials", ErrUnauthenticated)
+}
+
+// AcquireStream takes one of p's stream slots; call release when the
+// stream ends.
+func (a *Authenticator) AcquireStream(p *Principal) (release func(), err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if p.MaxStreams > 0 && a.streams[p.ID] >= p.MaxStreams {
+		return nil, fmt.Errorf("%w: limit %d", ErrTooManyStreams, p.MaxStreams)
+	}
+	a.streams[p.ID]++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			a.mu.Lock()
+			defer a.mu.Unlock()
+			if a.streams[p.ID]--; a.streams[p.ID] <= 0 {
+				delete(a.streams, p.ID)
+			}
+		})
+	}, nil
+}
+
+// Denial is one audit log record.
+type Denial struct {
+	Time   time.Time `json:"ts"`
+	Client string    `json:"client,omitempty"` // empty when authentication failed
+	Method string    `json:"method"`
+	Symbol string    `json:"symbol,omitempty"`
+	Peer   string    `json:"peer,omitempty"`
+	Reason string    `json:"reason"`
+}
+
+// Deny writes d to the audit log.
+func (a *Authenticator) Deny(d Denial) {
+	if d.Time.IsZero() {
+		d.Time = time.Now().UTC()
+	}
+	b, err := json.Marshal(struct {
+		Event string `json:"event"`
+		Denial
+	}{"deny", d})
+	if err != nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.audit.Write(append(b, '\n'))
+}
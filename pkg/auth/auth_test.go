@@ -0,0 +1,326 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// load writes cfg and returns its Authenticator and audit log. Clients
+// without keys get their id as API key.
+func load(t *testing.T, cfg Config) (*Authenticator, *bytes.Buffer) {
+	t.Helper()
+	for i := range cfg.Clients {
+		if cfg.Clients[i].APIKeySHA256 == nil {
+			sum := sha256.Sum256([]byte(cfg.Clients[i].ID))
+			cfg.Clients[i].APIKeySHA256 = []string{hex.EncodeToString(sum[:])}
+		}
+	}
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := writeFile(t, "auth.json", b)
+	var audit bytes.Buffer
+	a, err := Load(file, &audit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a, &audit
+}
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  string
+		want string
+	}{
+		{"no id", `{"clients":[{"api_key_sha256":[]}]}`, "has no id"},
+		{"duplicate", `{"clients":[{"id":"a"},{"id":"a"}]}`, "duplicate client"},
+		{"short hash", `{"clients":[{"id":"a","api_key_sha256":["abcd"]}]}`, "64 hex characters"},
+		{"quota action", `{"clients":[{"id":"a","quota":{"on_exceed":"explode"}}]}`, "on_exceed"},
+		{"jwt without key", `{"jwt":{}}`, "hmac_secret_file or public_key_file required"},
+		{"jwt with two keys", `{"jwt":{"hmac_secret_file":"a","public_key_file":"b"}}`, "either"},
+		{"malformed", `{"clients":`, "auth config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeFile(t, "auth.json", []byte(tt.cfg)), &bytes.Buffer{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load = %v, want an error with %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	a, _ := load(t, Config{Clients: []Client{{ID: "alice", Entitlements: Entitlements{Symbols: []string{"BTC*"}}}}})
+	p, err := a.Authenticate(Credentials{APIKey: "alice"})
+	if err != nil || p.ID != "alice" || p.Method != "api_key" || !p.AllowSymbol("btcusdt") || p.AllowSymbol("ETHUSDT") {
+		t.Errorf("Authenticate = %+v, %v", p, err)
+	}
+	for _, cr := range []Credentials{{APIKey: "bob"}, {}} {
+		if _, err := a.Authenticate(cr); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("%+v: %v, want ErrUnauthenticated", cr, err)
+		}
+	}
+	if _, err := a.Authenticate(Credentials{Bearer: "x.y.z"}); err == nil || !strings.Contains(err.Error(), "not accepted") {
+		t.Errorf("bearer without jwt config: %v", err)
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, claims jwt.Claims) string {
+	t.Helper()
+	s, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestJWTHMAC(t *testing.T) {
+	secret := []byte("s3cret")
+	a, _ := load(t, Config{
+		Clients: []Client{{ID: "alice"}},
+		JWT: &JWTConfig{
+			HMACSecretFile: writeFile(t, "secret", append(secret, '\n')),
+			Issuer:         "issuer",
+			Audience:       "price-engine",
+		},
+	})
+	now := time.Now()
+	valid := func() jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{
+			Subject:   "alice",
+			Issuer:    "issuer",
+			Audience:  jwt.ClaimStrings{"price-engine"},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		}
+	}
+	with := func(f func(*jwt.RegisteredClaims)) jwt.RegisteredClaims {
+		c := valid()
+		f(&c)
+		return c
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"valid", sign(t, jwt.SigningMethodHS256, secret, valid()), true},
+		{"HS512", sign(t, jwt.SigningMethodHS512, secret, valid()), true},
+		{"within leeway", sign(t, jwt.SigningMethodHS256, secret, with(func(c *jwt.RegisteredClaims) {
+			c.ExpiresAt = jwt.NewNumericDate(now.Add(-10 * time.Second))
+		})), true},
+		{"wrong secret", sign(t, jwt.SigningMethodHS256, []byte("other"), valid()), false},
+		{"alg none", sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, valid()), false},
+		{"expired", sign(t, jwt.SigningMethodHS256, secret, with(func(c *jwt.RegisteredClaims) {
+			c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute))
+		})), false},
+		{"no exp", sign(t, jwt.SigningMethodHS256, secret, with(func(c *jwt.RegisteredClaims) { c.ExpiresAt = nil })), false},
+		{"wrong issuer", sign(t, jwt.SigningMethodHS256, secret, with(func(c *jwt.RegisteredClaims) { c.Issuer = "mallory" })), false},
+		{"no issuer", sign(t, jwt.SigningMethodHS256, secret, with(func(c *jwt.RegisteredClaims) { c.Issuer = "" })), false},
+		{"wrong audience", sign(t, jwt.SigningMethodHS256, secret, with(func(c *jwt.RegisteredClaims) {
+			c.Audience = jwt.ClaimStrings{"other"}
+		})), false},
+		{"unknown subject", sign(t, jwt.SigningMethodHS256, secret, with(func(c *jwt.RegisteredClaims) { c.Subject = "bob" })), false},
+		{"malformed", "not.a.token", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := a.Authenticate(Credentials{Bearer: tt.token})
+			if tt.ok {
+				if err != nil || p.ID != "alice" || p.Method != "jwt" {
+					t.Errorf("Authenticate = %+v, %v", p, err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrUnauthenticated) {
+				t.Errorf("Authenticate = %+v, %v; want ErrUnauthenticated", p, err)
+			}
+		})
+	}
+}
+
+func TestJWTPublicKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	a, _ := load(t, Config{
+		Clients: []Client{{ID: "alice"}},
+		JWT:     &JWTConfig{PublicKeyFile: writeFile(t, "key.pem", pub)},
+	})
+	claims := jwt.RegisteredClaims{Subject: "alice", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}
+
+	if _, err := a.Authenticate(Credentials{Bearer: sign(t, jwt.SigningMethodES256, key, claims)}); err != nil {
+		t.Errorf("ES256: %v", err)
+	}
+	// the algorithm is pinned to the key type: an HMAC token keyed with
+	// the public key must not verify
+	for _, key := range [][]byte{pub, der} {
+		if _, err := a.Authenticate(Credentials{Bearer: sign(t, jwt.SigningMethodHS256, key, claims)}); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("HS256 with the public key: %v, want ErrUnauthenticated", err)
+		}
+	}
+}
+
+func TestMTLS(t *testing.T) {
+	a, _ := load(t, Config{Clients: []Client{
+		{ID: "alice"},
+		{ID: "bob", CertNames: []string{"bob.clients.example", "robert"}},
+	}})
+	cert := func(cn string, sans ...string) []*x509.Certificate {
+		return []*x509.Certificate{{Subject: pkix.Name{CommonName: cn}, DNSNames: sans}}
+	}
+
+	tests := []struct {
+		name  string
+		certs []*x509.Certificate
+		id    string
+	}{
+		{"CN is the id", cert("alice"), "alice"},
+		{"CN from cert_names", cert("robert"), "bob"},
+		{"SAN from cert_names", cert("", "other.example", "bob.clients.example"), "bob"},
+		{"id not a name once cert_names is set", cert("bob"), ""},
+		{"no match", cert("mallory", "mallory.example"), ""},
+		{"empty", cert(""), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := a.Authenticate(Credentials{Certs: tt.certs})
+			if tt.id == "" {
+				if !errors.Is(err, ErrUnauthenticated) {
+					t.Errorf("Authenticate = %+v, %v; want ErrUnauthenticated", p, err)
+				}
+				return
+			}
+			if err != nil || p.ID != tt.id || p.Method != "mtls" {
+				t.Errorf("Authenticate = %+v, %v; want %s", p, err, tt.id)
+			}
+		})
+	}
+
+	// an API key takes precedence over the certificate
+	p, err := a.Authenticate(Credentials{APIKey: "bob", Certs: cert("alice")})
+	if err != nil || p.ID != "bob" || p.Method != "api_key" {
+		t.Errorf("API key with a certificate: %+v, %v", p, err)
+	}
+}
+
+func TestAcquireStream(t *testing.T) {
+	a, _ := load(t, Config{Clients: []Client{
+		{ID: "alice", Entitlements: Entitlements{MaxStreams: 2}},
+		{ID: "bob"},
+	}})
+	auth := func(id string) *Principal {
+		p, err := a.Authenticate(Credentials{APIKey: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	// the limit is per client, across principals
+	r1, err := auth("alice").AcquireStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := auth("alice").AcquireStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth("alice").AcquireStream(); !errors.Is(err, ErrTooManyStreams) {
+		t.Fatalf("third stream: %v, want ErrTooManyStreams", err)
+	}
+	// releasing twice frees one slot only
+	r1()
+	r1()
+	r3, err := auth("alice").AcquireStream()
+	if err != nil {
+		t.Fatalf("after a release: %v", err)
+	}
+	if _, err := auth("alice").AcquireStream(); !errors.Is(err, ErrTooManyStreams) {
+		t.Fatalf("double release freed two slots: %v", err)
+	}
+	r2()
+	r3()
+	if n := len(a.streams); n != 0 {
+		t.Errorf("%d clients counted after every release", n)
+	}
+
+	for i := range 100 {
+		if _, err := auth("bob").AcquireStream(); err != nil {
+			t.Fatalf("unlimited client, stream %d: %v", i, err)
+		}
+	}
+}
+
+func TestDenyAudit(t *testing.T) {
+	a, audit := load(t, Config{Clients: []Client{{ID: "alice"}}})
+	p, err := a.Authenticate(Credentials{APIKey: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Peer = "10.0.0.1:5000"
+	p.Deny("/price.v1.PriceStream/StreamAggregates", "ETHUSDT", "symbol not entitled")
+	a.Deny(Denial{Method: "GET /v1/candles", Peer: "10.0.0.2:6000", Reason: "unauthenticated: unknown api key"})
+
+	lines := strings.Split(strings.TrimSpace(audit.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("audit log %q, want 2 lines", audit.String())
+	}
+	want := []map[string]string{
+		{"event": "deny", "client": "alice", "method": "/price.v1.PriceStream/StreamAggregates", "symbol": "ETHUSDT", "peer": "10.0.0.1:5000", "reason": "symbol not entitled"},
+		{"event": "deny", "method": "GET /v1/candles", "peer": "10.0.0.2:6000", "reason": "unauthenticated: unknown api key"},
+	}
+	for i, line := range lines {
+		var rec map[string]string
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("line %d %q: %v", i, line, err)
+		}
+		ts, err := time.Parse(time.RFC3339Nano, rec["ts"])
+		if err != nil || time.Since(ts) > time.Minute {
+			t.Errorf("line %d: ts %q", i, rec["ts"])
+		}
+		delete(rec, "ts")
+		if len(rec) != len(want[i]) {
+			t.Errorf("line %d: %v, want %v", i, rec, want[i])
+		}
+		for k, v := range want[i] {
+			if rec[k] != v {
+				t.Errorf("line %d: %s = %q, want %q", i, k, rec[k], v)
+			}
+		}
+	}
+}
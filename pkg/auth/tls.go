@@ -0,0 +1,54 @@
+// path: pkg/auth/tls.go
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ClientCertMode says whether clients must present a certificate.
+type ClientCertMode int
+
+const (
+	NoClientCert       ClientCertMode = iota
+	OptionalClientCert                // verified if presented, so other credentials still work
+	RequireClientCert
+)
+
+// LoadTLS builds a server TLS config from PEM files. clientCA enables
+// client certificate verification according to mode.
+func LoadTLS(certFile, keyFile, clientCA string, mode ClientCertMode) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("tls: %w", err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if clientCA == "" {
+		if mode == RequireClientCert {
+			return nil, errors.New("tls: client certificates required but no client CA given")
+		}
+		return cfg, nil
+	}
+	pem, err := os.ReadFile(clientCA)
+	if err != nil {
+		return nil, fmt.Errorf("tls: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("tls: no certificates in %s", clientCA)
+	}
+	cfg.ClientCAs = pool
+	switch mode {
+	case RequireClientCert:
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	case OptionalClientCert:
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return cfg, nil
+}
@@ -0,0 +1,188 @@
+// path: pkg/calendar/calendar.go
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // the built-in calendars load without a system zoneinfo database
+)
+
+// State is whether a symbol's market trades.
+type State int
+
+const (
+	Open State = iota
+	Closed
+	Halted
+)
+
+func (s State) String() string {
+	switch s {
+	case Open:
+		return "open"
+	case Closed:
+		return "closed"
+	case Halted:
+		return "halted"
+	}
+	return "unknown"
+}
+
+// Status is a symbol's market state at a point in time.
+type Status struct {
+	State  State
+	Reason string
+	Until  time.Time // Closed: next open; Halted: planned end; zero = unknown
+}
+
+// clock is a wall-clock time in a week, in minutes since Sunday 00:00.
+type clock int
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseClock parses "Fri 17:00".
+func parseClock(s string) (clock, error) {
+	day, hm, ok := strings.Cut(strings.TrimSpace(s), " ")
+	wd, known := weekdays[strings.ToLower(day)]
+	if !ok || !known {
+		return 0, fmt.Errorf("%q: want weekday and time, e.g. \"Fri 17:00\"", s)
+	}
+	t, err := time.Parse("15:04", strings.TrimSpace(hm))
+	if err != nil {
+		return 0, fmt.Errorf("%q: %w", s, err)
+	}
+	return clock(int(wd)*24*60 + t.Hour()*60 + t.Minute()), nil
+}
+
+func clockOf(t time.Time) clock {
+	return clock(int(t.Weekday())*24*60 + t.Hour()*60 + t.Minute())
+}
+
+// span is a weekly session; close before open wraps over Saturday night.
+type span struct{ open, close clock }
+
+func (s span) contains(c clock) bool {
+	if s.open <= s.close {
+		return c >= s.open && c < s.close
+	}
+	return c >= s.open || c < s.close
+}
+
+// Calendar is a market's weekly trading sessions and holidays in its time
+// zone. A calendar without sessions is always open, except on holidays.
+type Calendar struct {
+	Name     string
+	loc      *time.Location
+	sessions []span
+	holidays map[string]bool // YYYY-MM-DD in loc
+}
+
+// IsOpen reports whether the market trades at t.
+func (c *Calendar) IsOpen(t time.Time) bool {
+	return c.closed(t) == ""
+}
+
+// closed returns why the market does not trade at t, or "".
+func (c *Calendar) closed(t time.Time) string {
+	lt := t.In(c.loc)
+	if c.holidays[lt.Format(time.DateOnly)] {
+		return c.Name + " holiday"
+	}
+	if len(c.sessions) == 0 {
+		return ""
+	}
+	now := clockOf(lt)
+	for _, s := range c.sessions {
+		if s.contains(now) {
+			return ""
+		}
+	}
+	return "outside " + c.Name + " trading sessions"
+}
+
+// NextOpen returns the first time at or after t the market trades, or zero
+// if it does not within the next weeks.
+func (c *Calendar) NextOpen(t time.Time) time.Time {
+	// holidays are whole days, so the market can only open at a local
+	// midnight or a session open
+	for range 64 {
+		if c.IsOpen(t) {
+			return t
+		}
+		t = c.nextBoundary(t)
+	}
+	return time.Time{}
+}
+
+func (c *Calendar) nextBoundary(t time.Time) time.Time {
+	lt := t.In(c.loc)
+	y, m, d := lt.Date()
+	next := time.Date(y, m, d+1, 0, 0, 0, 0, c.loc)
+	for _, s := range c.sessions {
+		days := (int(s.open)/(24*60) - int(lt.Weekday()) + 7) % 7
+		mins := int(s.open) % (24 * 60)
+		at := time.Date(y, m, d+days, mins/60, mins%60, 0, 0, c.loc)
+		if !at.After(t) {
+			at = at.AddDate(0, 0, 7)
+		}
+		if at.Before(next) {
+			next = at
+		}
+	}
+	return next
+}
+
+// Spec is a calendar in the calendar file.
+type Spec struct {
+	TimeZone string        `json:"timezone"` // IANA name, default UTC
+	Sessions []SessionSpec `json:"sessions"` // empty = always open
+	Holidays []string      `json:"holidays"` // YYYY-MM-DD in the time zone, closed all day
+}
+
+// SessionSpec is one weekly session, e.g. {"open": "Sun 17:00", "close": "Fri 17:00"}.
+type SessionSpec struct {
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+// New builds the named calendar from spec.
+func New(name string, spec Spec) (*Calendar, error) {
+	loc, err := time.LoadLocation(spec.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("calendar %s: %w", name, err)
+	}
+	c := &Calendar{Name: name, loc: loc, holidays: make(map[string]bool)}
+	for _, ss := range spec.Sessions {
+		open, err := parseClock(ss.Open)
+		if err != nil {
+			return nil, fmt.Errorf("calendar %s: open %w", name, err)
+		}
+		end, err := parseClock(ss.Close)
+		if err != nil {
+			return nil, fmt.Errorf("calendar %s: close %w", name, err)
+		}
+		if open == end {
+			return nil, fmt.Errorf("calendar %s: session opens and closes at %s", name, ss.Open)
+		}
+		c.sessions = append(c.sessions, span{open, end})
+	}
+	for _, h := range spec.Holidays {
+		if _, err := time.Parse(time.DateOnly, h); err != nil {
+			return nil, fmt.Errorf("calendar %s: holiday %q: want YYYY-MM-DD", name, h)
+		}
+		c.holidays[h] = true
+	}
+	return c, nil
+}
+
+// Built-in calendars; the calendar file can redefine them.
+var builtin = map[string]Spec{
+	// spot FX trades from Sunday 17:00 to Friday 17:00 New York time
+	"fx": {TimeZone: "America/New_York", Sessions: []SessionSpec{{Open: "Sun 17:00", Close: "Fri 17:00"}}},
+	// crypto venues never close
+	"crypto": {TimeZone: "UTC"},
+}
@@ -0,0 +1,183 @@
+// path: pkg/calendar/registry.go
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/common"
+)
+
+// Halt suspends trading in a symbol from From (zero = already) until Until
+// (zero = until removed).
+type Halt struct {
+	Symbol string    `json:"symbol"`
+	From   time.Time `json:"from"`
+	Until  time.Time `json:"until"`
+	Reason string    `json:"reason"`
+}
+
+// Config is the JSON calendar file.
+type Config struct {
+	Calendars map[string]Spec   `json:"calendars"` // added to or replacing the built-in fx and crypto
+	Symbols   map[string]string `json:"symbols"`   // symbol -> calendar name
+	Halts     []Halt            `json:"halts"`
+}
+
+type book struct {
+	calendars map[string]*Calendar
+	symbols   map[string]*Calendar
+	halts     map[string][]Halt
+}
+
+func newBook(cfg Config) (*book, error) {
+	b := &book{
+		calendars: make(map[string]*Calendar),
+		symbols:   make(map[string]*Calendar),
+		halts:     make(map[string][]Halt),
+	}
+	specs := make(map[string]Spec, len(builtin)+len(cfg.Calendars))
+	for name, spec := range builtin {
+		specs[name] = spec
+	}
+	for name, spec := range cfg.Calendars {
+		specs[name] = spec
+	}
+	for name, spec := range specs {
+		c, err := New(name, spec)
+		if err != nil {
+			return nil, err
+		}
+		b.calendars[name] = c
+	}
+	for sym, name := range cfg.Symbols {
+		c := b.calendars[name]
+		if c == nil {
+			return nil, fmt.Errorf("symbol %s: unknown calendar %q", sym, name)
+		}
+		b.symbols[strings.ToUpper(sym)] = c
+	}
+	for _, h := range cfg.Halts {
+		if h.Symbol == "" {
+			return nil, fmt.Errorf("halt without symbol")
+		}
+		if !h.Until.IsZero() && !h.Until.After(h.From) {
+			return nil, fmt.Errorf("halt %s: until must be after from", h.Symbol)
+		}
+		sym := strings.ToUpper(h.Symbol)
+		b.halts[sym] = append(b.halts[sym], h)
+	}
+	return b, nil
+}
+
+// Registry assigns calendars to symbols: the calendar file's symbols
+// first, then fx for ISO currency pairs and crypto for everything else.
+type Registry struct {
+	path string
+	book atomic.Pointer[book]
+	mod  time.Time // of the loaded file
+}
+
+// Default returns the built-in calendars.
+func Default() *Registry {
+	b, err := newBook(Config{})
+	if err != nil {
+		panic(err) // the built-ins are valid and their zones embedded
+	}
+	r := &Registry{}
+	r.book.Store(b)
+	return r
+}
+
+// Load reads the calendar file.
+func Load(path string) (*Registry, error) {
+	r := &Registry{path: path}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Registry) reload() error {
+	fi, err := os.Stat(r.path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("calendar file %s: %w", r.path, err)
+	}
+	b, err := newBook(cfg)
+	if err != nil {
+		return fmt.Errorf("calendar file %s: %w", r.path, err)
+	}
+	r.book.Store(b)
+	r.mod = fi.ModTime()
+	return nil
+}
+
+// Run reloads the calendar file whenever it changes, checking every
+// interval, so halts and holidays apply without a restart. A file that
+// fails to load is logged and the previous calendars stay in effect.
+func (r *Registry) Run(ctx context.Context, every time.Duration) {
+	if r.path == "" {
+		return
+	}
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			fi, err := os.Stat(r.path)
+			if err != nil || fi.ModTime().Equal(r.mod) {
+				continue
+			}
+			if err := r.reload(); err != nil {
+				log.Printf("calendar: %v", err)
+				r.mod = fi.ModTime() // don't retry until it changes again
+				continue
+			}
+			log.Printf("calendar: reloaded %s", r.path)
+		}
+	}
+}
+
+// Calendar returns the calendar symbol trades on.
+func (r *Registry) Calendar(symbol string) *Calendar {
+	b := r.book.Load()
+	symbol = strings.ToUpper(symbol)
+	if c := b.symbols[symbol]; c != nil {
+		return c
+	}
+	if _, _, ok := common.SplitFX(symbol); ok {
+		return b.calendars["fx"]
+	}
+	return b.calendars["crypto"]
+}
+
+// Status returns symbol's market state at t: halted, closed by its
+// calendar, or open.
+func (r *Registry) Status(symbol string, t time.Time) Status {
+	for _, h := range r.book.Load().halts[strings.ToUpper(symbol)] {
+		if !t.Before(h.From) && (h.Until.IsZero() || t.Before(h.Until)) {
+			return Status{State: Halted, Reason: h.Reason, Until: h.Until}
+		}
+	}
+	c := r.Calendar(symbol)
+	if reason := c.closed(t); reason != "" {
+		return Status{State: Closed, Reason: reason, Until: c.NextOpen(t)}
+	}
+	return Status{State: Open}
+}
@@ -0,0 +1,34 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefault(t *testing.T) {
+	r := Default()
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sunday := time.Date(2026, 1, 4, 17, 0, 0, 0, ny)
+
+	tests := []struct {
+		symbol string
+		at     time.Time
+		state  State
+		until  time.Time
+	}{
+		{"EURUSD", time.Date(2026, 1, 7, 12, 0, 0, 0, ny), Open, time.Time{}},
+		{"EURUSD", time.Date(2026, 1, 3, 12, 0, 0, 0, ny), Closed, sunday},
+		{"EURUSD", sunday.Add(-time.Minute), Closed, sunday},
+		{"EURUSD", sunday, Open, time.Time{}},
+		{"BTCUSDT", time.Date(2026, 1, 3, 12, 0, 0, 0, ny), Open, time.Time{}},
+	}
+	for _, tt := range tests {
+		st := r.Status(tt.symbol, tt.at)
+		if st.State != tt.state || !st.Until.Equal(tt.until) {
+			t.Errorf("%s at %v: %v until %v, want %v until %v", tt.symbol, tt.at, st.State, st.Until, tt.state, tt.until)
+		}
+	}
+}
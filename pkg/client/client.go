@@ -0,0 +1,143 @@
+// path: pkg/client/client.go
+// Package client is the Go SDK for the price engine. It wraps the generated
+// PriceStreamClient with credentials, reconnects with backoff, resubscribes,
+// backfills missed candles from history and drops duplicate updates.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// Reconnect backoff defaults.
+const (
+	DefaultMinBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// Client is a connection to a price engine. It is safe for concurrent use.
+type Client struct {
+	conn *grpc.ClientConn
+	api  pricev1.PriceStreamClient
+
+	// closed is cancelled with ErrClosed by Close
+	closed context.Context
+	close  context.CancelCauseFunc
+
+	tls        *tls.Config
+	apiKey     string
+	token      string
+	dialOpts   []grpc.DialOption
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// ErrClosed is returned by subscriptions ended by Close.
+var ErrClosed = errors.New("client: closed")
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithTLS connects over TLS; set Certificates for mTLS. Without it the
+// client speaks plaintext HTTP/2.
+func WithTLS(cfg *tls.Config) Option {
+	return func(c *Client) { c.tls = cfg }
+}
+
+// WithAPIKey authenticates every call with an x-api-key header.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithToken authenticates every call with a JWT bearer token.
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithDialOptions adds raw gRPC dial options.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(c *Client) { c.dialOpts = append(c.dialOpts, opts...) }
+}
+
+// WithBackoff sets the reconnect delay range: the delay doubles from lo up
+// to hi after each failed attempt, with full jitter.
+func WithBackoff(lo, hi time.Duration) Option {
+	return func(c *Client) { c.minBackoff, c.maxBackoff = lo, hi }
+}
+
+// New creates a client for target (host:port). It does not connect until
+// the first call.
+func New(target string, opts ...Option) (*Client, error) {
+	c := &Client{minBackoff: DefaultMinBackoff, maxBackoff: DefaultMaxBackoff}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.minBackoff <= 0 || c.maxBackoff < c.minBackoff {
+		return nil, errors.New("client: backoff must be > 0 with max >= min")
+	}
+	creds := insecure.NewCredentials()
+	if c.tls != nil {
+		creds = credentials.NewTLS(c.tls)
+	}
+	dial := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if c.apiKey != "" || c.token != "" {
+		dial = append(dial, grpc.WithPerRPCCredentials(callCreds{apiKey: c.apiKey, token: c.token, secure: c.tls != nil}))
+	}
+	conn, err := grpc.NewClient(target, append(dial, c.dialOpts...)...)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	c.api = pricev1.NewPriceStreamClient(conn)
+	c.closed, c.close = context.WithCancelCause(context.Background())
+	return c, nil
+}
+
+// API returns the generated client for calls the SDK does not wrap.
+func (c *Client) API() pricev1.PriceStreamClient { return c.api }
+
+// Close closes the connection, ending all subscriptions with ErrClosed.
+func (c *Client) Close() error {
+	c.close(ErrClosed)
+	return c.conn.Close()
+}
+
+// bind returns a context that ends with ctx or when the client is closed,
+// with ErrClosed as its cause.
+func (c *Client) bind(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(c.closed, func() { cancel(ErrClosed) })
+	return ctx, func() {
+		stop()
+		cancel(nil)
+	}
+}
+
+// callCreds sends the API key or bearer token the engine's auth expects.
+type callCreds struct {
+	apiKey, token string
+	secure        bool
+}
+
+func (cc callCreds) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	md := make(map[string]string, 2)
+	if cc.apiKey != "" {
+		md["x-api-key"] = cc.apiKey
+	}
+	if cc.token != "" {
+		md["authorization"] = "Bearer " + cc.token
+	}
+	return md, nil
+}
+
+// RequireTransportSecurity allows credentials over plaintext only when the
+// client was not configured for TLS, e.g. behind a TLS-terminating sidecar.
+func (cc callCreds) RequireTransportSecurity() bool { return cc.secure }
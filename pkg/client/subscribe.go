@@ -0,0 +1,286 @@
+// path: pkg/client/subscribe.go
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// DefaultHeartbeat is the heartbeat interval requested when the
+// SubscribeRequest leaves it unset. A stream silent for three intervals is
+// considered dead and reconnected.
+const DefaultHeartbeat = 15 * time.Second
+
+var errStalled = errors.New("client: no message for three heartbeat intervals")
+
+// Handler receives a subscription's events. Callbacks run one at a time on
+// the subscribing goroutine; nil callbacks are skipped. A callback that
+// blocks makes the engine cut the stream off, after which the client
+// resubscribes and backfills what it missed.
+type Handler struct {
+	// OnCandle gets each window's updates in order, without duplicates:
+	// partials no newer than one already delivered and anything for a
+	// window whose final candle was delivered are dropped. Candles
+	// backfilled after a reconnect are final and carry all fields.
+	OnCandle    func(*pricev1.Candle)
+	OnStatus    func(*pricev1.StreamStatus)
+	OnHeartbeat func(*pricev1.Heartbeat)
+	// OnError gets errors the subscription recovers from: the error that
+	// ended a stream before reconnecting, failed backfills, whose candles
+	// are then skipped, and a *GapError before a time-bar final candle that
+	// does not follow the previous one.
+	OnError func(error)
+}
+
+// GapError reports time-bar windows between two final candles that were
+// neither streamed nor backfilled: [FromMs, ToMs) in window starts. A
+// window without trades has no candle, so a quiet market leaves gaps too.
+type GapError struct {
+	Symbol string
+	FromMs int64 // window end of the previous final candle
+	ToMs   int64 // window start of the next one
+}
+
+func (e *GapError) Error() string {
+	return fmt.Sprintf("client: %s: no final candle for windows %d to %d", e.Symbol, e.FromMs, e.ToMs)
+}
+
+// Subscribe streams req to h, reconnecting whenever the stream breaks,
+// until ctx ends, the client is closed or the engine refuses the
+// subscription for good (invalid request, authentication, entitlements or
+// quota). It returns that error, ctx's or ErrClosed.
+//
+// After a reconnect, time-bar subscriptions fetch the windows closed in
+// the meantime with GetCandles before live candles resume.
+func (c *Client) Subscribe(ctx context.Context, req *pricev1.SubscribeRequest, h Handler) error {
+	if req.GetSymbol() == "" {
+		return errors.New("client: symbol required")
+	}
+	req = proto.Clone(req).(*pricev1.SubscribeRequest)
+	if req.GetHeartbeatIntervalMs() == 0 {
+		req.HeartbeatIntervalMs = DefaultHeartbeat.Milliseconds()
+	}
+	ctx, cancel := c.bind(ctx)
+	defer cancel()
+	s := &subscription{c: c, req: req, h: h}
+	return s.run(ctx)
+}
+
+type subscription struct {
+	c   *Client
+	req *pricev1.SubscribeRequest
+	h   Handler
+
+	seen   dedup
+	resync bool // a stream ended; backfill on the next stream's first message
+}
+
+func (s *subscription) run(ctx context.Context) error {
+	backoff := s.c.minBackoff
+	for {
+		delivered, err := s.stream(ctx)
+		if ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+		if permanent(err) {
+			return err
+		}
+		s.resync = true
+		if delivered {
+			backoff = s.c.minBackoff
+		}
+		delay := rand.N(backoff)
+		if delivered && status.Code(err) == codes.Aborted {
+			// the engine asked for a resubscription: no need to wait
+			delay = 0
+		}
+		s.error(err)
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-time.After(delay):
+		}
+		backoff = min(backoff*2, s.c.maxBackoff)
+	}
+}
+
+// stream runs one StreamAggregateEvents call and reports whether it delivered
+// anything before it ended.
+func (s *subscription) stream(ctx context.Context) (delivered bool, err error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stall := 3 * time.Duration(s.req.GetHeartbeatIntervalMs()) * time.Millisecond
+	timer := time.AfterFunc(stall, func() { cancel(errStalled) })
+	defer timer.Stop()
+
+	st, err := s.c.api.StreamAggregateEvents(ctx, s.req)
+	if err != nil {
+		return false, err
+	}
+	for {
+		ev, err := st.Recv()
+		if err != nil {
+			if cause := context.Cause(ctx); errors.Is(cause, errStalled) {
+				return delivered, cause
+			}
+			return delivered, err
+		}
+		timer.Reset(stall)
+		if s.resync {
+			// the engine subscribes before it sends anything, so history
+			// now covers every window the new stream will not deliver
+			s.resync = false
+			s.backfill(ctx)
+		}
+		delivered = true
+		switch e := ev.GetEvent().(type) {
+		case *pricev1.AggregateEvent_Candle:
+			s.candle(e.Candle)
+		case *pricev1.AggregateEvent_Status:
+			if s.h.OnStatus != nil {
+				s.h.OnStatus(e.Status)
+			}
+		case *pricev1.AggregateEvent_Heartbeat:
+			if s.h.OnHeartbeat != nil {
+				s.h.OnHeartbeat(e.Heartbeat)
+			}
+		}
+	}
+}
+
+// backfill delivers the closed windows after the last final candle. Only
+// time bars at the engine interval have history; activity bars restart
+// with the new stream.
+func (s *subscription) backfill(ctx context.Context) {
+	if s.req.GetBarType() != pricev1.BarType_BAR_TIME || !s.seen.hasFinal {
+		return
+	}
+	resp, err := s.c.api.GetCandles(ctx, &pricev1.CandlesRequest{
+		Symbol: s.req.GetSymbol(),
+		FromMs: s.seen.final + 1,
+	})
+	if err != nil {
+		s.error(err)
+		return
+	}
+	for _, cd := range resp.GetCandles() {
+		s.candle(cd)
+	}
+}
+
+func (s *subscription) candle(c *pricev1.Candle) {
+	if s.req.GetFinalOnly() && !c.GetIsFinal() {
+		return
+	}
+	prev, had := s.seen.end, s.seen.hasFinal
+	if !s.seen.keep(c) {
+		return
+	}
+	// activity bars span their trades and are not contiguous
+	if had && c.GetIsFinal() && s.req.GetBarType() == pricev1.BarType_BAR_TIME && c.GetWindowStartMs() > prev {
+		s.error(&GapError{Symbol: c.GetSymbol(), FromMs: prev, ToMs: c.GetWindowStartMs()})
+	}
+	if s.h.OnCandle != nil {
+		s.h.OnCandle(c)
+	}
+}
+
+func (s *subscription) error(err error) {
+	if s.h.OnError != nil && err != nil {
+		s.h.OnError(err)
+	}
+}
+
+// permanent reports whether err means resubscribing cannot succeed.
+func permanent(err error) bool {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.PermissionDenied, codes.Unauthenticated,
+		codes.ResourceExhausted, codes.FailedPrecondition, codes.Unimplemented:
+		return true
+	}
+	return false
+}
+
+// dedup tracks what the handler has been given for one symbol.
+type dedup struct {
+	hasFinal bool
+	final    int64  // window start of the last final candle
+	end      int64  // its window end
+	start    int64  // window start of the last partial
+	rev      uint32 // its revision
+}
+
+// keep reports whether c is news and records it.
+func (d *dedup) keep(c *pricev1.Candle) bool {
+	start := c.GetWindowStartMs()
+	if d.hasFinal && start <= d.final {
+		return false
+	}
+	if c.GetIsFinal() {
+		d.hasFinal, d.final, d.end = true, start, c.GetWindowEndMs()
+		return true
+	}
+	if start < d.start || (start == d.start && c.GetRevision() <= d.rev) {
+		return false
+	}
+	d.start, d.rev = start, c.GetRevision()
+	return true
+}
+
+// Event is one message of a Watch subscription; exactly one field is set.
+type Event struct {
+	Candle    *pricev1.Candle
+	Status    *pricev1.StreamStatus
+	Heartbeat *pricev1.Heartbeat
+	Err       error // recovered from, see Handler.OnError
+}
+
+// Subscription is a Watch subscription.
+type Subscription struct {
+	// C delivers the events; it is closed when the subscription ends.
+	C <-chan Event
+
+	done chan struct{}
+	err  error
+}
+
+// Err waits for the subscription to end and returns why (see Subscribe).
+func (s *Subscription) Err() error {
+	<-s.done
+	return s.err
+}
+
+// Watch is Subscribe with a channel instead of callbacks. Cancel ctx or
+// close the client to stop it; events not received by then are dropped.
+func (c *Client) Watch(ctx context.Context, req *pricev1.SubscribeRequest) *Subscription {
+	ctx, cancel := c.bind(ctx)
+	ch := make(chan Event, 64)
+	sub := &Subscription{C: ch, done: make(chan struct{})}
+	send := func(ev Event) {
+		select {
+		case ch <- ev:
+		case <-ctx.Done():
+		}
+	}
+	go func() {
+		defer close(sub.done)
+		defer close(ch)
+		defer cancel()
+		sub.err = c.Subscribe(ctx, req, Handler{
+			OnCandle:    func(c *pricev1.Candle) { send(Event{Candle: c}) },
+			OnStatus:    func(st *pricev1.StreamStatus) { send(Event{Status: st}) },
+			OnHeartbeat: func(hb *pricev1.Heartbeat) { send(Event{Heartbeat: hb}) },
+			OnError:     func(err error) { send(Event{Err: err}) },
+		})
+	}()
+	return sub
+}
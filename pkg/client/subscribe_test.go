@@ -0,0 +1,295 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// script is what one StreamAggregateEvents call sends: events, then err.
+// A nil err keeps the stream open until the client leaves.
+type script struct {
+	events []*pricev1.AggregateEvent
+	err    error
+}
+
+// fakeEngine plays one script per StreamAggregateEvents call; calls past
+// the last script stay open without sending anything. GetCandles serves
+// history.
+type fakeEngine struct {
+	pricev1.UnimplementedPriceStreamServer
+	scripts []script
+	history []*pricev1.Candle
+
+	mu        sync.Mutex
+	calls     int
+	backfills []int64 // from_ms of each GetCandles call
+}
+
+func (e *fakeEngine) StreamAggregateEvents(req *pricev1.SubscribeRequest, stream pricev1.PriceStream_StreamAggregateEventsServer) error {
+	e.mu.Lock()
+	n := e.calls
+	e.calls++
+	e.mu.Unlock()
+	if n >= len(e.scripts) {
+		<-stream.Context().Done()
+		return stream.Context().Err()
+	}
+	sc := e.scripts[n]
+	for _, ev := range sc.events {
+		if err := stream.Send(ev); err != nil {
+			return err
+		}
+	}
+	if sc.err == nil {
+		<-stream.Context().Done()
+		return stream.Context().Err()
+	}
+	return sc.err
+}
+
+// streams returns the number of StreamAggregateEvents calls and the
+// from_ms of each GetCandles call so far.
+func (e *fakeEngine) streams() (int, []int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.calls, slices.Clone(e.backfills)
+}
+
+func (e *fakeEngine) GetCandles(_ context.Context, req *pricev1.CandlesRequest) (*pricev1.CandlesResponse, error) {
+	e.mu.Lock()
+	e.backfills = append(e.backfills, req.GetFromMs())
+	e.mu.Unlock()
+	var out []*pricev1.Candle
+	for _, c := range e.history {
+		if c.GetWindowStartMs() >= req.GetFromMs() {
+			out = append(out, c)
+		}
+	}
+	return &pricev1.CandlesResponse{Candles: out}, nil
+}
+
+// dial serves e over an in-memory listener and returns a client for it.
+func dial(t *testing.T, e *fakeEngine, opts ...Option) *Client {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	pricev1.RegisterPriceStreamServer(srv, e)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	dialer := func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }
+	c, err := New("passthrough:///bufnet", append(opts, WithDialOptions(grpc.WithContextDialer(dialer)))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// watch subscribes to BTCUSDT and returns the first n events.
+func watch(t *testing.T, c *Client, n int) []Event {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	sub := c.Watch(ctx, &pricev1.SubscribeRequest{Symbol: "BTCUSDT"})
+	var out []Event
+	for ev := range sub.C {
+		out = append(out, ev)
+		if len(out) == n {
+			return out
+		}
+	}
+	t.Fatalf("subscription ended after %d events: %v", len(out), sub.Err())
+	return nil
+}
+
+func candle(start int64, final bool, rev uint32) *pricev1.Candle {
+	return &pricev1.Candle{Symbol: "BTCUSDT", WindowStartMs: start, WindowEndMs: start + 1000, IsFinal: final, Revision: rev}
+}
+
+func candleEvent(start int64, final bool, rev uint32) *pricev1.AggregateEvent {
+	return &pricev1.AggregateEvent{Event: &pricev1.AggregateEvent_Candle{Candle: candle(start, final, rev)}}
+}
+
+func statusEvent(kind pricev1.StreamStatusKind) *pricev1.AggregateEvent {
+	return &pricev1.AggregateEvent{Event: &pricev1.AggregateEvent_Status{Status: &pricev1.StreamStatus{Kind: kind}}}
+}
+
+var (
+	open        = statusEvent(pricev1.StreamStatusKind_SK_MARKET_OPEN)
+	resubscribe = statusEvent(pricev1.StreamStatusKind_SK_RESUBSCRIBE_REQUIRED)
+	unavailable = status.Error(codes.Unavailable, "engine restarting")
+)
+
+func TestReconnect(t *testing.T) {
+	e := &fakeEngine{scripts: []script{
+		{err: unavailable},
+		{err: unavailable},
+		{events: []*pricev1.AggregateEvent{open, candleEvent(0, true, 0)}},
+	}}
+	c := dial(t, e, WithBackoff(time.Millisecond, 4*time.Millisecond))
+
+	evs := watch(t, c, 4)
+	for i, ev := range evs[:2] {
+		if status.Code(ev.Err) != codes.Unavailable {
+			t.Errorf("event %d: %+v, want the Unavailable error", i, ev)
+		}
+	}
+	if evs[2].Status.GetKind() != pricev1.StreamStatusKind_SK_MARKET_OPEN || evs[3].Candle.GetWindowStartMs() != 0 {
+		t.Errorf("events after reconnecting: %+v", evs[2:])
+	}
+	if n, _ := e.streams(); n != 3 {
+		t.Errorf("%d streams, want 3", n)
+	}
+}
+
+func TestPermanentError(t *testing.T) {
+	e := &fakeEngine{scripts: []script{{err: status.Error(codes.PermissionDenied, "symbol not entitled")}}}
+	c := dial(t, e, WithBackoff(time.Millisecond, time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := c.Subscribe(ctx, &pricev1.SubscribeRequest{Symbol: "BTCUSDT"}, Handler{})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Subscribe = %v, want PermissionDenied", err)
+	}
+	if n, _ := e.streams(); n != 1 {
+		t.Errorf("%d streams, want 1", n)
+	}
+}
+
+func TestResubscribeBackfill(t *testing.T) {
+	e := &fakeEngine{
+		scripts: []script{
+			{
+				events: []*pricev1.AggregateEvent{open, candleEvent(0, true, 0), resubscribe},
+				err:    status.Error(codes.Aborted, "subscriber fell behind"),
+			},
+			{events: []*pricev1.AggregateEvent{open, candleEvent(2000, false, 1), candleEvent(2000, true, 2)}},
+		},
+		// 0 is delivered already; 1000 was missed while resubscribing
+		history: []*pricev1.Candle{candle(0, true, 0), candle(1000, true, 0)},
+	}
+	// a resubscription requested by the engine does not wait for the backoff
+	c := dial(t, e, WithBackoff(time.Hour, time.Hour))
+
+	var got []string
+	for _, ev := range watch(t, c, 8) {
+		switch {
+		case ev.Candle != nil:
+			got = append(got, fmt.Sprintf("candle %d final=%v", ev.Candle.GetWindowStartMs(), ev.Candle.GetIsFinal()))
+		case ev.Status != nil:
+			got = append(got, ev.Status.GetKind().String())
+		case ev.Err != nil:
+			got = append(got, "error "+status.Code(ev.Err).String())
+		}
+	}
+	want := []string{
+		"SK_MARKET_OPEN",
+		"candle 0 final=true",
+		"SK_RESUBSCRIBE_REQUIRED",
+		"error Aborted",
+		"candle 1000 final=true", // backfilled, without a gap
+		"SK_MARKET_OPEN",
+		"candle 2000 final=false",
+		"candle 2000 final=true",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("events\n%q\nwant\n%q", got, want)
+	}
+	if _, from := e.streams(); !slices.Equal(from, []int64{1}) {
+		t.Errorf("GetCandles from %v, want [1]", from)
+	}
+}
+
+func TestDedup(t *testing.T) {
+	e := &fakeEngine{scripts: []script{{events: []*pricev1.AggregateEvent{
+		candleEvent(0, false, 1),
+		candleEvent(0, false, 1), // duplicate
+		candleEvent(0, false, 2),
+		candleEvent(0, false, 1), // older
+		candleEvent(0, true, 3),
+		candleEvent(0, false, 4), // window already final
+		candleEvent(0, true, 3),  // final again
+		candleEvent(1000, false, 1),
+	}}}}
+	c := dial(t, e)
+
+	var got []uint32
+	for _, ev := range watch(t, c, 4) {
+		if ev.Candle == nil || ev.Candle.GetWindowStartMs() != 0 && ev.Candle.GetWindowStartMs() != 1000 {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+		got = append(got, ev.Candle.GetRevision())
+	}
+	if !slices.Equal(got, []uint32{1, 2, 3, 1}) {
+		t.Errorf("revisions %v, want [1 2 3 1]", got)
+	}
+}
+
+func TestGap(t *testing.T) {
+	e := &fakeEngine{scripts: []script{{events: []*pricev1.AggregateEvent{
+		candleEvent(0, true, 0),
+		candleEvent(1000, false, 1), // partials do not close a gap
+		candleEvent(3000, true, 0),
+		candleEvent(4000, true, 0),
+	}}}}
+	c := dial(t, e)
+
+	evs := watch(t, c, 5)
+	var gap *GapError
+	if !errors.As(evs[2].Err, &gap) {
+		t.Fatalf("event 2: %+v, want a GapError", evs[2])
+	}
+	if *gap != (GapError{Symbol: "BTCUSDT", FromMs: 1000, ToMs: 3000}) {
+		t.Errorf("gap %+v, want windows 1000 to 3000", *gap)
+	}
+	if evs[3].Candle.GetWindowStartMs() != 3000 || evs[4].Candle.GetWindowStartMs() != 4000 {
+		t.Errorf("events after the gap: %+v", evs[3:])
+	}
+}
+
+func TestCloseDuringWatch(t *testing.T) {
+	e := &fakeEngine{scripts: []script{{events: []*pricev1.AggregateEvent{open}}}}
+	c := dial(t, e, WithBackoff(time.Millisecond, time.Millisecond))
+	sub := c.Watch(context.Background(), &pricev1.SubscribeRequest{Symbol: "BTCUSDT"})
+	if ev := <-sub.C; ev.Status.GetKind() != pricev1.StreamStatusKind_SK_MARKET_OPEN {
+		t.Fatalf("first event %+v", ev)
+	}
+
+	c.Close()
+	timeout := time.After(5 * time.Second)
+	for open := true; open; {
+		select {
+		case ev, ok := <-sub.C:
+			if ok && ev.Err != nil && !errors.Is(ev.Err, ErrClosed) {
+				t.Errorf("retried after Close: %v", ev.Err)
+			}
+			open = ok
+		case <-timeout:
+			t.Fatal("subscription still running after Close")
+		}
+	}
+	if err := sub.Err(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Err = %v, want ErrClosed", err)
+	}
+	if n, _ := e.streams(); n != 1 {
+		t.Errorf("%d streams, want 1", n)
+	}
+	// subscribing on a closed client ends at once
+	if err := c.Subscribe(context.Background(), &pricev1.SubscribeRequest{Symbol: "BTCUSDT"}, Handler{}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Subscribe after Close = %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,26 @@
+// path: pkg/common/symbols.go
+package common
+
+import "strings"
+
+// Quote assets recognised at the end of compact crypto symbols ("BTCUSDT").
+// Longer codes first so "FDUSD" wins over "USD".
+var cryptoQuotes = []string{
+	"FDUSD", "USDT", "USDC", "BUSD", "TUSD",
+	"BTC", "ETH", "BNB", "EUR", "USD", "GBP", "TRY", "JPY", "BRL",
+}
+
+// SplitSymbol splits a compact symbol into base and quote assets.
+// ISO FX pairs are tried first (SplitFX), then known crypto quote suffixes.
+func SplitSymbol(sym string) (string, string, bool) {
+	sym = strings.ToUpper(sym)
+	if b, q, ok := SplitFX(sym); ok {
+		return b, q, true
+	}
+	for _, q := range cryptoQuotes {
+		if len(sym) > len(q) && strings.HasSuffix(sym, q) {
+			return sym[:len(sym)-len(q)], q, true
+		}
+	}
+	return "", "", false
+}
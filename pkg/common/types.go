@@ -9,4 +9,45 @@ type Trade struct {
 	Qty      float64
 	Exchange string
 	TS       time.Time
+	TradeID  string // venue trade/tick ID, used to drop duplicates; empty if the venue has none
+	Side     Side   // aggressor side; SideUnknown for quotes and venues that do not report it
+
+	// Synthetic trades are derived from other instruments (cross rates);
+	// Legs records the source prices they were computed from.
+	Synthetic bool
+	Legs      []SourceLeg
+}
+
+// Side is the aggressor (taker) side of a trade.
+type Side int8
+
+const (
+	SideUnknown Side = iota
+	SideBuy          // buyer lifted the offer
+	SideSell         // seller hit the bid
+)
+
+// SourceLeg is one input of a synthetic price: the leg's last price and
+// whether it was inverted (1/price) to line up the currencies.
+type SourceLeg struct {
+	Symbol   string
+	Exchange string
+	Price    float64
+	Inverted bool
+	TS       time.Time
+}
+
+// Quote is a top-of-book (L1) update: best bid/ask and their sizes.
+type Quote struct {
+	Symbol   string
+	Exchange string
+	Bid      float64
+	BidQty   float64
+	Ask      float64
+	AskQty   float64
+	TS       time.Time
 }
+
+func (q Quote) Spread() float64 { return q.Ask - q.Bid }
+
+func (q Quote) Mid() float64 { return (q.Bid + q.Ask) / 2 }
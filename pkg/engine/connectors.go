@@ -0,0 +1,26 @@
+// path: pkg/engine/connectors.go
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/binaridigital/price-engine/pkg/ingest"
+)
+
+// Connectors are the names NewConnector knows.
+var Connectors = []string{"binance", "tradermade", "twelvedata"}
+
+// NewConnector returns a new built-in trade connector by name. Each call
+// opens its own connections, so calling it twice gives a hot standby.
+func NewConnector(name string) (ingest.Connector, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "binance":
+		return ingest.NewBinance(), nil
+	case "tradermade":
+		return ingest.NewTraderMade(), nil
+	case "twelvedata":
+		return ingest.NewTwelveData(), nil
+	}
+	return nil, fmt.Errorf("unknown connector: %s", name)
+}
@@ -0,0 +1,493 @@
+// path: pkg/engine/engine.go
+// Package engine runs the price engine: connectors, trade merging,
+// deduplication and filtering, aggregation into candles, and the API and
+// sinks the results are published to. cmd/aggregator is a flag front end
+// to it; other Go services can embed it the same way.
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/aggregate"
+	"github.com/binaridigital/price-engine/pkg/common"
+	"github.com/binaridigital/price-engine/pkg/fixing"
+	"github.com/binaridigital/price-engine/pkg/grpcapi"
+	"github.com/binaridigital/price-engine/pkg/indicator"
+	"github.com/binaridigital/price-engine/pkg/ingest"
+	"github.com/binaridigital/price-engine/pkg/orderbook"
+	"github.com/binaridigital/price-engine/pkg/profile"
+	"github.com/binaridigital/price-engine/pkg/synth"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// Engine is a configured pipeline. Create it with New, run it with Start
+// and shut it down with Stop.
+type Engine struct {
+	cfg     config
+	symbols []string
+	routes  []synth.Route
+
+	monitor    *ingest.Monitor
+	hub        *grpcapi.Hub
+	server     *grpcapi.Server
+	trades     *grpcapi.Topic[common.Trade]
+	quotes     *grpcapi.Topic[common.Quote]
+	books      *grpcapi.Topic[orderbook.Snapshot]
+	profiles   *grpcapi.Topic[*pricev1.VolumeProfile]
+	fixings    *grpcapi.Topic[*pricev1.Fixing]
+	indicators *grpcapi.Topic[*pricev1.IndicatorUpdate]
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+	cancel  context.CancelFunc
+	done    chan struct{}
+	wg      sync.WaitGroup // goroutines Stop waits for
+	errOnce sync.Once
+	err     error
+
+	indicatorDrops atomic.Uint64
+	alertDrops     atomic.Uint64
+}
+
+// Stats are cumulative pipeline counters.
+type Stats struct {
+	IndicatorDrops uint64 // final candles skipped because indicators fell behind
+	AlertDrops     uint64 // final candles skipped because alert evaluation fell behind
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("indicator_drops=%d alert_drops=%d", s.IndicatorDrops, s.AlertDrops)
+}
+
+// New validates the options and builds the engine without starting it.
+func New(opts ...Option) (*Engine, error) {
+	cfg := config{
+		interval:    time.Second,
+		stale:       ingest.DefaultStaleConfig,
+		dedup:       ingest.DefaultDedupWindow,
+		depthLevels: ingest.DefaultBookLevels,
+		meterFlush:  time.Minute,
+		history:     grpcapi.DefaultHistory,
+	}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	e := &Engine{cfg: cfg, done: make(chan struct{})}
+	e.symbols = trimAll(cfg.symbols, false)
+	if len(e.symbols) == 0 {
+		return nil, errors.New("engine: no symbols configured")
+	}
+	if len(cfg.conns) == 0 {
+		return nil, errors.New("engine: no connectors configured")
+	}
+	if cfg.interval <= 0 {
+		return nil, errors.New("engine: interval must be > 0")
+	}
+	if cfg.profiles != nil {
+		if err := cfg.profiles.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err := aggregate.ValidateOptions(cfg.aggOpts...); err != nil {
+		return nil, err
+	}
+	for _, s := range trimAll(cfg.synthetic, false) {
+		r, err := synth.Resolve(s, e.symbols)
+		if err != nil {
+			return nil, fmt.Errorf("synthetic: %w", err)
+		}
+		e.routes = append(e.routes, r)
+	}
+
+	e.monitor = ingest.NewMonitor(ingest.WithStaleConfig(cfg.stale))
+	for i, c := range append(cfg.conns[:len(cfg.conns):len(cfg.conns)], cfg.standby...) {
+		if mc, ok := c.(ingest.Monitorable); ok {
+			replica := 0
+			if i >= len(cfg.conns) {
+				replica = 1
+			}
+			mc.SetMonitor(e.monitor, replica)
+		}
+	}
+
+	e.hub = grpcapi.NewHub(grpcapi.WithHistory(cfg.history))
+	e.trades = grpcapi.NewTopic[common.Trade](4096)
+	srvOpts := []grpcapi.ServerOption{grpcapi.WithMonitor(e.monitor), grpcapi.WithTrades(e.trades)}
+	if len(cfg.quoteSymbols) > 0 {
+		e.quotes = grpcapi.NewTopic[common.Quote](256)
+		srvOpts = append(srvOpts, grpcapi.WithQuotes(e.quotes))
+	}
+	if len(cfg.depthSymbols) > 0 {
+		e.books = grpcapi.NewTopic[orderbook.Snapshot](64)
+		srvOpts = append(srvOpts, grpcapi.WithOrderBooks(e.books))
+	}
+	if cfg.profiles != nil {
+		e.profiles = grpcapi.NewTopic[*pricev1.VolumeProfile](16)
+		srvOpts = append(srvOpts, grpcapi.WithProfiles(e.profiles, cfg.profiles.Interval))
+	}
+	if len(cfg.schedules) > 0 {
+		e.fixings = grpcapi.NewFixingTopic(16)
+		srvOpts = append(srvOpts, grpcapi.WithFixings(e.fixings))
+	}
+	if !cfg.indicators.Empty() {
+		e.indicators = grpcapi.NewTopic[*pricev1.IndicatorUpdate](16)
+		srvOpts = append(srvOpts, grpcapi.WithIndicators(e.indicators))
+	}
+	if cfg.alerts != nil {
+		srvOpts = append(srvOpts, grpcapi.WithAlerts(cfg.alerts))
+	}
+	if cfg.meter != nil {
+		srvOpts = append(srvOpts, grpcapi.WithMeter(cfg.meter))
+	}
+	if cfg.calendars != nil {
+		srvOpts = append(srvOpts, grpcapi.WithCalendars(cfg.calendars))
+	}
+	e.server = grpcapi.NewServer(e.hub, cfg.interval, append(srvOpts, cfg.srvOpts...)...)
+	return e, nil
+}
+
+// Hub returns the hub candles are published to.
+func (e *Engine) Hub() *grpcapi.Hub { return e.hub }
+
+// Monitor returns the connector health monitor.
+func (e *Engine) Monitor() *ingest.Monitor { return e.monitor }
+
+// Server returns the API, e.g. to register on a gRPC server of the
+// embedding service instead of using WithAddr.
+func (e *Engine) Server() *grpcapi.Server { return e.server }
+
+// Stats returns the pipeline counters.
+func (e *Engine) Stats() Stats {
+	return Stats{IndicatorDrops: e.indicatorDrops.Load(), AlertDrops: e.alertDrops.Load()}
+}
+
+// Start listens on the WithAddr address, if any, and starts the pipeline.
+// The engine runs until ctx ends, Stop is called or serving fails.
+func (e *Engine) Start(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.started || e.stopped {
+		return errors.New("engine: already started")
+	}
+	var lis net.Listener
+	if e.cfg.addr != "" {
+		l, err := net.Listen("tcp", e.cfg.addr)
+		if err != nil {
+			return err
+		}
+		lis = l
+	}
+	e.started = true
+	ctx, e.cancel = context.WithCancel(ctx)
+	go func() {
+		<-ctx.Done()
+		close(e.done)
+	}()
+
+	candles := e.pipeline(ctx)
+	if lis != nil {
+		log.Printf("gRPC, gRPC-Web and Connect listening on %s", lis.Addr())
+		e.run(func() {
+			if err := grpcapi.ServeListener(ctx, lis, e.server, e.cfg.serveOpts...); err != nil {
+				e.fail(fmt.Errorf("grpc serve: %w", err))
+			}
+		})
+	}
+	e.run(func() { e.publish(ctx, candles) })
+	return nil
+}
+
+// Done is closed once the engine stops running; Stop then returns why.
+func (e *Engine) Done() <-chan struct{} { return e.done }
+
+// Stop stops the engine and waits for every goroutine it started: the API
+// shuts down, usage is saved, connectors close and the candles aggregated
+// so far reach the hub and the sinks. It then closes the sinks that are
+// io.Closers. It returns the error that stopped the engine, if any.
+func (e *Engine) Stop() error {
+	e.mu.Lock()
+	if e.stopped {
+		e.mu.Unlock()
+		e.wg.Wait()
+		return e.err
+	}
+	e.stopped = true
+	started := e.started
+	e.mu.Unlock()
+
+	if started {
+		e.cancel()
+		e.wg.Wait()
+	}
+	var closers []io.Closer
+	for _, s := range e.cfg.sinks {
+		if c, ok := s.(io.Closer); ok {
+			closers = append(closers, c)
+		}
+	}
+	for _, s := range []MessageSink{e.cfg.profileSink, e.cfg.quarantineSink, e.cfg.fixingSink} {
+		if c, ok := s.(io.Closer); ok {
+			closers = append(closers, c)
+		}
+	}
+	// in reverse, like deferred closes
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].Close(); err != nil {
+			log.Printf("engine: closing sink: %v", err)
+		}
+	}
+	return e.err
+}
+
+// run starts a goroutine Stop waits for.
+func (e *Engine) run(fn func()) {
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		fn()
+	}()
+}
+
+// fail stops the engine with err; the first error wins.
+func (e *Engine) fail(err error) {
+	e.errOnce.Do(func() { e.err = err })
+	e.cancel()
+}
+
+// pipeline starts ingestion and everything fed from the trade stream, and
+// returns the aggregated candles.
+func (e *Engine) pipeline(ctx context.Context) <-chan *pricev1.Candle {
+	cfg := &e.cfg
+
+	// Ingestion
+	var tradeChans []<-chan common.Trade
+	for _, c := range append(cfg.conns[:len(cfg.conns):len(cfg.conns)], cfg.standby...) {
+		for _, s := range e.symbols {
+			tc, ec := c.Start(ctx, s)
+			tradeChans = append(tradeChans, tc)
+			e.run(func() { logErrors("ingest", ec) })
+		}
+	}
+	for _, c := range cfg.standby {
+		log.Printf("%s: hot standby connection enabled", c.Name())
+	}
+
+	// Merge, dedup, filter
+	merged := ingest.MergeTrades(ctx, tradeChans...)
+	merged = ingest.NewDeduper(cfg.dedup).Run(ctx, merged)
+	if cfg.filter != nil {
+		filter := ingest.NewTickFilter(*cfg.filter)
+		var rejected <-chan ingest.Rejected
+		merged, rejected = filter.Run(ctx, merged)
+		e.run(func() {
+			t := time.NewTicker(time.Minute)
+			defer t.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-t.C:
+					if r := filter.Rejected(); len(r) > 0 {
+						log.Printf("tick filter rejected: %v", r)
+					}
+				}
+			}
+		})
+		// drained even without a sink, so the filter never blocks
+		e.run(func() {
+			drain(ctx, rejected, func(r ingest.Rejected) {
+				if cfg.quarantineSink != nil {
+					q := quarantined(r)
+					cfg.quarantineSink.Publish(quarantineKey(q), q, r.At)
+				}
+			})
+		})
+	}
+	if len(e.routes) > 0 {
+		for _, r := range e.routes {
+			log.Printf("synthetic %s", r)
+		}
+		merged = synth.Run(ctx, merged, e.routes, cfg.legAge)
+	}
+
+	aggOpts := append([]aggregate.Option{aggregate.WithStaleCheck(e.monitor.StaleSources)}, cfg.aggOpts...)
+	if cfg.calendars != nil {
+		e.run(func() { cfg.calendars.Run(ctx, 10*time.Second) })
+	}
+
+	// Top-of-book quotes: live stream + spread statistics in candles
+	if e.quotes != nil {
+		var quoteChans []<-chan common.Quote
+		for _, s := range trimAll(cfg.quoteSymbols, false) {
+			qc := ingest.NewBinanceQuotes()
+			qc.SetMonitor(e.monitor, 0)
+			ch, ec := qc.Start(ctx, s)
+			quoteChans = append(quoteChans, ch)
+			e.run(func() { logErrors("quote", ec) })
+		}
+		toAgg := make(chan common.Quote, 2048)
+		e.run(func() {
+			defer close(toAgg)
+			for q := range ingest.Merge(ctx, quoteChans...) {
+				e.quotes.Publish(q.Symbol, q)
+				select {
+				case toAgg <- q:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+		aggOpts = append(aggOpts, aggregate.WithQuotes(toAgg))
+	}
+
+	// Volume profile taps the same trade feed as the candles
+	if cfg.profiles != nil {
+		feeds := ingest.Tee(ctx, merged, 2)
+		merged = feeds[0]
+		profiles := profile.Run(ctx, feeds[1], *cfg.profiles)
+		e.run(func() {
+			drain(ctx, profiles, func(p *pricev1.VolumeProfile) {
+				e.profiles.Publish(p.Symbol, p)
+				if cfg.profileSink != nil {
+					cfg.profileSink.Publish(profileKey(p), p, time.UnixMilli(p.WindowEndMs))
+				}
+			})
+		})
+	}
+	// Fixings tap the trade feed for their scheduled windows
+	if len(cfg.schedules) > 0 {
+		feeds := ingest.Tee(ctx, merged, 2)
+		merged = feeds[0]
+		fixings := fixing.Run(ctx, feeds[1], cfg.schedules, cfg.fixingGrace)
+		e.run(func() {
+			drain(ctx, fixings, func(f *pricev1.Fixing) {
+				log.Printf("fixing %s %s = %g (%s, %d trades)", f.Name, f.Symbol, f.Value, f.Method, f.TradeCount)
+				e.fixings.Publish(f.Symbol, f)
+				if cfg.fixingSink != nil {
+					cfg.fixingSink.Publish(fixingKey(f), f, time.UnixMilli(f.WindowEndMs))
+				}
+			})
+		})
+	}
+	// Live trades feed tick/volume/dollar bars built per subscription
+	feeds := ingest.Tee(ctx, merged, 2)
+	merged = feeds[0]
+	e.run(func() {
+		for t := range feeds[1] {
+			e.trades.Publish(t.Symbol, t)
+		}
+	})
+
+	// Order books
+	for _, s := range trimAll(cfg.depthSymbols, true) {
+		depth := ingest.NewBinanceDepth(cfg.depthLevels)
+		depth.SetMonitor(e.monitor, 0)
+		bc, ec := depth.Start(ctx, s)
+		e.run(func() {
+			for snap := range bc {
+				e.books.Publish(snap.Symbol, snap)
+			}
+		})
+		e.run(func() { logErrors("depth", ec) })
+	}
+
+	if cfg.meter != nil {
+		e.run(func() { cfg.meter.Run(ctx, cfg.meterFlush) })
+	}
+	return aggregate.Run(ctx, merged, cfg.interval, aggOpts...)
+}
+
+// publish sends candles to the hub, indicators, alerts and sinks until the
+// aggregator closes candles, which it does when ctx ends: the candles
+// already aggregated still go out.
+func (e *Engine) publish(ctx context.Context, candles <-chan *pricev1.Candle) {
+	cfg := &e.cfg
+	var indicatorIn, alertIn chan *pricev1.Candle
+	if e.indicators != nil {
+		indicatorIn = make(chan *pricev1.Candle, 1024)
+		updates := indicator.Run(ctx, indicatorIn, cfg.indicators)
+		e.run(func() {
+			for u := range updates {
+				e.indicators.Publish(u.Symbol, u)
+			}
+		})
+	}
+	if cfg.alerts != nil {
+		alertIn = make(chan *pricev1.Candle, 1024)
+		e.run(func() { cfg.alerts.Run(ctx, alertIn) })
+	}
+	// Indicators and alerts must not hold up the hub and the sinks: when
+	// one falls behind, it misses candles, counted in Stats.
+	forward := func(what string, ch chan<- *pricev1.Candle, c *pricev1.Candle, drops *atomic.Uint64) {
+		if ch == nil || !c.IsFinal {
+			return
+		}
+		select {
+		case ch <- c:
+		default:
+			if drops.Add(1) == 1 {
+				log.Printf("engine: %s fell behind; dropping final candles", what)
+			}
+		}
+	}
+
+	for c := range candles {
+		e.hub.Publish(c)
+		forward("indicators", indicatorIn, c, &e.indicatorDrops)
+		forward("alerts", alertIn, c, &e.alertDrops)
+		for _, s := range cfg.sinks {
+			s.PublishCandle(c)
+		}
+	}
+	// also closed when every connector ended: nothing is left to run
+	e.cancel()
+}
+
+// drain calls fn for every value from ch until it closes or ctx ends.
+func drain[T any](ctx context.Context, ch <-chan T, fn func(T)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case v, ok := <-ch:
+			if !ok {
+				return
+			}
+			fn(v)
+		}
+	}
+}
+
+func logErrors(what string, ch <-chan error) {
+	for err := range ch {
+		if err != nil {
+			log.Printf("%s error: %v", what, err)
+		}
+	}
+}
+
+// trimAll trims the strings, drops empty ones and optionally upper-cases.
+func trimAll(in []string, upper bool) []string {
+	var out []string
+	for _, s := range in {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if upper {
+			s = strings.ToUpper(s)
+		}
+		out = append(out, s)
+	}
+	return out
+}
@@ -0,0 +1,140 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/binaridigital/price-engine/pkg/aggregate"
+	"github.com/binaridigital/price-engine/pkg/common"
+	"github.com/binaridigital/price-engine/pkg/ingest"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// tickConn sends a trade every millisecond and an error at start. When ctx
+// ends it takes a moment to close, like a websocket; both channels close
+// after that. running counts the goroutines still going.
+type tickConn struct {
+	running atomic.Int32
+}
+
+func (c *tickConn) Name() string { return "tick" }
+
+func (c *tickConn) Start(ctx context.Context, symbol string) (<-chan common.Trade, <-chan error) {
+	out := make(chan common.Trade)
+	errc := make(chan error)
+	c.running.Add(1)
+	go func() {
+		defer c.running.Add(-1)
+		defer close(out)
+		defer close(errc)
+		defer time.Sleep(20 * time.Millisecond)
+		select {
+		case errc <- errors.New("first connection refused"):
+		case <-ctx.Done():
+			return
+		}
+		t := time.NewTicker(time.Millisecond)
+		defer t.Stop()
+		for i := 0; ; i++ {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-t.C:
+				select {
+				case out <- common.Trade{Symbol: symbol, Price: 100, Qty: 1, Exchange: "tick", TS: now, TradeID: strconv.Itoa(i)}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, errc
+}
+
+func TestNewInvalidSizeBuckets(t *testing.T) {
+	_, err := New(WithSymbols("BTCUSDT"), WithConnectors(&tickConn{}),
+		WithAggregateOptions(aggregate.WithSizeBuckets([]float64{1000, 100})))
+	if err == nil {
+		t.Error("New accepted descending size buckets")
+	}
+}
+
+func TestStop(t *testing.T) {
+	conn := &tickConn{}
+	var (
+		mu      sync.Mutex
+		last    *pricev1.Candle
+		finals  int
+		stopped atomic.Bool
+	)
+	sink := SinkFunc(func(c *pricev1.Candle) error {
+		if stopped.Load() {
+			t.Error("candle published after Stop returned")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		last = c
+		if c.GetIsFinal() {
+			finals++
+		}
+		return nil
+	})
+	const interval = 20 * time.Millisecond
+	e, err := New(WithSymbols("BTCUSDT"), WithConnectors(conn), WithInterval(interval), WithSink(sink))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(5 * time.Millisecond) {
+		mu.Lock()
+		n := finals
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no final candle")
+		}
+	}
+
+	if err := e.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	stopped.Store(true)
+	if n := conn.running.Load(); n != 0 {
+		t.Errorf("%d connector goroutines still running after Stop", n)
+	}
+	// the sinks got every candle the hub did
+	ce, ok := e.Hub().Cache().Get("BTCUSDT")
+	if !ok {
+		t.Fatal("no candle in the hub")
+	}
+	mu.Lock()
+	if !proto.Equal(ce.Latest, last) {
+		t.Errorf("hub has %v, the sink %v", ce.Latest, last)
+	}
+	mu.Unlock()
+	// a goroutine left behind would publish within a few windows
+	time.Sleep(3 * interval)
+}
+
+func TestQuarantineKey(t *testing.T) {
+	ts := time.UnixMilli(1700000000000)
+	withID := quarantined(ingest.Rejected{Trade: common.Trade{Symbol: "BTCUSDT", Exchange: "binance", TradeID: "42", TS: ts, Price: 1}})
+	withoutID := quarantined(ingest.Rejected{Trade: common.Trade{Symbol: "EURUSD", Exchange: "oanda", TS: ts, Price: 1.25}})
+	if k := quarantineKey(withID); k != "BTCUSDT|binance|42" {
+		t.Errorf("key %q", k)
+	}
+	if k := quarantineKey(withoutID); k != "EURUSD|oanda|1700000000000|1.25" {
+		t.Errorf("key without trade ID %q", k)
+	}
+}
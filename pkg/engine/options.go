@@ -0,0 +1,201 @@
+// path: pkg/engine/options.go
+package engine
+
+import (
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/aggregate"
+	"github.com/binaridigital/price-engine/pkg/alert"
+	"github.com/binaridigital/price-engine/pkg/calendar"
+	"github.com/binaridigital/price-engine/pkg/fixing"
+	"github.com/binaridigital/price-engine/pkg/grpcapi"
+	"github.com/binaridigital/price-engine/pkg/indicator"
+	"github.com/binaridigital/price-engine/pkg/ingest"
+	"github.com/binaridigital/price-engine/pkg/metering"
+	"github.com/binaridigital/price-engine/pkg/profile"
+)
+
+// Option configures an Engine.
+type Option func(*config)
+
+type config struct {
+	symbols   []string
+	interval  time.Duration
+	conns     []ingest.Connector
+	standby   []ingest.Connector
+	stale     ingest.StaleConfig
+	dedup     time.Duration
+	filter    *ingest.FilterConfig
+	synthetic []string
+	legAge    time.Duration
+	aggOpts   []aggregate.Option
+
+	quoteSymbols []string
+	depthSymbols []string
+	depthLevels  int
+	profiles     *profile.Config
+	schedules    []fixing.Schedule
+	fixingGrace  time.Duration
+	indicators   indicator.Config
+	alerts       *alert.Engine
+	meter        *metering.Meter
+	meterFlush   time.Duration
+	calendars    *calendar.Registry
+
+	addr      string
+	history   int
+	srvOpts   []grpcapi.ServerOption
+	serveOpts []grpcapi.ServeOption
+
+	sinks          []Sink
+	profileSink    MessageSink
+	fixingSink     MessageSink
+	quarantineSink MessageSink
+}
+
+// WithSymbols sets the symbols every connector ingests.
+func WithSymbols(symbols ...string) Option {
+	return func(c *config) { c.symbols = append(c.symbols, symbols...) }
+}
+
+// WithInterval sets the candle window (one second by default).
+func WithInterval(d time.Duration) Option {
+	return func(c *config) { c.interval = d }
+}
+
+// WithConnectors adds trade connectors; see NewConnector for the built-in
+// ones. At least one is required.
+func WithConnectors(conns ...ingest.Connector) Option {
+	return func(c *config) { c.conns = append(c.conns, conns...) }
+}
+
+// WithStandby adds hot-standby connectors: duplicate connections to a venue
+// whose trades the deduper drops when the primary already delivered them.
+func WithStandby(conns ...ingest.Connector) Option {
+	return func(c *config) { c.standby = append(c.standby, conns...) }
+}
+
+// WithStaleConfig sets the connector stale-feed detection
+// (ingest.DefaultStaleConfig by default).
+func WithStaleConfig(cfg ingest.StaleConfig) Option {
+	return func(c *config) { c.stale = cfg }
+}
+
+// WithDedupWindow sets how long venue trade IDs are remembered
+// (ingest.DefaultDedupWindow by default).
+func WithDedupWindow(d time.Duration) Option {
+	return func(c *config) { c.dedup = d }
+}
+
+// WithTickFilter rejects bad ticks before aggregation; cfg is typically
+// ingest.DefaultFilterConfig. Trades are aggregated unfiltered by default.
+func WithTickFilter(cfg ingest.FilterConfig) Option {
+	return func(c *config) { c.filter = &cfg }
+}
+
+// WithoutTickFilter undoes an earlier WithTickFilter.
+func WithoutTickFilter() Option {
+	return func(c *config) { c.filter = nil }
+}
+
+// WithSynthetic triangulates cross pairs from the ingested symbols, using
+// leg prices at most maxLegAge old.
+func WithSynthetic(maxLegAge time.Duration, pairs ...string) Option {
+	return func(c *config) { c.legAge, c.synthetic = maxLegAge, append(c.synthetic, pairs...) }
+}
+
+// WithAggregateOptions passes options such as consensus pricing and size
+// buckets to the aggregator.
+func WithAggregateOptions(opts ...aggregate.Option) Option {
+	return func(c *config) { c.aggOpts = append(c.aggOpts, opts...) }
+}
+
+// WithQuotes ingests Binance best bid/offer for symbols: a live quote
+// stream and spread statistics in candles.
+func WithQuotes(symbols ...string) Option {
+	return func(c *config) { c.quoteSymbols = append(c.quoteSymbols, symbols...) }
+}
+
+// WithDepth maintains Binance L2 order books of levels per side for
+// symbols.
+func WithDepth(levels int, symbols ...string) Option {
+	return func(c *config) { c.depthLevels, c.depthSymbols = levels, append(c.depthSymbols, symbols...) }
+}
+
+// WithProfiles builds volume profiles from the trade feed.
+func WithProfiles(cfg profile.Config) Option {
+	return func(c *config) { c.profiles = &cfg }
+}
+
+// WithFixings computes the scheduled reference price fixings, waiting grace
+// after each window for late trades.
+func WithFixings(grace time.Duration, schedules ...fixing.Schedule) Option {
+	return func(c *config) { c.fixingGrace, c.schedules = grace, append(c.schedules, schedules...) }
+}
+
+// WithIndicators computes technical indicators on final candles.
+func WithIndicators(cfg indicator.Config) Option {
+	return func(c *config) { c.indicators = cfg }
+}
+
+// WithAlerts evaluates the alert engine's rules on final candles.
+func WithAlerts(a *alert.Engine) Option {
+	return func(c *config) { c.alerts = a }
+}
+
+// WithMeter meters and enforces quotas on the API, saving usage every
+// flush and once more on Stop.
+func WithMeter(m *metering.Meter, flush time.Duration) Option {
+	return func(c *config) { c.meter, c.meterFlush = m, flush }
+}
+
+// WithCalendars sets the trading calendars behind stream status events and
+// reloads them while the engine runs (calendar.Default by default).
+func WithCalendars(r *calendar.Registry) Option {
+	return func(c *config) { c.calendars = r }
+}
+
+// WithAddr serves the API on addr. Without it the engine serves nothing;
+// Server can then be mounted elsewhere.
+func WithAddr(addr string) Option {
+	return func(c *config) { c.addr = addr }
+}
+
+// WithHistory sets how many closed windows per symbol are kept
+// (grpcapi.DefaultHistory by default).
+func WithHistory(n int) Option {
+	return func(c *config) { c.history = n }
+}
+
+// WithServerOptions adds API options; they are applied after the engine's
+// own, so they can override them.
+func WithServerOptions(opts ...grpcapi.ServerOption) Option {
+	return func(c *config) { c.srvOpts = append(c.srvOpts, opts...) }
+}
+
+// WithServeOptions adds listener options (TLS, auth, CORS, connection
+// limits).
+func WithServeOptions(opts ...grpcapi.ServeOption) Option {
+	return func(c *config) { c.serveOpts = append(c.serveOpts, opts...) }
+}
+
+// WithSink adds a sink for every candle, partial and final.
+func WithSink(s Sink) Option {
+	return func(c *config) { c.sinks = append(c.sinks, s) }
+}
+
+// WithProfileSink sends volume profiles to s, keyed "symbol|window start".
+func WithProfileSink(s MessageSink) Option {
+	return func(c *config) { c.profileSink = s }
+}
+
+// WithFixingSink sends fixings to s, keyed "symbol|name|window end".
+func WithFixingSink(s MessageSink) Option {
+	return func(c *config) { c.fixingSink = s }
+}
+
+// WithQuarantineSink sends trades rejected by the tick filter to s, keyed
+// by symbol.
+func WithQuarantineSink(s MessageSink) Option {
+	return func(c *config) { c.quarantineSink = s }
+}
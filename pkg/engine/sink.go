@@ -0,0 +1,64 @@
+// path: pkg/engine/sink.go
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/binaridigital/price-engine/pkg/ingest"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// Sink receives the engine's candles, in order, from a single goroutine;
+// a slow sink holds up the pipeline. A kafka.Publisher is a Sink. Errors
+// are the sink's to count and report: the engine ignores them. Sinks that
+// are io.Closers are closed by Stop once the pipeline stopped.
+type Sink interface {
+	PublishCandle(c *pricev1.Candle) error
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(c *pricev1.Candle) error
+
+func (f SinkFunc) PublishCandle(c *pricev1.Candle) error { return f(c) }
+
+// MessageSink receives the engine's other outputs; key starts with the
+// symbol, followed by '|' and the message's identity. A kafka.Publisher is
+// a MessageSink. Errors and closing are as for Sink.
+type MessageSink interface {
+	Publish(key string, m proto.Message, ts time.Time) error
+}
+
+func profileKey(p *pricev1.VolumeProfile) string {
+	return fmt.Sprintf("%s|%d", p.Symbol, p.WindowStartMs)
+}
+
+func fixingKey(f *pricev1.Fixing) string {
+	return fmt.Sprintf("%s|%s|%d", f.Symbol, f.Name, f.WindowEndMs)
+}
+
+// quarantineKey identifies a rejected trade by its venue trade ID, or by
+// its timestamp and price on venues without IDs.
+func quarantineKey(q *pricev1.QuarantinedTrade) string {
+	if q.TradeId != "" {
+		return fmt.Sprintf("%s|%s|%s", q.Symbol, q.Exchange, q.TradeId)
+	}
+	return fmt.Sprintf("%s|%s|%d|%v", q.Symbol, q.Exchange, q.Ts, q.Price)
+}
+
+func quarantined(r ingest.Rejected) *pricev1.QuarantinedTrade {
+	return &pricev1.QuarantinedTrade{
+		Symbol:         r.Trade.Symbol,
+		Exchange:       r.Trade.Exchange,
+		Price:          r.Trade.Price,
+		Qty:            r.Trade.Qty,
+		Ts:             r.Trade.TS.UnixMilli(),
+		TradeId:        r.Trade.TradeID,
+		Reason:         r.Reason,
+		ReferencePrice: r.Reference,
+		Score:          r.Score,
+		RejectedTs:     r.At.UnixMilli(),
+	}
+}
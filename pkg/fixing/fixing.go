@@ -0,0 +1,294 @@
+// path: pkg/fixing/fixing.go
+package fixing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/common"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// DefaultGrace is how long after a window closes late trades are still
+// accepted before the fixing is published.
+const DefaultGrace = 2 * time.Second
+
+// Method selects the published reference value.
+type Method string
+
+const (
+	MethodTWAP   Method = "twap"
+	MethodVWAP   Method = "vwap"
+	MethodMedian Method = "median"
+)
+
+// Schedule is one daily fixing: the window [At-Window, At) in Location,
+// computed for each of Symbols.
+type Schedule struct {
+	Name     string
+	Symbols  []string
+	At       time.Duration // time of day the window closes
+	Location *time.Location
+	Window   time.Duration
+	Method   Method
+}
+
+// ParseSchedules reads ";"-separated schedules of space-separated
+// key=value pairs, e.g.
+//
+//	name=LDN4PM symbols=EURUSD,GBPUSD at=16:00 tz=Europe/London window=5m method=twap
+func ParseSchedules(s string) ([]Schedule, error) {
+	var out []Schedule
+	for _, entry := range strings.Split(s, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		sc := Schedule{Location: time.UTC, Window: 5 * time.Minute, Method: MethodTWAP}
+		for _, kv := range strings.Fields(entry) {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				return nil, fmt.Errorf("fixing %q: want key=value, got %q", entry, kv)
+			}
+			switch strings.ToLower(k) {
+			case "name":
+				sc.Name = v
+			case "symbols":
+				for _, sym := range strings.Split(v, ",") {
+					if sym = strings.ToUpper(strings.TrimSpace(sym)); sym != "" {
+						sc.Symbols = append(sc.Symbols, sym)
+					}
+				}
+			case "at":
+				t, err := time.Parse("15:04", v)
+				if err != nil {
+					return nil, fmt.Errorf("fixing %q: at: %w", entry, err)
+				}
+				sc.At = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+			case "tz":
+				loc, err := time.LoadLocation(v)
+				if err != nil {
+					return nil, fmt.Errorf("fixing %q: tz: %w", entry, err)
+				}
+				sc.Location = loc
+			case "window":
+				d, err := time.ParseDuration(v)
+				if err != nil || d <= 0 || d >= 24*time.Hour {
+					return nil, fmt.Errorf("fixing %q: bad window %q", entry, v)
+				}
+				sc.Window = d
+			case "method":
+				sc.Method = Method(strings.ToLower(v))
+				switch sc.Method {
+				case MethodTWAP, MethodVWAP, MethodMedian:
+				default:
+					return nil, fmt.Errorf("fixing %q: unknown method %q (twap|vwap|median)", entry, v)
+				}
+			default:
+				return nil, fmt.Errorf("fixing %q: unknown key %q", entry, k)
+			}
+		}
+		if sc.Name == "" || len(sc.Symbols) == 0 {
+			return nil, fmt.Errorf("fixing %q: name and symbols are required", entry)
+		}
+		out = append(out, sc)
+	}
+	return out, nil
+}
+
+// next returns the first window of sc that opens at or after now, so a
+// fixing is never computed from a partially observed window.
+func (sc Schedule) next(now time.Time) (start, end time.Time) {
+	local := now.In(sc.Location)
+	for day := 0; ; day++ {
+		d := time.Date(local.Year(), local.Month(), local.Day()+day, 0, 0, 0, 0, sc.Location)
+		end = time.Date(d.Year(), d.Month(), d.Day(), int(sc.At/time.Hour), int(sc.At%time.Hour/time.Minute), 0, 0, sc.Location)
+		start = end.Add(-sc.Window)
+		if !start.Before(now) {
+			return start, end
+		}
+	}
+}
+
+type slot struct {
+	sc         Schedule
+	symbol     string
+	start, end time.Time
+	trades     []common.Trade
+}
+
+// Run collects trades inside each scheduled window and emits one Fixing
+// per schedule and symbol once the window has closed and grace has passed.
+// Windows without trades are logged and skipped.
+func Run(ctx context.Context, trades <-chan common.Trade, schedules []Schedule, grace time.Duration) <-chan *pricev1.Fixing {
+	out := make(chan *pricev1.Fixing, 64)
+	bySymbol := make(map[string][]*slot)
+	now := time.Now()
+	for _, sc := range schedules {
+		for _, sym := range sc.Symbols {
+			s := &slot{sc: sc, symbol: sym}
+			s.start, s.end = sc.next(now)
+			bySymbol[sym] = append(bySymbol[sym], s)
+			log.Printf("fixing %s %s: next window %s – %s", sc.Name, sym, s.start.Format(time.RFC3339), s.end.Format(time.RFC3339))
+		}
+	}
+
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case t, ok := <-trades:
+				if !ok {
+					return
+				}
+				if t.Price <= 0 {
+					continue
+				}
+				for _, s := range bySymbol[t.Symbol] {
+					if !t.TS.Before(s.start) && t.TS.Before(s.end) {
+						s.trades = append(s.trades, t)
+					}
+				}
+			case now := <-ticker.C:
+				for _, slots := range bySymbol {
+					for _, s := range slots {
+						if now.Before(s.end.Add(grace)) {
+							continue
+						}
+						if f := compute(s); f != nil {
+							select {
+							case out <- f:
+							case <-ctx.Done():
+								return
+							}
+						} else {
+							log.Printf("fixing %s %s: no trades in window ending %s", s.sc.Name, s.symbol, s.end.Format(time.RFC3339))
+						}
+						s.trades = nil
+						s.start, s.end = s.sc.next(s.end)
+					}
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+func compute(s *slot) *pricev1.Fixing {
+	// Synthetic crosses only stand in when no venue traded the symbol.
+	trades := make([]common.Trade, 0, len(s.trades))
+	for _, t := range s.trades {
+		if !t.Synthetic {
+			trades = append(trades, t)
+		}
+	}
+	if len(trades) == 0 {
+		trades = s.trades
+	}
+	if len(trades) == 0 {
+		return nil
+	}
+	sort.SliceStable(trades, func(i, j int) bool { return trades[i].TS.Before(trades[j].TS) })
+
+	var vol, notional float64
+	sources := make(map[string]*pricev1.FixingSource)
+	prices := make([]float64, len(trades))
+	for i, t := range trades {
+		vol += t.Qty
+		notional += t.Price * t.Qty
+		prices[i] = t.Price
+		src := sources[t.Exchange]
+		if src == nil {
+			src = &pricev1.FixingSource{Exchange: t.Exchange}
+			sources[t.Exchange] = src
+		}
+		src.TradeCount++
+		src.Volume += t.Qty
+	}
+
+	// TWAP: each price holds until the next trade or the window end; the
+	// stretch before the first trade is not observed and is left out.
+	var twap float64
+	span := s.end.Sub(trades[0].TS)
+	for i, t := range trades {
+		until := s.end
+		if i+1 < len(trades) {
+			until = trades[i+1].TS
+		}
+		if span > 0 {
+			twap += t.Price * float64(until.Sub(t.TS)) / float64(span)
+		}
+	}
+	if span <= 0 {
+		twap = trades[len(trades)-1].Price
+	}
+
+	var vwap float64
+	if vol > 0 {
+		vwap = notional / vol
+	}
+
+	sort.Float64s(prices)
+	median := prices[len(prices)/2]
+	if len(prices)%2 == 0 {
+		median = (prices[len(prices)/2-1] + prices[len(prices)/2]) / 2
+	}
+
+	f := &pricev1.Fixing{
+		Name:          s.sc.Name,
+		Symbol:        s.symbol,
+		Method:        string(s.sc.Method),
+		Twap:          twap,
+		Vwap:          vwap,
+		Median:        median,
+		WindowStartMs: s.start.UnixMilli(),
+		WindowEndMs:   s.end.UnixMilli(),
+		TradeCount:    uint64(len(trades)),
+		Volume:        vol,
+		Methodology:   methodology(s.sc, trades[0].Synthetic),
+		PublishedTs:   time.Now().UnixMilli(),
+	}
+	switch s.sc.Method {
+	case MethodVWAP:
+		f.Value = vwap
+	case MethodMedian:
+		f.Value = median
+	default:
+		f.Value = twap
+	}
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f.Sources = append(f.Sources, sources[name])
+	}
+	return f
+}
+
+func methodology(sc Schedule, synthetic bool) string {
+	var desc string
+	switch sc.Method {
+	case MethodVWAP:
+		desc = "volume-weighted average of trade prices"
+	case MethodMedian:
+		desc = "median of trade prices"
+	default:
+		desc = "time-weighted average of the last trade price from the first trade to the window end"
+	}
+	h, m := int(sc.At/time.Hour), int(sc.At%time.Hour/time.Minute)
+	src := "all venues, synthetic crosses excluded"
+	if synthetic {
+		src = "synthetic cross rates (no venue trades in window)"
+	}
+	return fmt.Sprintf("%s over the %s window ending %02d:%02d %s; sources: %s", desc, sc.Window, h, m, sc.Location, src)
+}
@@ -0,0 +1,221 @@
+package fixing
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/common"
+)
+
+func TestCompute(t *testing.T) {
+	end := time.Date(2026, 3, 2, 10, 5, 0, 0, time.UTC)
+	at := func(min, sec int) time.Time { return time.Date(2026, 3, 2, 10, min, sec, 0, time.UTC) }
+	trade := func(exchange string, ts time.Time, price, qty float64) common.Trade {
+		return common.Trade{Symbol: "BTCUSDT", Exchange: exchange, TS: ts, Price: price, Qty: qty}
+	}
+	synthetic := func(ts time.Time, price, qty float64) common.Trade {
+		t := trade("synthetic", ts, price, qty)
+		t.Synthetic = true
+		return t
+	}
+
+	tests := []struct {
+		name               string
+		method             Method
+		trades             []common.Trade
+		twap, vwap, median float64
+		value              float64
+		count              uint64
+	}{
+		{
+			// 100 holds 1m, 102 holds 2m, 101 holds 1m of the 4m after the first trade
+			name:   "twap, out of order",
+			method: MethodTWAP,
+			trades: []common.Trade{
+				trade("kraken", at(2, 0), 102, 3),
+				trade("binance", at(1, 0), 100, 1),
+				trade("binance", at(4, 0), 101, 1),
+			},
+			twap: 101.25, vwap: 101.4, median: 101, value: 101.25, count: 3,
+		},
+		{
+			name:   "vwap",
+			method: MethodVWAP,
+			trades: []common.Trade{
+				trade("binance", at(1, 0), 100, 1),
+				trade("kraken", at(2, 0), 102, 3),
+				trade("binance", at(4, 0), 101, 1),
+			},
+			twap: 101.25, vwap: 101.4, median: 101, value: 101.4, count: 3,
+		},
+		{
+			name:   "median of an even count",
+			method: MethodMedian,
+			trades: []common.Trade{
+				trade("binance", at(1, 0), 100, 1),
+				trade("binance", at(2, 0), 106, 1),
+				trade("kraken", at(3, 0), 104, 1),
+				trade("kraken", at(4, 0), 102, 1),
+			},
+			twap: 103, vwap: 103, median: 103, value: 103, count: 4,
+		},
+		{
+			name:   "synthetic crosses left out",
+			method: MethodTWAP,
+			trades: []common.Trade{
+				trade("binance", at(1, 0), 100, 1),
+				synthetic(at(2, 0), 500, 10),
+				trade("binance", at(3, 0), 104, 1),
+			},
+			twap: 102, vwap: 102, median: 102, value: 102, count: 2,
+		},
+		{
+			name:   "synthetic crosses alone",
+			method: MethodMedian,
+			trades: []common.Trade{
+				synthetic(at(3, 0), 1.2, 1),
+				synthetic(at(4, 0), 1.1, 3),
+			},
+			twap: 1.15, vwap: 1.125, median: 1.15, value: 1.15, count: 2,
+		},
+		{
+			name:   "trade at the window end",
+			method: MethodTWAP,
+			trades: []common.Trade{trade("binance", end, 99, 2)},
+			twap:   99, vwap: 99, median: 99, value: 99, count: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := Schedule{Name: "TEST", Symbols: []string{"BTCUSDT"}, At: 10*time.Hour + 5*time.Minute, Location: time.UTC, Window: 5 * time.Minute, Method: tt.method}
+			f := compute(&slot{sc: sc, symbol: "BTCUSDT", start: end.Add(-sc.Window), end: end, trades: tt.trades})
+			if f == nil {
+				t.Fatal("no fixing")
+			}
+			for _, c := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"twap", f.GetTwap(), tt.twap},
+				{"vwap", f.GetVwap(), tt.vwap},
+				{"median", f.GetMedian(), tt.median},
+				{"value", f.GetValue(), tt.value},
+			} {
+				if math.Abs(c.got-c.want) > 1e-9 {
+					t.Errorf("%s %v, want %v", c.name, c.got, c.want)
+				}
+			}
+			if f.GetTradeCount() != tt.count || f.GetMethod() != string(tt.method) {
+				t.Errorf("trade count %d, method %q", f.GetTradeCount(), f.GetMethod())
+			}
+			if f.GetWindowStartMs() != end.Add(-5*time.Minute).UnixMilli() || f.GetWindowEndMs() != end.UnixMilli() {
+				t.Errorf("window %d–%d", f.GetWindowStartMs(), f.GetWindowEndMs())
+			}
+		})
+	}
+}
+
+func TestComputeSources(t *testing.T) {
+	end := time.Date(2026, 3, 2, 10, 5, 0, 0, time.UTC)
+	s := &slot{
+		sc:     Schedule{Name: "TEST", Location: time.UTC, Window: 5 * time.Minute, Method: MethodTWAP},
+		symbol: "BTCUSDT",
+		start:  end.Add(-5 * time.Minute),
+		end:    end,
+		trades: []common.Trade{
+			{Exchange: "kraken", TS: end.Add(-3 * time.Minute), Price: 100, Qty: 3},
+			{Exchange: "binance", TS: end.Add(-2 * time.Minute), Price: 100, Qty: 1},
+			{Exchange: "binance", TS: end.Add(-time.Minute), Price: 100, Qty: 0.5},
+		},
+	}
+	f := compute(s)
+	src := f.GetSources()
+	if len(src) != 2 || src[0].GetExchange() != "binance" || src[0].GetTradeCount() != 2 || src[0].GetVolume() != 1.5 ||
+		src[1].GetExchange() != "kraken" || src[1].GetTradeCount() != 1 || src[1].GetVolume() != 3 {
+		t.Errorf("sources %v", src)
+	}
+	if f.GetVolume() != 4.5 || !strings.Contains(f.GetMethodology(), "synthetic crosses excluded") {
+		t.Errorf("volume %v, methodology %q", f.GetVolume(), f.GetMethodology())
+	}
+}
+
+func TestComputeEmptyWindow(t *testing.T) {
+	end := time.Date(2026, 3, 2, 10, 5, 0, 0, time.UTC)
+	s := &slot{sc: Schedule{Name: "TEST", Location: time.UTC, Window: 5 * time.Minute}, symbol: "BTCUSDT", start: end.Add(-5 * time.Minute), end: end}
+	if f := compute(s); f != nil {
+		t.Errorf("empty window gave %v", f)
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, time.UTC)
+	}
+	// UK clocks go forward on 29 March 2026 and back on 25 October 2026
+	tests := []struct {
+		name       string
+		at, window time.Duration
+		now        time.Time
+		start, end time.Time
+	}{
+		{"same day", 16 * time.Hour, 5 * time.Minute, utc(3, 2, 12, 0), utc(3, 2, 15, 55), utc(3, 2, 16, 0)},
+		{"window open: next day", 16 * time.Hour, 5 * time.Minute, utc(3, 2, 15, 57), utc(3, 3, 15, 55), utc(3, 3, 16, 0)},
+		{"now at the window start", 16 * time.Hour, 5 * time.Minute, utc(3, 2, 15, 55), utc(3, 2, 15, 55), utc(3, 2, 16, 0)},
+		{"into summer time", 16 * time.Hour, 5 * time.Minute, utc(3, 28, 17, 0), utc(3, 29, 14, 55), utc(3, 29, 15, 0)},
+		{"out of summer time", 16 * time.Hour, 5 * time.Minute, utc(10, 24, 16, 0), utc(10, 25, 15, 55), utc(10, 25, 16, 0)},
+		// 03:00 BST is 02:00 UTC; the two hours before it are 00:00-02:00 UTC
+		{"window across the change", 3 * time.Hour, 2 * time.Hour, utc(3, 28, 12, 0), utc(3, 29, 0, 0), utc(3, 29, 2, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := Schedule{At: tt.at, Location: london, Window: tt.window}
+			start, end := sc.next(tt.now)
+			if !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Errorf("next(%v) = %v – %v, want %v – %v", tt.now, start.UTC(), end.UTC(), tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestParseSchedules(t *testing.T) {
+	scs, err := ParseSchedules("name=LDN4PM symbols=eurusd,,GBPUSD at=16:00 tz=Europe/London window=1m method=VWAP; ;name=WMR symbols=USDJPY at=08:30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scs) != 2 {
+		t.Fatalf("%d schedules, want 2", len(scs))
+	}
+	ldn, wmr := scs[0], scs[1]
+	if ldn.Name != "LDN4PM" || strings.Join(ldn.Symbols, ",") != "EURUSD,GBPUSD" || ldn.At != 16*time.Hour ||
+		ldn.Location.String() != "Europe/London" || ldn.Window != time.Minute || ldn.Method != MethodVWAP {
+		t.Errorf("first schedule %+v", ldn)
+	}
+	if wmr.At != 8*time.Hour+30*time.Minute || wmr.Location != time.UTC || wmr.Window != 5*time.Minute || wmr.Method != MethodTWAP {
+		t.Errorf("defaults %+v", wmr)
+	}
+
+	for _, s := range []string{
+		"name=X symbols=EURUSD at",
+		"name=X symbols=EURUSD at=25:00",
+		"name=X symbols=EURUSD at=4pm",
+		"name=X symbols=EURUSD tz=Mars/Olympus",
+		"name=X symbols=EURUSD window=0s",
+		"name=X symbols=EURUSD window=24h",
+		"name=X symbols=EURUSD window=soon",
+		"name=X symbols=EURUSD method=mode",
+		"name=X symbols=EURUSD colour=red",
+		"symbols=EURUSD at=16:00",
+		"name=X symbols=, at=16:00",
+		"name=X symbols=EURUSD;name=Y",
+	} {
+		if _, err := ParseSchedules(s); err == nil {
+			t.Errorf("ParseSchedules(%q) accepted", s)
+		}
+	}
+}
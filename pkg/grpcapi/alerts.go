@@ -0,0 +1,122 @@
+// path: pkg/grpcapi/alerts.go
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/binaridigital/price-engine/pkg/alert"
+	"github.com/binaridigital/price-engine/pkg/auth"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// WithAlerts enables the alert rule and StreamAlerts RPCs.
+func WithAlerts(e *alert.Engine) ServerOption {
+	return func(s *Server) { s.alerts = e }
+}
+
+var errNoAlerts = errors.New("alerts not available on this engine instance")
+
+func (s *Server) CreateAlertRule(ctx context.Context, req *pricev1.AlertRule) (*pricev1.AlertRule, error) {
+	if s.alerts == nil {
+		return nil, errNoAlerts
+	}
+	if err := s.authorize(ctx, pricev1.PriceStream_CreateAlertRule_FullMethodName, strings.ToUpper(req.GetSymbol()), 0); err != nil {
+		return nil, err
+	}
+	req = proto.Clone(req).(*pricev1.AlertRule)
+	req.Owner = ""
+	if p, ok := auth.FromContext(ctx); ok {
+		req.Owner = p.ID
+	}
+	r, err := s.alerts.Add(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return r, nil
+}
+
+// DeleteAlertRule requires the caller to be entitled to the rule's symbol
+// and, unless an admin, to be the client that created it.
+func (s *Server) DeleteAlertRule(ctx context.Context, req *pricev1.DeleteAlertRuleRequest) (*pricev1.DeleteAlertRuleResponse, error) {
+	if s.alerts == nil {
+		return nil, errNoAlerts
+	}
+	const method = pricev1.PriceStream_DeleteAlertRule_FullMethodName
+	r, found := s.alerts.Get(req.GetId())
+	if !found {
+		return &pricev1.DeleteAlertRuleResponse{}, nil
+	}
+	if err := s.authorize(ctx, method, r.GetSymbol(), 0); err != nil {
+		return nil, err
+	}
+	if p, ok := auth.FromContext(ctx); ok && !p.Admin && r.GetOwner() != p.ID {
+		const reason = "alert rule belongs to another client"
+		p.Deny(method, r.GetSymbol(), reason)
+		return nil, status.Error(codes.PermissionDenied, reason)
+	}
+	ok, err := s.alerts.Remove(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &pricev1.DeleteAlertRuleResponse{Deleted: ok}, nil
+}
+
+// ListAlertRules returns the caller's own rules, or every client's for an
+// admin, on the symbols the caller is entitled to.
+func (s *Server) ListAlertRules(ctx context.Context, req *pricev1.ListAlertRulesRequest) (*pricev1.ListAlertRulesResponse, error) {
+	if s.alerts == nil {
+		return nil, errNoAlerts
+	}
+	owner := ruleOwner(ctx)
+	resp := &pricev1.ListAlertRulesResponse{}
+	for _, r := range s.alerts.List(req.GetSymbol()) {
+		if (owner == "" || r.GetOwner() == owner) && allowed(ctx, r.GetSymbol()) {
+			resp.Rules = append(resp.Rules, r)
+		}
+	}
+	return resp, nil
+}
+
+// StreamAlerts sends the alerts of the caller's own rules, or of every
+// client's for an admin.
+func (s *Server) StreamAlerts(req *pricev1.AlertRequest, stream pricev1.PriceStream_StreamAlertsServer) error {
+	if s.alerts == nil {
+		return errNoAlerts
+	}
+	ctx := stream.Context()
+	if req.GetSymbol() != "" {
+		if err := s.authorize(ctx, pricev1.PriceStream_StreamAlerts_FullMethodName, req.GetSymbol(), 0); err != nil {
+			return err
+		}
+	}
+	ch, unsub := s.alerts.Subscribe(req.GetSymbol(), ruleOwner(ctx))
+	defer unsub()
+	for {
+		select {
+		case <-ctx.Done():
+			return context.Canceled
+		case a := <-ch:
+			if !allowed(ctx, a.GetSymbol()) {
+				continue
+			}
+			if err := stream.Send(a); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ruleOwner returns the owner whose rules the caller may see: its own id,
+// or "" (all) for an admin or when authentication is off.
+func ruleOwner(ctx context.Context) string {
+	if p, ok := auth.FromContext(ctx); ok && !p.Admin {
+		return p.ID
+	}
+	return ""
+}
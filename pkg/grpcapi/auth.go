@@ -0,0 +1,202 @@
+// path: pkg/grpcapi/auth.go
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/binaridigital/price-engine/pkg/auth"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// bearerToken strips the "Bearer " scheme from an Authorization value.
+func bearerToken(v string) string {
+	if len(v) > 7 && strings.EqualFold(v[:7], "bearer ") {
+		return strings.TrimSpace(v[7:])
+	}
+	return ""
+}
+
+// authenticate resolves the caller from the x-api-key or authorization
+// metadata, or the verified client certificate.
+func authenticate(ctx context.Context, a *auth.Authenticator, method string) (*auth.Principal, error) {
+	var cr auth.Credentials
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get("x-api-key"); len(v) > 0 {
+		cr.APIKey = v[0]
+	}
+	if v := md.Get("authorization"); len(v) > 0 {
+		cr.Bearer = bearerToken(v[0])
+	}
+	var addr string
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+		if ti, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			cr.Certs = ti.State.PeerCertificates
+		}
+	}
+	pr, err := a.Authenticate(cr)
+	if err != nil {
+		a.Deny(auth.Denial{Method: method, Peer: addr, Reason: err.Error()})
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	pr.Peer = addr
+	return pr, nil
+}
+
+func unaryAuth(a *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isHealthMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		p, err := authenticate(ctx, a, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(auth.NewContext(ctx, p), req)
+	}
+}
+
+// streamAuth also holds one of the client's stream slots while the stream
+// runs.
+func streamAuth(a *auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isHealthMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		p, err := authenticate(ss.Context(), a, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		release, err := p.AcquireStream()
+		if err != nil {
+			p.Deny(info.FullMethod, "", err.Error())
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		defer release()
+		return handler(srv, &authStream{ServerStream: ss, ctx: auth.NewContext(ss.Context(), p)})
+	}
+}
+
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context { return s.ctx }
+
+// httpAuth authenticates gateway requests with the same credentials as
+// gRPC. Browsers cannot set headers on EventSource or WebSocket, so the
+// api_key and access_token query parameters are accepted as well.
+func httpAuth(a *auth.Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		cr := auth.Credentials{
+			APIKey: r.Header.Get("X-Api-Key"),
+			Bearer: bearerToken(r.Header.Get("Authorization")),
+		}
+		if cr.APIKey == "" {
+			cr.APIKey = q.Get("api_key")
+		}
+		if cr.Bearer == "" {
+			cr.Bearer = q.Get("access_token")
+		}
+		if r.TLS != nil {
+			cr.Certs = r.TLS.PeerCertificates
+		}
+		method := r.Method + " " + r.URL.Path
+		p, err := a.Authenticate(cr)
+		if err != nil {
+			a.Deny(auth.Denial{Method: method, Peer: r.RemoteAddr, Reason: err.Error()})
+			writeConnectError(w, codes.Unauthenticated, err.Error())
+			return
+		}
+		p.Peer = r.RemoteAddr
+		next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), p)))
+	})
+}
+
+// acquireStream takes a stream slot for a gateway stream; release is a
+// no-op when auth is disabled.
+func acquireStream(ctx context.Context, method string) (func(), error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return func() {}, nil
+	}
+	release, err := p.AcquireStream()
+	if err != nil {
+		p.Deny(method, "", err.Error())
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return release, nil
+}
+
+// authorize checks the caller's entitlements for symbol: the symbol
+// itself, the interval (0 skips the check) and every exchange known to
+// feed it. Denials are audited.
+func (s *Server) authorize(ctx context.Context, method, symbol string, intervalMs int64) error {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	var reason string
+	switch {
+	case !p.AllowSymbol(symbol):
+		reason = "symbol not entitled"
+	case intervalMs > 0 && !p.AllowInterval(intervalMs):
+		reason = fmt.Sprintf("interval %dms not entitled", intervalMs)
+	default:
+		for _, ex := range s.symbolExchanges()[symbol] {
+			if !p.AllowExchange(ex) {
+				reason = fmt.Sprintf("exchange %s not entitled", ex)
+				break
+			}
+		}
+	}
+	if reason == "" {
+		return nil
+	}
+	p.Deny(method, symbol, reason)
+	return status.Error(codes.PermissionDenied, reason)
+}
+
+// authorizeCandle rejects a candle built from a venue the caller is not
+// entitled to, which can appear after the subscription started.
+func authorizeCandle(ctx context.Context, method string, c *pricev1.Candle) error {
+	p, ok := auth.FromContext(ctx)
+	if !ok || len(p.Exchanges) == 0 {
+		return nil
+	}
+	deny := func(v string) error {
+		reason := fmt.Sprintf("exchange %s not entitled", v)
+		p.Deny(method, c.GetSymbol(), reason)
+		return status.Error(codes.PermissionDenied, reason)
+	}
+	for _, v := range c.GetContributingVenues() {
+		if !p.AllowExchange(v) {
+			return deny(v)
+		}
+	}
+	for _, l := range c.GetSourceLegs() {
+		if !p.AllowExchange(l.GetExchange()) {
+			return deny(l.GetExchange())
+		}
+	}
+	return nil
+}
+
+// allowed reports whether the caller may see symbol, without auditing;
+// list RPCs use it to filter their results.
+func allowed(ctx context.Context, symbol string) bool {
+	p, ok := auth.FromContext(ctx)
+	return !ok || p.AllowSymbol(symbol)
+}
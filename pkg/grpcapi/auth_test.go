@@ -0,0 +1,309 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/binaridigital/price-engine/pkg/alert"
+	"github.com/binaridigital/price-engine/pkg/auth"
+	"github.com/binaridigital/price-engine/pkg/ingest"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// authenticator loads an auth config with the given clients, each with
+// its id as API key.
+func authenticator(t *testing.T, clients ...auth.Client) *auth.Authenticator {
+	t.Helper()
+	for i := range clients {
+		sum := sha256.Sum256([]byte(clients[i].ID))
+		clients[i].APIKeySHA256 = []string{hex.EncodeToString(sum[:])}
+	}
+	b, err := json.Marshal(auth.Config{Clients: clients})
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "auth.json")
+	if err := os.WriteFile(file, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a, err := auth.Load(file, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+// principals returns a context authenticated as each of the clients.
+func principals(t *testing.T, clients ...auth.Client) map[string]context.Context {
+	t.Helper()
+	a := authenticator(t, clients...)
+	out := make(map[string]context.Context)
+	for _, c := range clients {
+		p, err := a.Authenticate(auth.Credentials{APIKey: c.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[c.ID] = auth.NewContext(context.Background(), p)
+	}
+	return out
+}
+
+func TestSymbolExchangesTradesOnly(t *testing.T) {
+	mon := ingest.NewMonitor()
+	mon.Track("binance", "btcusdt", 0)
+	mon.TrackFeed(ingest.FeedDepth, "binance-depth", "btcusdt", 0)
+	mon.TrackFeed(ingest.FeedQuotes, "binance-quotes", "btcusdt", 0)
+	s := NewServer(NewHub(), time.Second, WithMonitor(mon))
+
+	if got := s.symbolExchanges()["BTCUSDT"]; !slices.Equal(got, []string{"binance"}) {
+		t.Errorf("exchanges %v, want [binance]", got)
+	}
+
+	// a client entitled to binance only is not refused for the trackers
+	ctx := principals(t, auth.Client{ID: "c", Entitlements: auth.Entitlements{Exchanges: []string{"binance"}}})["c"]
+	if err := s.authorize(ctx, "test", "BTCUSDT", 0); err != nil {
+		t.Errorf("authorize: %v", err)
+	}
+}
+
+func TestDeleteAlertRule(t *testing.T) {
+	ctxs := principals(t,
+		auth.Client{ID: "alice"},
+		auth.Client{ID: "bob"},
+		auth.Client{ID: "eth-only", Entitlements: auth.Entitlements{Symbols: []string{"ETH*"}}},
+		auth.Client{ID: "ops", Entitlements: auth.Entitlements{Admin: true}},
+	)
+	alerts, err := alert.NewEngine(filepath.Join(t.TempDir(), "rules.json"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(NewHub(), time.Second, WithAlerts(alerts))
+	create := func() string {
+		r, err := s.CreateAlertRule(ctxs["alice"], &pricev1.AlertRule{
+			Symbol: "btcusdt", Kind: pricev1.AlertKind_AK_PRICE_CROSS, Level: 100, Owner: "bob",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.GetOwner() != "alice" {
+			t.Fatalf("owner %q, want alice", r.GetOwner())
+		}
+		return r.GetId()
+	}
+
+	tests := []struct {
+		caller  string
+		code    codes.Code
+		deleted bool
+	}{
+		{caller: "bob", code: codes.PermissionDenied},
+		{caller: "eth-only", code: codes.PermissionDenied},
+		{caller: "alice", deleted: true},
+		{caller: "ops", deleted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caller, func(t *testing.T) {
+			id := create()
+			resp, err := s.DeleteAlertRule(ctxs[tt.caller], &pricev1.DeleteAlertRuleRequest{Id: id})
+			if status.Code(err) != tt.code || resp.GetDeleted() != tt.deleted {
+				t.Fatalf("DeleteAlertRule = %v, %v; want deleted %v, code %v", resp, err, tt.deleted, tt.code)
+			}
+			if _, exists := alerts.Get(id); exists == tt.deleted {
+				t.Errorf("rule exists = %v after the call", exists)
+			}
+		})
+	}
+
+	resp, err := s.DeleteAlertRule(ctxs["bob"], &pricev1.DeleteAlertRuleRequest{Id: "unknown"})
+	if err != nil || resp.GetDeleted() {
+		t.Errorf("unknown rule: %v, %v", resp, err)
+	}
+}
+
+// alertStream collects what StreamAlerts sends.
+type alertStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *pricev1.Alert
+}
+
+func (s alertStream) Context() context.Context { return s.ctx }
+
+func (s alertStream) Send(a *pricev1.Alert) error {
+	s.sent <- a
+	return nil
+}
+
+func TestAlertRulesOwner(t *testing.T) {
+	ctxs := principals(t,
+		auth.Client{ID: "alice"},
+		auth.Client{ID: "bob"},
+		auth.Client{ID: "ops", Entitlements: auth.Entitlements{Admin: true}},
+	)
+	alerts, err := alert.NewEngine(filepath.Join(t.TempDir(), "rules.json"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(NewHub(), time.Second, WithAlerts(alerts))
+	ids := make(map[string]string)
+	for _, owner := range []string{"alice", "bob"} {
+		r, err := s.CreateAlertRule(ctxs[owner], &pricev1.AlertRule{Symbol: "BTCUSDT", Kind: pricev1.AlertKind_AK_PRICE_CROSS, Level: 100})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[owner] = r.GetId()
+	}
+
+	// subscribe everyone before the rules fire
+	streams := make(map[string]alertStream)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, caller := range []string{"alice", "bob", "ops"} {
+		resp, err := s.ListAlertRules(ctxs[caller], &pricev1.ListAlertRulesRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, r := range resp.GetRules() {
+			got = append(got, r.GetId())
+		}
+		if want := ownIDs(caller, ids); !slices.Equal(sortedIDs(got), want) {
+			t.Errorf("%s lists %v, want %v", caller, got, want)
+		}
+
+		p, _ := auth.FromContext(ctxs[caller])
+		st := alertStream{ctx: auth.NewContext(ctx, p), sent: make(chan *pricev1.Alert, 4)}
+		streams[caller] = st
+		go s.StreamAlerts(&pricev1.AlertRequest{}, st)
+	}
+	// StreamAlerts subscribes asynchronously
+	time.Sleep(50 * time.Millisecond)
+
+	candles := make(chan *pricev1.Candle, 2)
+	candles <- &pricev1.Candle{Symbol: "BTCUSDT", Close: 99, IsFinal: true}
+	candles <- &pricev1.Candle{Symbol: "BTCUSDT", Close: 101, IsFinal: true}
+	close(candles)
+	alerts.Run(ctx, candles)
+
+	for caller, st := range streams {
+		want := ownIDs(caller, ids)
+		var got []string
+		for range want {
+			select {
+			case a := <-st.sent:
+				got = append(got, a.GetRuleId())
+			case <-time.After(5 * time.Second):
+				t.Fatalf("%s got %v, want %v", caller, got, want)
+			}
+		}
+		select {
+		case a := <-st.sent:
+			t.Errorf("%s also got the alert of rule %s", caller, a.GetRuleId())
+		case <-time.After(20 * time.Millisecond):
+		}
+		if !slices.Equal(sortedIDs(got), want) {
+			t.Errorf("%s streamed %v, want %v", caller, got, want)
+		}
+	}
+}
+
+// ownIDs returns the sorted ids of the rules caller may see.
+func ownIDs(caller string, ids map[string]string) []string {
+	if id, ok := ids[caller]; ok {
+		return []string{id}
+	}
+	return sortedIDs([]string{ids["alice"], ids["bob"]})
+}
+
+func sortedIDs(ids []string) []string {
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	return ids
+}
+
+func TestExchangeStatusEntitlements(t *testing.T) {
+	mon := ingest.NewMonitor()
+	mon.Track("binance", "BTCUSDT", 0)
+	mon.Track("binance", "ETHUSDT", 0)
+	s := NewServer(NewHub(), time.Second, WithMonitor(mon))
+	ctx := principals(t, auth.Client{ID: "c", Entitlements: auth.Entitlements{Symbols: []string{"BTC*"}}})["c"]
+
+	resp, err := s.GetExchangeStatus(ctx, &pricev1.ExchangeStatusRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, st := range resp.GetStatuses() {
+		got = append(got, st.GetSymbol())
+	}
+	if !slices.Equal(got, []string{"BTCUSDT"}) {
+		t.Errorf("statuses for %v, want [BTCUSDT]", got)
+	}
+
+	_, err = s.GetExchangeStatus(ctx, &pricev1.ExchangeStatusRequest{Symbol: "ETHUSDT"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ETHUSDT status: %v, want PermissionDenied", err)
+	}
+}
+
+func TestStreamAggregatesPermissionDenied(t *testing.T) {
+	a := authenticator(t,
+		auth.Client{ID: "alice"},
+		auth.Client{ID: "eth-only", Entitlements: auth.Entitlements{Symbols: []string{"ETH*"}}},
+		auth.Client{ID: "minutes", Entitlements: auth.Entitlements{IntervalsMs: []int64{60000}}},
+		auth.Client{ID: "coinbase", Entitlements: auth.Entitlements{Exchanges: []string{"coinbase"}}},
+	)
+	addr, hub := serve(t, WithAuth(a))
+	hub.Publish(&pricev1.Candle{Symbol: "BTCUSDT", WindowStartMs: 1000, WindowEndMs: 2000, Close: 100, ContributingVenues: []string{"binance"}})
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := pricev1.NewPriceStreamClient(conn)
+
+	tests := []struct {
+		client string
+		req    *pricev1.SubscribeRequest
+		code   codes.Code
+		reason string
+	}{
+		{"eth-only", &pricev1.SubscribeRequest{Symbol: "BTCUSDT"}, codes.PermissionDenied, "symbol not entitled"},
+		{"minutes", &pricev1.SubscribeRequest{Symbol: "BTCUSDT"}, codes.PermissionDenied, "interval 1000ms not entitled"},
+		{"coinbase", &pricev1.SubscribeRequest{Symbol: "BTCUSDT"}, codes.PermissionDenied, "exchange binance not entitled"},
+		// activity bars have no interval to check: this one gets past
+		// authorization to find no trade feed
+		{"minutes", &pricev1.SubscribeRequest{Symbol: "BTCUSDT", BarType: pricev1.BarType_BAR_TICK, BarSize: 10}, codes.Unknown, "activity-based bars not available"},
+		{"alice", &pricev1.SubscribeRequest{Symbol: "BTCUSDT"}, codes.DeadlineExceeded, ""},
+		{"mallory", &pricev1.SubscribeRequest{Symbol: "BTCUSDT"}, codes.Unauthenticated, "unknown api key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.client, func(t *testing.T) {
+			// an entitled stream stays open until the deadline
+			ctx, cancel := context.WithTimeout(metadata.AppendToOutgoingContext(context.Background(), "x-api-key", tt.client), 300*time.Millisecond)
+			defer cancel()
+			st, err := c.StreamAggregates(ctx, tt.req)
+			if err == nil {
+				_, err = st.Recv()
+			}
+			if status.Code(err) != tt.code || !strings.Contains(status.Convert(err).Message(), tt.reason) {
+				t.Errorf("StreamAggregates = %v, want %v with %q", err, tt.code, tt.reason)
+			}
+		})
+	}
+}
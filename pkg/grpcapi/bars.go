@@ -0,0 +1,76 @@
+// path: pkg/grpcapi/bars.go
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/binaridigital/price-engine/pkg/aggregate"
+	"github.com/binaridigital/price-engine/pkg/common"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// WithTrades enables tick, volume and dollar bars: each such subscription
+// builds its own bars from the trades published on t.
+func WithTrades(t *Topic[common.Trade]) ServerOption {
+	return func(s *Server) { s.trades = t }
+}
+
+func (s *Server) streamBars(req *pricev1.SubscribeRequest, stream grpc.ServerStream, method string, envelope bool) error {
+	if s.trades == nil {
+		return errors.New("activity-based bars not available on this engine instance")
+	}
+	b, err := aggregate.NewBarBuilder(req.GetSymbol(), req.GetBarType(), req.GetBarSize())
+	if err != nil {
+		return err
+	}
+	opts, err := subscribeOptions(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	mask, err := newFieldMask(req.GetFields())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	// a missed trade would corrupt every bar after it
+	ch, unsub := s.trades.SubscribeLossless(req.GetSymbol())
+	defer unsub()
+	// the same delivery options as time bars, applied per subscription
+	bars := newConflator(1024, opts)
+	defer bars.close()
+	ev, err := s.newEvents(req, stream, envelope)
+	if err != nil {
+		return err
+	}
+	defer ev.stop()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return context.Canceled
+		case now := <-ev.C():
+			if err := ev.tick(now); err != nil {
+				return err
+			}
+		case t, ok := <-ch:
+			if !ok {
+				return ev.resubscribe("subscriber fell behind the trade feed")
+			}
+			bars.offer(b.Add(t))
+		case c, ok := <-bars.ch:
+			if !ok {
+				return ev.resubscribe("subscriber fell behind")
+			}
+			if err := authorizeCandle(stream.Context(), method, c); err != nil {
+				return err
+			}
+			if err := ev.candle(mask.apply(c)); err != nil {
+				return err
+			}
+		}
+	}
+}
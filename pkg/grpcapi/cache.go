@@ -0,0 +1,160 @@
+// path: pkg/grpcapi/cache.go
+package grpcapi
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// CacheEntry is the cached state of one symbol.
+type CacheEntry struct {
+	Latest    *pricev1.Candle // latest update, partial or final
+	Final     *pricev1.Candle // last closed window; nil until one closed
+	FirstSeen time.Time
+	Updated   time.Time
+	Updates   uint64
+	Venues    map[string]struct{} // contributing venues and leg exchanges seen so far
+}
+
+// DefaultHistory is the number of closed windows kept per symbol.
+const DefaultHistory = 3600
+
+// Cache is the last-value cache the Hub maintains for the unary RPCs,
+// together with a bounded history of closed windows per symbol.
+type Cache struct {
+	mu         sync.RWMutex
+	symbols    map[string]*CacheEntry
+	history    map[string]*candleRing
+	historyLen int
+}
+
+// NewCache keeps up to historyLen closed windows per symbol; 0 keeps none.
+func NewCache(historyLen int) *Cache {
+	return &Cache{
+		symbols:    make(map[string]*CacheEntry),
+		history:    make(map[string]*candleRing),
+		historyLen: historyLen,
+	}
+}
+
+func (c *Cache) put(cd *pricev1.Candle) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.symbols[cd.GetSymbol()]
+	if e == nil {
+		e = &CacheEntry{FirstSeen: now, Venues: make(map[string]struct{})}
+		c.symbols[cd.GetSymbol()] = e
+	}
+	e.Latest = cd
+	if cd.GetIsFinal() {
+		e.Final = cd
+		if c.historyLen > 0 {
+			r := c.history[cd.GetSymbol()]
+			if r == nil {
+				r = &candleRing{buf: make([]*pricev1.Candle, 0, min(c.historyLen, 64)), max: c.historyLen}
+				c.history[cd.GetSymbol()] = r
+			}
+			r.push(cd)
+		}
+	}
+	e.Updated = now
+	e.Updates++
+	for _, v := range cd.GetContributingVenues() {
+		e.Venues[v] = struct{}{}
+	}
+	for _, l := range cd.GetSourceLegs() {
+		e.Venues[l.GetExchange()] = struct{}{}
+	}
+}
+
+// Get returns a copy of symbol's entry.
+func (c *Cache) Get(symbol string) (CacheEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.symbols[symbol]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	return e.copy(), true
+}
+
+// All returns a copy of every entry keyed by symbol.
+func (c *Cache) All() map[string]CacheEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make(map[string]CacheEntry, len(c.symbols))
+	for sym, e := range c.symbols {
+		out[sym] = e.copy()
+	}
+	return out
+}
+
+// Symbols returns the cached symbols, sorted.
+func (c *Cache) Symbols() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]string, 0, len(c.symbols))
+	for sym := range c.symbols {
+		out = append(out, sym)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// History returns symbol's closed windows starting in [from, to), oldest
+// first. A zero to means no upper bound; limit > 0 keeps the most recent
+// limit candles of the range.
+func (c *Cache) History(symbol string, from, to int64, limit int) []*pricev1.Candle {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	r := c.history[symbol]
+	if r == nil {
+		return nil
+	}
+	var out []*pricev1.Candle
+	r.each(func(cd *pricev1.Candle) {
+		start := cd.GetWindowStartMs()
+		if start >= from && (to == 0 || start < to) {
+			out = append(out, cd)
+		}
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out
+}
+
+// candleRing keeps the last max candles in arrival order.
+type candleRing struct {
+	buf  []*pricev1.Candle
+	next int // oldest element once the ring is full
+	max  int
+}
+
+func (r *candleRing) push(cd *pricev1.Candle) {
+	if len(r.buf) < r.max {
+		r.buf = append(r.buf, cd)
+		return
+	}
+	r.buf[r.next] = cd
+	r.next = (r.next + 1) % r.max
+}
+
+func (r *candleRing) each(f func(*pricev1.Candle)) {
+	for i := range r.buf {
+		f(r.buf[(r.next+i)%len(r.buf)])
+	}
+}
+
+func (e *CacheEntry) copy() CacheEntry {
+	cp := *e
+	cp.Venues = make(map[string]struct{}, len(e.Venues))
+	for v := range e.Venues {
+		cp.Venues[v] = struct{}{}
+	}
+	return cp
+}
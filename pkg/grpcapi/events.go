@@ -0,0 +1,181 @@
+// path: pkg/grpcapi/events.go
+package grpcapi
+
+import (
+	"cmp"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/binaridigital/price-engine/pkg/calendar"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// DefaultHeartbeat is how long a StreamAggregateEvents stream stays silent
+// before a heartbeat is sent, unless the request asks otherwise.
+const DefaultHeartbeat = 15 * time.Second
+
+// WithCalendars sets the trading calendars and halts behind market status
+// events (calendar.Default by default).
+func WithCalendars(r *calendar.Registry) ServerOption {
+	return func(s *Server) { s.calendars = r }
+}
+
+// WithHeartbeat sets the default heartbeat interval of StreamAggregateEvents.
+func WithHeartbeat(d time.Duration) ServerOption {
+	return func(s *Server) { s.heartbeat = d }
+}
+
+// errResubscribe ends a stream whose subscriber fell too far behind for
+// every candle to be delivered.
+var errResubscribe = status.Error(codes.Aborted, "subscriber fell behind; resubscribe and backfill with GetCandles")
+
+// events sends the messages of a candle stream. StreamAggregates gets the
+// bare candles; StreamAggregateEvents wraps them in AggregateEvents and
+// adds status events when the symbol's market or source state changes, and
+// heartbeats when the stream was otherwise silent.
+type events struct {
+	s        *Server
+	stream   grpc.ServerStream
+	envelope bool // StreamAggregateEvents
+	symbol   string
+	every    time.Duration
+	ticker   *time.Ticker
+	sent     time.Time // last message
+
+	started bool
+	market  calendar.Status
+	stale   string // comma-joined stale sources while the market is open
+}
+
+// newEvents prepares the messages of a StreamAggregates stream, or with
+// envelope of a StreamAggregateEvents stream: it then validates the
+// heartbeat interval and sends the initial status events.
+func (s *Server) newEvents(req *pricev1.SubscribeRequest, stream grpc.ServerStream, envelope bool) (*events, error) {
+	e := &events{s: s, stream: stream, symbol: req.GetSymbol()}
+	if !envelope {
+		return e, nil
+	}
+	ms := req.GetHeartbeatIntervalMs()
+	if ms != 0 && ms < 1000 {
+		return nil, status.Error(codes.InvalidArgument, "heartbeat_interval_ms must be 0 or >= 1000")
+	}
+	e.envelope = true
+	e.every = cmp.Or(time.Duration(ms)*time.Millisecond, s.heartbeat)
+	e.ticker = time.NewTicker(time.Second)
+	if err := e.tick(time.Now()); err != nil {
+		e.stop()
+		return nil, err
+	}
+	return e, nil
+}
+
+// C ticks every second; pass the time to tick. It never fires on a
+// StreamAggregates stream.
+func (e *events) C() <-chan time.Time {
+	if e.ticker == nil {
+		return nil
+	}
+	return e.ticker.C
+}
+
+func (e *events) stop() {
+	if e.ticker != nil {
+		e.ticker.Stop()
+	}
+}
+
+// send records at as the time of the last message; ticks pass their own
+// time so heartbeats stay on the tick grid.
+func (e *events) send(ev *pricev1.AggregateEvent, at time.Time) error {
+	e.sent = at
+	return e.stream.SendMsg(ev)
+}
+
+func (e *events) candle(c *pricev1.Candle) error {
+	if !e.envelope {
+		return e.stream.SendMsg(c)
+	}
+	return e.send(&pricev1.AggregateEvent{Event: &pricev1.AggregateEvent_Candle{Candle: c}}, time.Now())
+}
+
+func (e *events) status(st *pricev1.StreamStatus, at time.Time) error {
+	st.Symbol = e.symbol
+	st.TsMs = at.UnixMilli()
+	return e.send(&pricev1.AggregateEvent{Event: &pricev1.AggregateEvent_Status{Status: st}}, at)
+}
+
+// tick sends the status changes since the last tick, then a heartbeat if
+// nothing went out for the heartbeat interval.
+func (e *events) tick(now time.Time) error {
+	for _, st := range e.changes(now) {
+		if err := e.status(st, now); err != nil {
+			return err
+		}
+	}
+	if now.Sub(e.sent) < e.every {
+		return nil
+	}
+	hb := &pricev1.Heartbeat{ServerTimeMs: now.UnixMilli()}
+	if ce, ok := e.s.hub.Cache().Get(e.symbol); ok {
+		hb.LastUpdateMs = ce.Updated.UnixMilli()
+	}
+	return e.send(&pricev1.AggregateEvent{Event: &pricev1.AggregateEvent_Heartbeat{Heartbeat: hb}}, now)
+}
+
+// changes compares the symbol's market and source state with what the
+// client was last told. Stale sources are only reported while the market
+// is open: connectors of a closed market are expected to go quiet.
+func (e *events) changes(now time.Time) []*pricev1.StreamStatus {
+	var out []*pricev1.StreamStatus
+	ms := e.s.calendars.Status(e.symbol, now)
+	if !e.started || ms.State != e.market.State || ms.Reason != e.market.Reason || !ms.Until.Equal(e.market.Until) {
+		st := &pricev1.StreamStatus{Reason: ms.Reason}
+		switch ms.State {
+		case calendar.Open:
+			st.Kind = pricev1.StreamStatusKind_SK_MARKET_OPEN
+		case calendar.Closed:
+			st.Kind = pricev1.StreamStatusKind_SK_MARKET_CLOSED
+		case calendar.Halted:
+			st.Kind = pricev1.StreamStatusKind_SK_SYMBOL_HALTED
+			st.Reason = cmp.Or(st.Reason, "halted")
+		}
+		if !ms.Until.IsZero() {
+			st.NextOpenMs = ms.Until.UnixMilli()
+		}
+		out = append(out, st)
+		// a reopened market starts with a clean source state
+		e.market, e.stale = ms, ""
+	}
+	e.started = true
+	if ms.State != calendar.Open {
+		return out
+	}
+	stale := e.s.monitor.StaleSources(e.symbol)
+	if key := strings.Join(stale, ","); key != e.stale {
+		st := &pricev1.StreamStatus{Kind: pricev1.StreamStatusKind_SK_SOURCE_LIVE}
+		if len(stale) > 0 {
+			st.Kind = pricev1.StreamStatusKind_SK_SOURCE_STALE
+			st.Reason = "no data from " + strings.Join(stale, ", ")
+			st.StaleSources = stale
+		}
+		out = append(out, st)
+		e.stale = key
+	}
+	return out
+}
+
+// resubscribe tells the client to resubscribe and ends the stream.
+func (e *events) resubscribe(reason string) error {
+	if !e.envelope {
+		return errResubscribe
+	}
+	err := e.status(&pricev1.StreamStatus{
+		Kind:   pricev1.StreamStatusKind_SK_RESUBSCRIBE_REQUIRED,
+		Reason: reason,
+	}, time.Now())
+	return cmp.Or(err, errResubscribe)
+}
@@ -0,0 +1,97 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+func TestStreamAggregatesCandlesOnly(t *testing.T) {
+	addr, hub := serve(t)
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := pricev1.NewPriceStreamClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req := &pricev1.SubscribeRequest{Symbol: "BTCUSDT", HeartbeatIntervalMs: 1000}
+
+	candles, err := c.StreamAggregates(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := c.StreamAggregateEvents(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the events stream opens with the market status
+	ev, err := events.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.GetStatus().GetKind() != pricev1.StreamStatusKind_SK_MARKET_OPEN {
+		t.Fatalf("first event %v, want SK_MARKET_OPEN", ev)
+	}
+
+	// wait past a heartbeat interval: StreamAggregates must not send one
+	time.Sleep(1500 * time.Millisecond)
+	hub.Publish(&pricev1.Candle{Symbol: "BTCUSDT", WindowStartMs: 1000, WindowEndMs: 2000, Close: 101, IsFinal: true})
+
+	cd, err := candles.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cd.GetWindowStartMs() != 1000 || cd.GetClose() != 101 {
+		t.Errorf("StreamAggregates sent %v, want the published candle", cd)
+	}
+	var got []string
+	for {
+		ev, err := events.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case ev.GetHeartbeat() != nil:
+			got = append(got, "heartbeat")
+			continue
+		case ev.GetCandle().GetClose() == 101:
+			got = append(got, "candle")
+		default:
+			t.Fatalf("unexpected event %v", ev)
+		}
+		break
+	}
+	if len(got) != 2 || got[0] != "heartbeat" {
+		t.Errorf("StreamAggregateEvents sent %v, want a heartbeat, then the candle", got)
+	}
+}
+
+func TestHeartbeatIntervalValidation(t *testing.T) {
+	addr, _ := serve(t)
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := pricev1.NewPriceStreamClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req := &pricev1.SubscribeRequest{Symbol: "BTCUSDT", HeartbeatIntervalMs: 10}
+
+	events, err := c.StreamAggregateEvents(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := events.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("10ms heartbeat: %v, want InvalidArgument", err)
+	}
+}
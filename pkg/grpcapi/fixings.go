@@ -0,0 +1,59 @@
+// path: pkg/grpcapi/fixings.go
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// WithFixings enables StreamFixings. t should be keyed by fixing name
+// (see NewFixingTopic) so new subscribers get the last fixing of every
+// schedule.
+func WithFixings(t *Topic[*pricev1.Fixing]) ServerOption {
+	return func(s *Server) { s.fixings = t }
+}
+
+// NewFixingTopic returns a topic for WithFixings.
+func NewFixingTopic(buf int) *Topic[*pricev1.Fixing] {
+	return NewKeyedTopic(buf, (*pricev1.Fixing).GetName)
+}
+
+func (s *Server) StreamFixings(req *pricev1.FixingRequest, stream pricev1.PriceStream_StreamFixingsServer) error {
+	if req.GetSymbol() == "" {
+		return errors.New("symbol required")
+	}
+	if s.fixings == nil {
+		return errors.New("fixings not available on this engine instance")
+	}
+	if err := s.authorize(stream.Context(), pricev1.PriceStream_StreamFixings_FullMethodName, req.GetSymbol(), 0); err != nil {
+		return err
+	}
+	match := func(f *pricev1.Fixing) bool {
+		return req.GetName() == "" || f.GetName() == req.GetName()
+	}
+	ch, unsub := s.fixings.Subscribe(req.GetSymbol())
+	defer unsub()
+	for _, f := range s.fixings.LastAll(req.GetSymbol()) {
+		if !match(f) {
+			continue
+		}
+		if err := stream.Send(f); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return context.Canceled
+		case f := <-ch:
+			if !match(f) {
+				continue
+			}
+			if err := stream.Send(f); err != nil {
+				return err
+			}
+		}
+	}
+}
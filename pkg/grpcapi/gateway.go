@@ -0,0 +1,507 @@
+// path: pkg/grpcapi/gateway.go
+package grpcapi
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"nhooyr.io/websocket"
+
+	"github.com/binaridigital/price-engine/pkg/auth"
+	"github.com/binaridigital/price-engine/pkg/metering"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// gateway exposes the Hub as JSON over HTTP under /v1/:
+//
+//	GET /v1/stream/ws       WebSocket; subscriptions from the query and from client messages
+//	GET /v1/stream/sse      Server-Sent Events for the symbols in the query
+//	GET /v1/prices/latest   GetLatestPrice
+//	GET /v1/snapshot        GetSnapshot
+//	GET /v1/symbols         ListSymbols
+//	GET /v1/candles         GetCandles
+//
+// Subscriptions take the SubscribeRequest options as query parameters
+// (symbol, interval_ms, final_only, max_updates_per_sec, fields) and go
+// through the same validation as StreamAggregates. Each subscription takes
+// one of the client's max_streams slots, and a connection carries at most
+// maxConnSubscriptions. Errors use the Connect JSON error format.
+type gateway struct {
+	s   *Server
+	ws  websocket.AcceptOptions
+	mux *http.ServeMux
+}
+
+// keepAlive is how often idle streams get an SSE comment or WebSocket ping.
+const keepAlive = 15 * time.Second
+
+// maxConnSubscriptions bounds the subscriptions of one SSE or WebSocket
+// connection.
+const maxConnSubscriptions = 64
+
+var errConnSubscriptions = status.Errorf(codes.ResourceExhausted, "at most %d subscriptions per connection", maxConnSubscriptions)
+
+func newGateway(s *Server, corsOrigins []string) *gateway {
+	g := &gateway{s: s, mux: http.NewServeMux()}
+	for _, o := range corsOrigins {
+		if o == "*" {
+			g.ws.InsecureSkipVerify = true
+			continue
+		}
+		if u, err := url.Parse(o); err == nil && u.Host != "" {
+			g.ws.OriginPatterns = append(g.ws.OriginPatterns, u.Host)
+		}
+	}
+	g.mux.HandleFunc("GET /v1/stream/ws", g.serveWS)
+	g.mux.HandleFunc("GET /v1/stream/sse", g.serveSSE)
+	g.mux.HandleFunc("GET /v1/prices/latest", g.latest)
+	g.mux.HandleFunc("GET /v1/snapshot", g.snapshot)
+	g.mux.HandleFunc("GET /v1/symbols", g.symbols)
+	g.mux.HandleFunc("GET /v1/candles", g.candles)
+	g.mux.HandleFunc("/v1/", func(w http.ResponseWriter, r *http.Request) {
+		writeConnectError(w, codes.NotFound, "no such endpoint")
+	})
+	return g
+}
+
+func (g *gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) { g.mux.ServeHTTP(w, r) }
+
+func writeGatewayError(w http.ResponseWriter, err error) {
+	st, _ := status.FromError(err)
+	writeConnectError(w, st.Code(), st.Message())
+}
+
+// listParam collects a parameter given repeatedly or comma-separated.
+func listParam(q url.Values, name string) []string {
+	var out []string
+	for _, v := range q[name] {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+func intParam(q url.Values, name string) (int64, error) {
+	v := q.Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "%s: %v", name, err)
+	}
+	return n, nil
+}
+
+// subscribeRequests builds one SubscribeRequest per symbol in the query.
+func subscribeRequests(q url.Values) ([]*pricev1.SubscribeRequest, error) {
+	base := &pricev1.SubscribeRequest{Fields: listParam(q, "fields")}
+	var err error
+	if base.IntervalMs, err = intParam(q, "interval_ms"); err != nil {
+		return nil, err
+	}
+	if v := q.Get("final_only"); v != "" {
+		if base.FinalOnly, err = strconv.ParseBool(v); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "final_only: %v", err)
+		}
+	}
+	if v := q.Get("max_updates_per_sec"); v != "" {
+		if base.MaxUpdatesPerSec, err = strconv.ParseFloat(v, 64); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "max_updates_per_sec: %v", err)
+		}
+	}
+	var reqs []*pricev1.SubscribeRequest
+	for _, sym := range listParam(q, "symbol") {
+		req := proto.Clone(base).(*pricev1.SubscribeRequest)
+		req.Symbol = strings.ToUpper(sym)
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+// subscription forwards one Hub subscription into a connection's shared
+// channel until stopped.
+type subscription struct {
+	unsub   func()
+	release func() // the client's stream slot
+	done    chan struct{}
+	meter   *metering.Stream // nil without metering
+}
+
+// streamMsg is a candle in JSON, or the error that ended a subscription.
+type streamMsg struct {
+	sub    *subscription
+	symbol string
+	candle []byte
+	err    error
+}
+
+func (g *gateway) start(ctx context.Context, method string, req *pricev1.SubscribeRequest, out chan<- streamMsg) (*subscription, error) {
+	if req.GetSymbol() == "" {
+		return nil, status.Error(codes.InvalidArgument, "symbol required")
+	}
+	if req.GetBarType() != pricev1.BarType_BAR_TIME {
+		return nil, status.Error(codes.InvalidArgument, "only time bars are available over the gateway")
+	}
+	if err := g.s.authorize(ctx, method, req.GetSymbol(), cmp.Or(req.GetIntervalMs(), g.s.engineIntervalMs)); err != nil {
+		return nil, err
+	}
+	release, err := acquireStream(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+	ch, unsub, mask, err := g.s.subscribe(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	sub := &subscription{unsub: unsub, release: release, done: make(chan struct{})}
+	if g.s.meter != nil {
+		client, q := meterClient(ctx)
+		sub.meter = g.s.meter.Open(client, req.GetSymbol(), q)
+	}
+	go func() {
+		for c := range ch {
+			msg := streamMsg{sub: sub, symbol: c.GetSymbol()}
+			if msg.err = authorizeCandle(ctx, method, c); msg.err == nil {
+				if msg.candle, msg.err = protojson.Marshal(mask.apply(c)); msg.err != nil {
+					continue
+				}
+				if sub.meter != nil {
+					switch sub.meter.Send(len(msg.candle), !c.GetIsFinal()) {
+					case metering.Drop:
+						continue
+					case metering.Stop:
+						if p, ok := auth.FromContext(ctx); ok {
+							p.Deny(method, c.GetSymbol(), "daily quota exceeded")
+						}
+						msg.candle, msg.err = nil, errQuota
+					}
+				}
+			}
+			select {
+			case out <- msg:
+			case <-sub.done:
+				return
+			}
+			if msg.err != nil {
+				return
+			}
+		}
+		// the Hub cut the subscriber off unless it was stopped; stop
+		// closes done before it unsubscribes
+		select {
+		case <-sub.done:
+			return
+		default:
+		}
+		select {
+		case <-sub.done:
+		case out <- streamMsg{sub: sub, symbol: req.GetSymbol(), err: errResubscribe}:
+		}
+	}()
+	return sub, nil
+}
+
+func (sub *subscription) stop() {
+	close(sub.done)
+	sub.unsub()
+	sub.release()
+	if sub.meter != nil {
+		sub.meter.Close()
+	}
+}
+
+func (g *gateway) serveSSE(w http.ResponseWriter, r *http.Request) {
+	reqs, err := subscribeRequests(r.URL.Query())
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+	if len(reqs) == 0 {
+		writeGatewayError(w, status.Error(codes.InvalidArgument, "symbol required"))
+		return
+	}
+	if len(reqs) > maxConnSubscriptions {
+		writeGatewayError(w, errConnSubscriptions)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeGatewayError(w, status.Error(codes.Internal, "streaming not supported"))
+		return
+	}
+	const method = "GET /v1/stream/sse"
+	if g.s.meter != nil && quotaDenied(r.Context(), g.s.meter, method) {
+		writeGatewayError(w, errQuota)
+		return
+	}
+	out := make(chan streamMsg, 256)
+	for _, req := range reqs {
+		sub, err := g.start(r.Context(), method, req, out)
+		if err != nil {
+			writeGatewayError(w, err)
+			return
+		}
+		defer sub.stop()
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	ping := time.NewTicker(keepAlive)
+	defer ping.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ping.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case m := <-out:
+			if m.err != nil {
+				st, _ := status.FromError(m.err)
+				b, _ := json.Marshal(connectError(st.Code(), st.Message()))
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", b)
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: candle\ndata: %s\n\n", m.candle); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// wsCommand is a client message on the WebSocket: a SubscribeRequest in
+// JSON plus "op" ("subscribe" or "unsubscribe").
+type wsCommand struct {
+	Op string `json:"op"`
+}
+
+// wsEvent is a server message on the WebSocket.
+type wsEvent struct {
+	Type    string          `json:"type"` // candle, subscribed, unsubscribed, error
+	Symbol  string          `json:"symbol,omitempty"`
+	Candle  json.RawMessage `json:"candle,omitempty"`
+	Message string          `json:"message,omitempty"`
+}
+
+func (g *gateway) serveWS(w http.ResponseWriter, r *http.Request) {
+	initial, err := subscribeRequests(r.URL.Query())
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+	if len(initial) > maxConnSubscriptions {
+		writeGatewayError(w, errConnSubscriptions)
+		return
+	}
+	const method = "GET /v1/stream/ws"
+	if g.s.meter != nil && quotaDenied(r.Context(), g.s.meter, method) {
+		writeGatewayError(w, errQuota)
+		return
+	}
+	conn, err := websocket.Accept(w, r, &g.ws)
+	if err != nil {
+		return
+	}
+	defer conn.CloseNow()
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	out := make(chan streamMsg, 256)
+	events := make(chan wsEvent, len(initial)+16)
+	var mu sync.Mutex
+	subs := make(map[string]*subscription)
+	defer func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, sub := range subs {
+			sub.stop()
+		}
+	}()
+	handle := func(op string, req *pricev1.SubscribeRequest) wsEvent {
+		sym := req.GetSymbol()
+		mu.Lock()
+		defer mu.Unlock()
+		switch op {
+		case "subscribe":
+			if old := subs[sym]; old != nil {
+				// resubscribing replaces the options
+				old.stop()
+				delete(subs, sym)
+			} else if len(subs) >= maxConnSubscriptions {
+				return wsEvent{Type: "error", Symbol: sym, Message: status.Convert(errConnSubscriptions).Message()}
+			}
+			sub, err := g.start(ctx, method, req, out)
+			if err != nil {
+				st, _ := status.FromError(err)
+				return wsEvent{Type: "error", Symbol: sym, Message: st.Message()}
+			}
+			subs[sym] = sub
+			return wsEvent{Type: "subscribed", Symbol: sym}
+		case "unsubscribe":
+			if sub := subs[sym]; sub != nil {
+				sub.stop()
+				delete(subs, sym)
+			}
+			return wsEvent{Type: "unsubscribed", Symbol: sym}
+		}
+		return wsEvent{Type: "error", Message: fmt.Sprintf("unknown op %q", op)}
+	}
+	for _, req := range initial {
+		events <- handle("subscribe", req)
+	}
+
+	// reader: client commands
+	go func() {
+		defer cancel()
+		for {
+			_, b, err := conn.Read(ctx)
+			if err != nil {
+				return
+			}
+			var cmd wsCommand
+			req := &pricev1.SubscribeRequest{}
+			err = json.Unmarshal(b, &cmd)
+			if err == nil {
+				err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(b, req)
+			}
+			ev := wsEvent{Type: "error", Message: "malformed message"}
+			if err == nil {
+				req.Symbol = strings.ToUpper(req.GetSymbol())
+				ev = handle(cmd.Op, req)
+			}
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(keepAlive)
+	defer ping.Stop()
+	for {
+		var msg wsEvent
+		select {
+		case <-ctx.Done():
+			conn.Close(websocket.StatusNormalClosure, "")
+			return
+		case <-ping.C:
+			pctx, pcancel := context.WithTimeout(ctx, keepAlive)
+			err := conn.Ping(pctx)
+			pcancel()
+			if err != nil {
+				return
+			}
+			continue
+		case msg = <-events:
+		case m := <-out:
+			mu.Lock()
+			current := subs[m.symbol] == m.sub
+			if current && m.err != nil {
+				// the subscription ended itself; forget it
+				m.sub.stop()
+				delete(subs, m.symbol)
+			}
+			mu.Unlock()
+			if !current {
+				// queued before an unsubscribe or resubscribe
+				continue
+			}
+			msg = wsEvent{Type: "candle", Candle: m.candle}
+			if m.err != nil {
+				st, _ := status.FromError(m.err)
+				msg = wsEvent{Type: "error", Symbol: m.symbol, Message: st.Message()}
+			}
+		}
+		b, _ := json.Marshal(msg)
+		if err := conn.Write(ctx, websocket.MessageText, b); err != nil {
+			return
+		}
+	}
+}
+
+// unary serves a REST endpoint: quota check, call, JSON response and
+// metering, as the interceptors do for gRPC.
+func (g *gateway) unary(w http.ResponseWriter, r *http.Request, symbol string, call func(context.Context) (proto.Message, error)) {
+	ctx := r.Context()
+	if g.s.meter != nil && quotaDenied(ctx, g.s.meter, r.Method+" "+r.URL.Path) {
+		writeGatewayError(w, errQuota)
+		return
+	}
+	resp, err := call(ctx)
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+	b, err := protojson.Marshal(resp)
+	if err != nil {
+		writeGatewayError(w, status.Error(codes.Internal, err.Error()))
+		return
+	}
+	if g.s.meter != nil {
+		client, _ := meterClient(ctx)
+		g.s.meter.Record(client, strings.ToUpper(symbol), len(b))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+func (g *gateway) latest(w http.ResponseWriter, r *http.Request) {
+	req := &pricev1.LatestPriceRequest{Symbols: listParam(r.URL.Query(), "symbol")}
+	g.unary(w, r, "", func(ctx context.Context) (proto.Message, error) { return g.s.GetLatestPrice(ctx, req) })
+}
+
+func (g *gateway) snapshot(w http.ResponseWriter, r *http.Request) {
+	g.unary(w, r, "", func(ctx context.Context) (proto.Message, error) {
+		return g.s.GetSnapshot(ctx, &pricev1.SnapshotRequest{})
+	})
+}
+
+func (g *gateway) symbols(w http.ResponseWriter, r *http.Request) {
+	g.unary(w, r, "", func(ctx context.Context) (proto.Message, error) {
+		return g.s.ListSymbols(ctx, &pricev1.ListSymbolsRequest{})
+	})
+}
+
+func (g *gateway) candles(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := &pricev1.CandlesRequest{Symbol: q.Get("symbol")}
+	var err error
+	var limit int64
+	if req.FromMs, err = intParam(q, "from_ms"); err == nil {
+		if req.ToMs, err = intParam(q, "to_ms"); err == nil {
+			limit, err = intParam(q, "limit")
+		}
+	}
+	if err == nil && (limit < 0 || limit > 1<<31-1) {
+		err = status.Error(codes.InvalidArgument, "limit out of range")
+	}
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+	req.Limit = int32(limit)
+	g.unary(w, r, req.GetSymbol(), func(ctx context.Context) (proto.Message, error) { return g.s.GetCandles(ctx, req) })
+}
@@ -0,0 +1,278 @@
+package grpcapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nhooyr.io/websocket"
+
+	"github.com/binaridigital/price-engine/pkg/auth"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// gatewayServer serves the gateway with API-key auth for clients until the
+// test ends and returns its URL and the hub behind it. The hub holds one
+// candle for BTCUSDT.
+func gatewayServer(t *testing.T, clients ...auth.Client) (string, *Hub) {
+	t.Helper()
+	hub := NewHub()
+	hub.Publish(&pricev1.Candle{Symbol: "BTCUSDT", WindowStartMs: 0, WindowEndMs: 1000, Close: 100, IsFinal: true})
+	a := authenticator(t, clients...)
+	srv := httptest.NewServer(httpAuth(a, newGateway(NewServer(hub, time.Second), nil)))
+	t.Cleanup(srv.Close)
+	return srv.URL, hub
+}
+
+func gatewayGet(t *testing.T, url, key string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "" {
+		req.Header.Set("X-Api-Key", key)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+// checkError reads a Connect JSON error from resp.
+func checkError(t *testing.T, resp *http.Response, status int, code string) {
+	t.Helper()
+	defer resp.Body.Close()
+	var e struct{ Code, Message string }
+	if err := json.NewDecoder(resp.Body).Decode(&e); err != nil {
+		t.Fatalf("error body: %v", err)
+	}
+	if resp.StatusCode != status || e.Code != code || e.Message == "" {
+		t.Errorf("got %d %+v, want %d with code %s", resp.StatusCode, e, status, code)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type %q", ct)
+	}
+}
+
+// symbols returns n symbols for a query, SYM0USDT,SYM1USDT,...
+func symbols(n int) string {
+	s := make([]string, n)
+	for i := range s {
+		s[i] = fmt.Sprintf("SYM%dUSDT", i)
+	}
+	return strings.Join(s, ",")
+}
+
+func TestGatewayREST(t *testing.T) {
+	base, _ := gatewayServer(t,
+		auth.Client{ID: "alice"},
+		auth.Client{ID: "eth-only", Entitlements: auth.Entitlements{Symbols: []string{"ETH*"}}},
+	)
+
+	resp := gatewayGet(t, base+"/v1/prices/latest?symbol=btcusdt", "alice")
+	var latest struct {
+		Prices []struct {
+			Symbol string
+			Price  float64
+		}
+	}
+	err := json.NewDecoder(resp.Body).Decode(&latest)
+	resp.Body.Close()
+	if err != nil || resp.StatusCode != http.StatusOK || len(latest.Prices) != 1 || latest.Prices[0].Price != 100 {
+		t.Errorf("latest: %d %+v %v", resp.StatusCode, latest, err)
+	}
+
+	tests := []struct {
+		path, key string
+		status    int
+		code      string
+	}{
+		{"/v1/candles?symbol=BTCUSDT", "", http.StatusUnauthorized, "unauthenticated"},
+		{"/v1/candles?symbol=BTCUSDT", "mallory", http.StatusUnauthorized, "unauthenticated"},
+		{"/v1/candles?symbol=BTCUSDT", "eth-only", http.StatusForbidden, "permission_denied"},
+		{"/v1/candles?symbol=BTCUSDT&limit=x", "alice", http.StatusBadRequest, "invalid_argument"},
+		{"/v1/candles?symbol=BTCUSDT&limit=-1", "alice", http.StatusBadRequest, "invalid_argument"},
+		{"/v1/candles?symbol=XRPUSDT", "alice", http.StatusNotFound, "not_found"},
+		{"/v1/nothing", "alice", http.StatusNotFound, "not_found"},
+		{"/v1/stream/sse", "alice", http.StatusBadRequest, "invalid_argument"},
+		{"/v1/stream/sse?symbol=BTCUSDT&interval_ms=60000", "alice", http.StatusBadRequest, "invalid_argument"},
+		{"/v1/stream/sse?symbol=BTCUSDT&final_only=maybe", "alice", http.StatusBadRequest, "invalid_argument"},
+		{"/v1/stream/sse?symbol=" + symbols(maxConnSubscriptions+1), "alice", http.StatusTooManyRequests, "resource_exhausted"},
+		{"/v1/stream/ws?symbol=" + symbols(maxConnSubscriptions+1), "alice", http.StatusTooManyRequests, "resource_exhausted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			checkError(t, gatewayGet(t, base+tt.path, tt.key), tt.status, tt.code)
+		})
+	}
+}
+
+// sseStream opens an SSE stream and returns its events as "event data"
+// lines.
+func sseStream(t *testing.T, ctx context.Context, url, key string) (*http.Response, <-chan string) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Api-Key", key)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := make(chan string, 16)
+	if resp.StatusCode != http.StatusOK {
+		close(events)
+		return resp, events
+	}
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+		sc := bufio.NewScanner(resp.Body)
+		var event string
+		for sc.Scan() {
+			line := sc.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				events <- event + " " + strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	return resp, events
+}
+
+func next(t *testing.T, events <-chan string) string {
+	t.Helper()
+	select {
+	case ev := <-events:
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+		return ""
+	}
+}
+
+func TestGatewaySSE(t *testing.T) {
+	base, hub := gatewayServer(t, auth.Client{ID: "alice", Entitlements: auth.Entitlements{MaxStreams: 2}})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	resp, events := sseStream(t, ctx, base+"/v1/stream/sse?symbol=btcusdt,ethusdt&fields=close", "alice")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("SSE: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	hub.Publish(&pricev1.Candle{Symbol: "ETHUSDT", WindowStartMs: 1000, WindowEndMs: 2000, Close: 3000, Volume: 5})
+	ev := next(t, events)
+	var cd map[string]any
+	if data, ok := strings.CutPrefix(ev, "candle "); !ok || json.Unmarshal([]byte(data), &cd) != nil || cd["close"] != 3000.0 || cd["volume"] != nil {
+		t.Errorf("event %q, want the masked ETHUSDT candle", ev)
+	}
+
+	// both slots are taken by the two subscriptions
+	checkError(t, gatewayGet(t, base+"/v1/stream/sse?symbol=BTCUSDT", "alice"), http.StatusTooManyRequests, "resource_exhausted")
+	cancel()
+	for range events {
+	}
+	// the slots are released with the stream
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, _ := sseStream(t, t.Context(), base+"/v1/stream/sse?symbol=BTCUSDT,ETHUSDT", "alice")
+		if resp.StatusCode == http.StatusOK {
+			resp.Body.Close()
+			break
+		}
+		resp.Body.Close()
+		if time.Now().After(deadline) {
+			t.Fatalf("stream slots not released: %d", resp.StatusCode)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func wsRead(t *testing.T, ctx context.Context, c *websocket.Conn) wsEvent {
+	t.Helper()
+	_, b, err := c.Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ev wsEvent
+	if err := json.Unmarshal(b, &ev); err != nil {
+		t.Fatal(err)
+	}
+	return ev
+}
+
+func TestGatewayWS(t *testing.T) {
+	base, hub := gatewayServer(t, auth.Client{ID: "alice", Entitlements: auth.Entitlements{MaxStreams: 2}})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	url := "ws" + strings.TrimPrefix(base, "http") + "/v1/stream/ws?symbol=btcusdt&api_key=alice"
+	c, _, err := websocket.Dial(ctx, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.CloseNow()
+	if ev := wsRead(t, ctx, c); ev.Type != "subscribed" || ev.Symbol != "BTCUSDT" {
+		t.Fatalf("first event %+v", ev)
+	}
+
+	steps := []struct {
+		send string
+		want wsEvent
+	}{
+		{`{"op":"subscribe","symbol":"ethusdt"}`, wsEvent{Type: "subscribed", Symbol: "ETHUSDT"}},
+		// alice's two stream slots are taken
+		{`{"op":"subscribe","symbol":"SOLUSDT"}`, wsEvent{Type: "error", Symbol: "SOLUSDT", Message: "too many concurrent streams: limit 2"}},
+		// resubscribing replaces a subscription and reuses its slot
+		{`{"op":"subscribe","symbol":"ETHUSDT","fields":["close"]}`, wsEvent{Type: "subscribed", Symbol: "ETHUSDT"}},
+		{`{"op":"unsubscribe","symbol":"BTCUSDT"}`, wsEvent{Type: "unsubscribed", Symbol: "BTCUSDT"}},
+		{`{"op":"subscribe","symbol":"SOLUSDT"}`, wsEvent{Type: "subscribed", Symbol: "SOLUSDT"}},
+		{`{"op":"subscribe","symbol":"ETHUSDT","interval_ms":60000}`, wsEvent{Type: "error", Symbol: "ETHUSDT", Message: "requested interval not supported by current engine instance"}},
+		{`{"op":"fly","symbol":"ETHUSDT"}`, wsEvent{Type: "error", Message: `unknown op "fly"`}},
+		{`{"op":`, wsEvent{Type: "error", Message: "malformed message"}},
+	}
+	for _, st := range steps {
+		if err := c.Write(ctx, websocket.MessageText, []byte(st.send)); err != nil {
+			t.Fatal(err)
+		}
+		if ev := wsRead(t, ctx, c); ev.Type != st.want.Type || ev.Symbol != st.want.Symbol || ev.Message != st.want.Message {
+			t.Errorf("%s: %+v, want %+v", st.send, ev, st.want)
+		}
+	}
+
+	// the failed resubscription dropped ETHUSDT: only SOLUSDT is live
+	hub.Publish(&pricev1.Candle{Symbol: "ETHUSDT", WindowStartMs: 1000, WindowEndMs: 2000, Close: 3000})
+	hub.Publish(&pricev1.Candle{Symbol: "SOLUSDT", WindowStartMs: 1000, WindowEndMs: 2000, Close: 150})
+	ev := wsRead(t, ctx, c)
+	var cd pricev1.Candle
+	if ev.Type != "candle" || json.Unmarshal(ev.Candle, &cd) != nil || cd.GetSymbol() != "SOLUSDT" {
+		t.Errorf("event %+v, want the SOLUSDT candle", ev)
+	}
+
+	// closing the socket releases the slots
+	c.Close(websocket.StatusNormalClosure, "")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, events := sseStream(t, t.Context(), base+"/v1/stream/sse?symbol=BTCUSDT,ETHUSDT", "alice")
+		resp.Body.Close()
+		for range events {
+		}
+		if resp.StatusCode == http.StatusOK {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("stream slots not released: %d", resp.StatusCode)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,119 @@
+// path: pkg/grpcapi/health.go
+package grpcapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/binaridigital/price-engine/pkg/calendar"
+	"github.com/binaridigital/price-engine/pkg/ingest"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// DefaultMaxLag is how long the engine stays ready without publishing a
+// candle.
+const DefaultMaxLag = 30 * time.Second
+
+// WithMaxLag marks the engine not ready when no candle was published for
+// longer than d while a market is open.
+func WithMaxLag(d time.Duration) ServerOption {
+	return func(s *Server) { s.maxLag = d }
+}
+
+// isHealthMethod reports whether a gRPC method belongs to the health
+// service, which probes call without credentials and which is not metered.
+func isHealthMethod(method string) bool {
+	return strings.HasPrefix(method, "/grpc.health.v1.Health/")
+}
+
+// Ready returns why the engine cannot serve prices, or nil: at least one
+// trade connector must be live and, while the market of any of the
+// engine's symbols is open, a candle for one of them must have been
+// published within the max lag. Depth and quote feeds are not counted,
+// and markets their calendar closes or halts cannot make the engine lag.
+func (s *Server) Ready() error {
+	now := time.Now()
+	symbols := make(map[string]struct{})
+	if s.monitor != nil {
+		live := false
+		for _, st := range s.monitor.Snapshot() {
+			if st.Kind != ingest.FeedTrades {
+				continue
+			}
+			symbols[strings.ToUpper(st.Symbol)] = struct{}{}
+			live = live || st.State == ingest.StateLive
+		}
+		if !live {
+			return errors.New("no live connector")
+		}
+	}
+	entries := s.hub.Cache().All()
+	for sym := range entries {
+		symbols[sym] = struct{}{}
+	}
+	open := false
+	var last time.Time
+	for sym := range symbols {
+		if s.calendars.Status(sym, now).State != calendar.Open {
+			continue
+		}
+		open = true
+		if e, ok := entries[sym]; ok && e.Updated.After(last) {
+			last = e.Updated
+		}
+	}
+	if len(symbols) > 0 && !open {
+		return nil
+	}
+	if last.IsZero() {
+		return errors.New("no candle published yet")
+	}
+	if lag := now.Sub(last); lag > s.maxLag {
+		return fmt.Errorf("no candle published for %s", lag.Truncate(time.Second))
+	}
+	return nil
+}
+
+// watchHealth mirrors Ready into the gRPC health service, for the whole
+// server ("") and for PriceStream, until stop is closed.
+func (s *Server) watchHealth(hs *health.Server, stop <-chan struct{}) {
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+	for {
+		st := healthpb.HealthCheckResponse_SERVING
+		if s.Ready() != nil {
+			st = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		hs.SetServingStatus("", st)
+		hs.SetServingStatus(pricev1.PriceStream_ServiceDesc.ServiceName, st)
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// healthz is the liveness probe: the server answers HTTP.
+func healthz(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+// readyz is the readiness probe: 200 when Ready, 503 with the reason
+// otherwise.
+func (s *Server) readyz(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err := s.Ready(); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, err)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
@@ -0,0 +1,102 @@
+package grpcapi
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/calendar"
+	"github.com/binaridigital/price-engine/pkg/ingest"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+func TestReady(t *testing.T) {
+	// EURUSD is halted: its market is closed for the test
+	file := filepath.Join(t.TempDir(), "calendars.json")
+	if err := os.WriteFile(file, []byte(`{"halts":[{"symbol":"EURUSD","reason":"test"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cals, err := calendar.Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type feed struct {
+		kind   ingest.FeedKind
+		symbol string
+	}
+	tests := []struct {
+		name      string
+		live      []feed   // live trackers; nil = no monitor
+		published []string // symbols with a candle, published before the lag
+		late      bool     // the lag has passed since
+		want      string   // prefix of the error; "" = ready
+	}{
+		{name: "nothing published", want: "no candle published yet"},
+		{name: "published", published: []string{"BTCUSDT"}},
+		{name: "lagging", published: []string{"BTCUSDT"}, late: true, want: "no candle published for"},
+		{
+			name: "only a depth feed is live",
+			live: []feed{{ingest.FeedDepth, "BTCUSDT"}},
+			want: "no live connector",
+		},
+		{
+			name:      "trade feed live",
+			live:      []feed{{ingest.FeedTrades, "BTCUSDT"}, {ingest.FeedDepth, "BTCUSDT"}},
+			published: []string{"BTCUSDT"},
+		},
+		{
+			name: "market closed",
+			live: []feed{{ingest.FeedTrades, "EURUSD"}},
+		},
+		{
+			name:      "market closed and lagging",
+			live:      []feed{{ingest.FeedTrades, "EURUSD"}},
+			published: []string{"EURUSD"},
+			late:      true,
+		},
+		{
+			name:      "only the closed market published",
+			live:      []feed{{ingest.FeedTrades, "EURUSD"}, {ingest.FeedTrades, "BTCUSDT"}},
+			published: []string{"EURUSD"},
+			want:      "no candle published yet",
+		},
+		{
+			name:      "open market lagging",
+			live:      []feed{{ingest.FeedTrades, "EURUSD"}, {ingest.FeedTrades, "BTCUSDT"}},
+			published: []string{"BTCUSDT"},
+			late:      true,
+			want:      "no candle published for",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const lag = 20 * time.Millisecond
+			hub := NewHub()
+			opts := []ServerOption{WithCalendars(cals), WithMaxLag(lag)}
+			if tt.live != nil {
+				mon := ingest.NewMonitor()
+				for _, f := range tt.live {
+					mon.TrackFeed(f.kind, "binance", f.symbol, 0).Live()
+				}
+				opts = append(opts, WithMonitor(mon))
+			}
+			s := NewServer(hub, time.Second, opts...)
+			for _, sym := range tt.published {
+				hub.Publish(&pricev1.Candle{Symbol: sym})
+			}
+			if tt.late {
+				time.Sleep(2 * lag)
+			}
+			err := s.Ready()
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("not ready: %v", err)
+			case tt.want != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.want)):
+				t.Errorf("Ready() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
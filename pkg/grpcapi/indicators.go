@@ -0,0 +1,46 @@
+// path: pkg/grpcapi/indicators.go
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// WithIndicators enables StreamIndicators.
+func WithIndicators(t *Topic[*pricev1.IndicatorUpdate]) ServerOption {
+	return func(s *Server) { s.indicators = t }
+}
+
+func (s *Server) StreamIndicators(req *pricev1.SubscribeRequest, stream pricev1.PriceStream_StreamIndicatorsServer) error {
+	if req.GetSymbol() == "" {
+		return errors.New("symbol required")
+	}
+	if s.indicators == nil {
+		return errors.New("indicators not available on this engine instance")
+	}
+	if req.GetIntervalMs() != 0 && req.GetIntervalMs() != s.engineIntervalMs {
+		return errors.New("requested interval not supported by current engine instance")
+	}
+	if err := s.authorize(stream.Context(), pricev1.PriceStream_StreamIndicators_FullMethodName, req.GetSymbol(), 0); err != nil {
+		return err
+	}
+	ch, unsub := s.indicators.Subscribe(req.GetSymbol())
+	defer unsub()
+	if u, ok := s.indicators.Last(req.GetSymbol()); ok {
+		if err := stream.Send(u); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return context.Canceled
+		case u := <-ch:
+			if err := stream.Send(u); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// path: pkg/grpcapi/latest.go
+package grpcapi
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/binaridigital/price-engine/pkg/ingest"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+func (s *Server) GetLatestPrice(ctx context.Context, req *pricev1.LatestPriceRequest) (*pricev1.LatestPriceResponse, error) {
+	cache := s.hub.Cache()
+	symbols := req.GetSymbols()
+	if len(symbols) == 0 {
+		symbols = cache.Symbols()
+	}
+	resp := &pricev1.LatestPriceResponse{}
+	for _, sym := range symbols {
+		sym = strings.ToUpper(strings.TrimSpace(sym))
+		e, ok := cache.Get(sym)
+		if !ok || !allowed(ctx, sym) {
+			resp.Missing = append(resp.Missing, sym)
+			continue
+		}
+		c := e.Latest
+		resp.Prices = append(resp.Prices, &pricev1.LatestPrice{
+			Symbol:         sym,
+			Price:          c.GetClose(),
+			Vwap:           c.GetVwap(),
+			ConsensusPrice: c.GetConsensusPrice(),
+			WindowStartMs:  c.GetWindowStartMs(),
+			IsFinal:        c.GetIsFinal(),
+			LastTradeTs:    c.GetLastTradeTs(),
+			UpdatedTs:      e.Updated.UnixMilli(),
+			Stale:          c.GetStale(),
+		})
+	}
+	return resp, nil
+}
+
+func (s *Server) GetSnapshot(ctx context.Context, _ *pricev1.SnapshotRequest) (*pricev1.SnapshotResponse, error) {
+	resp := &pricev1.SnapshotResponse{Ts: time.Now().UnixMilli()}
+	entries := s.hub.Cache().All()
+	for _, sym := range sortedKeys(entries) {
+		if !allowed(ctx, sym) {
+			continue
+		}
+		e := entries[sym]
+		resp.Candles = append(resp.Candles, e.Latest)
+		if e.Final != nil {
+			resp.Final = append(resp.Final, e.Final)
+		}
+	}
+	return resp, nil
+}
+
+func (s *Server) ListSymbols(ctx context.Context, _ *pricev1.ListSymbolsRequest) (*pricev1.ListSymbolsResponse, error) {
+	exchanges := s.symbolExchanges()
+	resp := &pricev1.ListSymbolsResponse{}
+	entries := s.hub.Cache().All()
+	for _, sym := range sortedKeys(entries) {
+		if !allowed(ctx, sym) {
+			continue
+		}
+		e := entries[sym]
+		c := e.Latest
+		resp.Symbols = append(resp.Symbols, &pricev1.SymbolInfo{
+			Symbol:         sym,
+			InstrumentType: c.GetInstrumentType(),
+			PriceType:      c.GetPriceType(),
+			BaseCcy:        c.GetBaseCcy(),
+			QuoteCcy:       c.GetQuoteCcy(),
+			Exchanges:      exchanges[sym],
+			Synthetic:      c.GetSynthetic(),
+			FirstSeenTs:    e.FirstSeen.UnixMilli(),
+			LastUpdateTs:   e.Updated.UnixMilli(),
+			Updates:        e.Updates,
+			Stale:          c.GetStale(),
+		})
+	}
+	return resp, nil
+}
+
+func (s *Server) GetCandles(ctx context.Context, req *pricev1.CandlesRequest) (*pricev1.CandlesResponse, error) {
+	sym := strings.ToUpper(strings.TrimSpace(req.GetSymbol()))
+	if sym == "" {
+		return nil, status.Error(codes.InvalidArgument, "symbol required")
+	}
+	if req.GetLimit() < 0 || req.GetFromMs() < 0 || req.GetToMs() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "from_ms, to_ms and limit must be >= 0")
+	}
+	if err := s.authorize(ctx, pricev1.PriceStream_GetCandles_FullMethodName, sym, 0); err != nil {
+		return nil, err
+	}
+	if _, ok := s.hub.Cache().Get(sym); !ok {
+		return nil, status.Errorf(codes.NotFound, "no data for symbol %s", sym)
+	}
+	candles := s.hub.Cache().History(sym, req.GetFromMs(), req.GetToMs(), int(req.GetLimit()))
+	return &pricev1.CandlesResponse{Candles: candles}, nil
+}
+
+// exchangesTTL is how long symbolExchanges reuses its result; venues
+// show up in the cache as their trades arrive.
+const exchangesTTL = 5 * time.Second
+
+// symbolExchanges returns the trade venues feeding each symbol, sorted:
+// the trade connectors (from the health monitor, when wired) and the
+// venues seen in candles. Depth and quote feeds are not venues of the
+// price. The result is shared and must not be modified.
+func (s *Server) symbolExchanges() map[string][]string {
+	s.exchMu.Lock()
+	defer s.exchMu.Unlock()
+	if s.exch != nil && time.Since(s.exchAt) < exchangesTTL {
+		return s.exch
+	}
+	feeds := make(map[string]map[string]struct{})
+	add := func(sym, ex string) {
+		if feeds[sym] == nil {
+			feeds[sym] = make(map[string]struct{})
+		}
+		feeds[sym][ex] = struct{}{}
+	}
+	if s.monitor != nil {
+		for _, st := range s.monitor.Snapshot() {
+			if st.Kind == ingest.FeedTrades {
+				add(strings.ToUpper(st.Symbol), st.Exchange)
+			}
+		}
+	}
+	for sym, e := range s.hub.Cache().All() {
+		for v := range e.Venues {
+			add(sym, v)
+		}
+	}
+	out := make(map[string][]string, len(feeds))
+	for sym, ex := range feeds {
+		out[sym] = sortedKeys(ex)
+	}
+	s.exch, s.exchAt = out, time.Now()
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,207 @@
+// path: pkg/grpcapi/limits.go
+package grpcapi
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Connection limit defaults. They apply to native gRPC connections through
+// grpc.KeepaliveParams and to the other protocols through net/http.
+const (
+	DefaultKeepaliveTime    = 2 * time.Minute
+	DefaultKeepaliveTimeout = 20 * time.Second
+	DefaultSendTimeout      = 30 * time.Second
+	DefaultIdleTimeout      = 5 * time.Minute
+	DefaultKeepaliveMinTime = 20 * time.Second
+)
+
+// WithKeepalive pings an HTTP/2 connection after interval without any
+// frame from the client and closes it when the ping is not answered within
+// timeout. A zero interval disables pings.
+func WithKeepalive(interval, timeout time.Duration) ServeOption {
+	return func(c *serveConfig) { c.keepaliveTime, c.keepaliveTimeout = interval, timeout }
+}
+
+// WithKeepalivePolicy limits client pings on native gRPC connections: a
+// client that pings more often than every minTime, or while it has no
+// open stream unless permitWithoutStream, gets a GOAWAY (too_many_pings)
+// and is disconnected; a zero minTime means gRPC's default of 5 minutes.
+// The other protocols have no such policy; there the HTTP/2 server's
+// control-frame limit bounds ping floods.
+func WithKeepalivePolicy(minTime time.Duration, permitWithoutStream bool) ServeOption {
+	return func(c *serveConfig) { c.keepaliveMinTime, c.keepaliveWithoutStream = minTime, permitWithoutStream }
+}
+
+// WithMaxConnectionAge asks clients to reconnect once a connection is
+// about age old (minus up to 10% jitter): the next response on it carries
+// a GOAWAY (Connection: close on HTTP/1.1). A non-zero grace closes the
+// connection that long after age, ending streams still open on it. A zero
+// age disables the limit.
+func WithMaxConnectionAge(age, grace time.Duration) ServeOption {
+	return func(c *serveConfig) { c.maxConnAge, c.maxConnAgeGrace = age, grace }
+}
+
+// WithSendTimeout fails a stream whose client does not accept a write
+// within d, so a stalled reader cannot hold a handler forever. Zero
+// disables it.
+func WithSendTimeout(d time.Duration) ServeOption {
+	return func(c *serveConfig) { c.sendTimeout = d }
+}
+
+// connAge retires connections after a maximum age.
+type connAge struct {
+	age, grace time.Duration
+
+	mu   sync.Mutex
+	kill map[net.Conn]*time.Timer
+}
+
+type retireKey struct{}
+
+func newConnAge(age, grace time.Duration) *connAge {
+	return &connAge{age: age, grace: grace, kill: make(map[net.Conn]*time.Timer)}
+}
+
+// connContext is the http.Server ConnContext hook.
+func (a *connAge) connContext(ctx context.Context, c net.Conn) context.Context {
+	age := a.age - rand.N(a.age/10+1)
+	if a.grace > 0 {
+		a.mu.Lock()
+		a.kill[c] = time.AfterFunc(age+a.grace, func() {
+			c.Close()
+			a.forget(c)
+		})
+		a.mu.Unlock()
+	}
+	return context.WithValue(ctx, retireKey{}, time.Now().Add(age))
+}
+
+// connState is the http.Server ConnState hook. Hijacked connections
+// (WebSockets) keep their timer.
+func (a *connAge) connState(c net.Conn, st http.ConnState) {
+	if st == http.StateClosed {
+		a.forget(c)
+	}
+}
+
+func (a *connAge) forget(c net.Conn) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if t := a.kill[c]; t != nil {
+		t.Stop()
+		delete(a.kill, c)
+	}
+}
+
+// handler marks responses on connections past their age; net/http turns
+// Connection: close into a GOAWAY on HTTP/2.
+func (a *connAge) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if t, ok := r.Context().Value(retireKey{}).(time.Time); ok && time.Now().After(t) {
+			w.Header().Set("Connection", "close")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// sendTimeout bounds every write and flush of a response with a write
+// deadline. WebSocket upgrades are left alone: they need the Hijacker.
+func sendTimeout(d time.Duration, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(&deadlineWriter{ResponseWriter: w, rc: http.NewResponseController(w), timeout: d}, r)
+	})
+}
+
+type deadlineWriter struct {
+	http.ResponseWriter
+	rc      *http.ResponseController
+	timeout time.Duration
+}
+
+func (w *deadlineWriter) Write(p []byte) (int, error) {
+	w.rc.SetWriteDeadline(time.Now().Add(w.timeout))
+	defer w.rc.SetWriteDeadline(time.Time{})
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *deadlineWriter) Flush() {
+	w.rc.SetWriteDeadline(time.Now().Add(w.timeout))
+	defer w.rc.SetWriteDeadline(time.Time{})
+	w.rc.Flush()
+}
+
+func (w *deadlineWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// sendTimeoutStream is sendTimeout for native gRPC, where a send blocks on
+// HTTP/2 flow control once the client stops reading. The handler runs on
+// its own goroutine; when a send stalls for d, the call returns
+// DEADLINE_EXCEEDED, which resets the stream and so unblocks the send.
+func sendTimeoutStream(d time.Duration) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ts := &timedStream{ServerStream: ss, timeout: d, stalled: make(chan struct{})}
+		done := make(chan error, 1)
+		go func() { done <- handler(srv, ts) }()
+		select {
+		case err := <-done:
+			return err
+		case <-ts.stalled:
+			return status.Error(codes.DeadlineExceeded, "client is not reading the stream")
+		}
+	}
+}
+
+type timedStream struct {
+	grpc.ServerStream
+	timeout time.Duration
+	once    sync.Once
+	stalled chan struct{}
+}
+
+func (s *timedStream) SendMsg(m any) error {
+	t := time.AfterFunc(s.timeout, func() { s.once.Do(func() { close(s.stalled) }) })
+	defer t.Stop()
+	return s.ServerStream.SendMsg(m)
+}
+
+// gRPC runs handlers on their own goroutines, where a panic would take the
+// whole engine down; the recovery interceptors turn it into INTERNAL.
+var errPanic = status.Error(codes.Internal, "internal error")
+
+func logPanic(method string, v any) {
+	log.Printf("grpc: panic in %s: %v\n%s", method, v, debug.Stack())
+}
+
+func recoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			logPanic(info.FullMethod, v)
+			resp, err = nil, errPanic
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func recoverStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			logPanic(info.FullMethod, v)
+			err = errPanic
+		}
+	}()
+	return handler(srv, ss)
+}
@@ -0,0 +1,182 @@
+// path: pkg/grpcapi/metering.go
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/binaridigital/price-engine/pkg/auth"
+	"github.com/binaridigital/price-engine/pkg/metering"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// anonymous is the client usage is booked to when auth is disabled.
+const anonymous = "anonymous"
+
+// WithMeter meters every call per client and symbol, enforces the clients'
+// quotas and enables GetUsageReport.
+func WithMeter(m *metering.Meter) ServerOption {
+	return func(s *Server) { s.meter = m }
+}
+
+var errQuota = status.Error(codes.ResourceExhausted, "daily quota exceeded")
+
+func meterClient(ctx context.Context) (string, *metering.Quota) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return anonymous, nil
+	}
+	if p.Quota == nil {
+		return p.ID, nil
+	}
+	return p.ID, &metering.Quota{
+		MessagesPerDay:      p.Quota.MessagesPerDay,
+		BytesPerDay:         p.Quota.BytesPerDay,
+		StreamSecondsPerDay: p.Quota.StreamSecondsPerDay,
+		OnExceed:            metering.Action(p.Quota.OnExceed),
+	}
+}
+
+// quotaDenied reports whether new calls are refused because the caller
+// exceeded a terminating quota.
+func quotaDenied(ctx context.Context, m *metering.Meter, method string) bool {
+	client, q := meterClient(ctx)
+	if q == nil || q.OnExceed != metering.Terminate || !m.Exceeded(client, q) {
+		return false
+	}
+	if p, ok := auth.FromContext(ctx); ok {
+		p.Deny(method, "", "daily quota exceeded")
+	}
+	return true
+}
+
+func requestSymbol(req any) string {
+	if r, ok := req.(interface{ GetSymbol() string }); ok {
+		return strings.ToUpper(r.GetSymbol())
+	}
+	return ""
+}
+
+func meterUnary(m *metering.Meter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isHealthMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		if quotaDenied(ctx, m, info.FullMethod) {
+			return nil, errQuota
+		}
+		resp, err := handler(ctx, req)
+		if err == nil {
+			client, _ := meterClient(ctx)
+			pm, _ := resp.(proto.Message)
+			m.Record(client, requestSymbol(req), proto.Size(pm))
+		}
+		return resp, err
+	}
+}
+
+func meterStream(m *metering.Meter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isHealthMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		if quotaDenied(ss.Context(), m, info.FullMethod) {
+			return errQuota
+		}
+		client, q := meterClient(ss.Context())
+		ms := &meteredStream{ServerStream: ss, m: m, client: client, quota: q, method: info.FullMethod}
+		defer ms.close()
+		return handler(srv, ms)
+	}
+}
+
+// meteredStream books the subscription once the request (and its symbol)
+// is known, and passes every message through the quota.
+type meteredStream struct {
+	grpc.ServerStream
+	m      *metering.Meter
+	client string
+	quota  *metering.Quota
+	method string
+	st     *metering.Stream
+}
+
+func (s *meteredStream) open(symbol string) {
+	if s.st == nil {
+		s.st = s.m.Open(s.client, symbol, s.quota)
+	}
+}
+
+func (s *meteredStream) RecvMsg(msg any) error {
+	err := s.ServerStream.RecvMsg(msg)
+	if err == nil {
+		s.open(requestSymbol(msg))
+	}
+	return err
+}
+
+func (s *meteredStream) SendMsg(msg any) error {
+	s.open("")
+	var partial bool
+	switch m := msg.(type) {
+	case *pricev1.Candle:
+		partial = !m.GetIsFinal()
+	case *pricev1.AggregateEvent:
+		// heartbeats and status events are neither counted nor throttled
+		if m.GetCandle() == nil {
+			return s.ServerStream.SendMsg(msg)
+		}
+		partial = !m.GetCandle().GetIsFinal()
+	}
+	pm, _ := msg.(proto.Message)
+	switch s.st.Send(proto.Size(pm), partial) {
+	case metering.Drop:
+		return nil
+	case metering.Stop:
+		if p, ok := auth.FromContext(s.Context()); ok {
+			p.Deny(s.method, "", "daily quota exceeded")
+		}
+		return errQuota
+	}
+	return s.ServerStream.SendMsg(msg)
+}
+
+func (s *meteredStream) close() {
+	if s.st != nil {
+		s.st.Close()
+	}
+}
+
+func (s *Server) GetUsageReport(ctx context.Context, req *pricev1.UsageReportRequest) (*pricev1.UsageReportResponse, error) {
+	if s.meter == nil {
+		return nil, errors.New("usage metering not enabled on this engine instance")
+	}
+	f := metering.Filter{
+		Client:  req.GetClient(),
+		Symbol:  strings.ToUpper(req.GetSymbol()),
+		FromDay: req.GetFromDay(),
+		ToDay:   req.GetToDay(),
+	}
+	if p, ok := auth.FromContext(ctx); ok && !p.Admin {
+		f.Client = p.ID
+	}
+	resp := &pricev1.UsageReportResponse{}
+	for _, r := range s.meter.Report(f) {
+		resp.Rows = append(resp.Rows, &pricev1.UsageRow{
+			Day:           r.Day,
+			Client:        r.Client,
+			Symbol:        r.Symbol,
+			Subscriptions: r.Subscriptions,
+			Messages:      r.Messages,
+			Bytes:         r.Bytes,
+			StreamSeconds: r.StreamSeconds,
+		})
+	}
+	return resp, nil
+}
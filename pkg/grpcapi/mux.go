@@ -0,0 +1,240 @@
+// path: pkg/grpcapi/mux.go
+package grpcapi
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/soheilhy/cmux"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+	"google.golang.org/grpc/credentials"
+)
+
+// isGRPCContentType reports whether ct is native gRPC; gRPC-Web
+// (application/grpc-web...) is not.
+func isGRPCContentType(ct string) bool {
+	return ct == "application/grpc" || strings.HasPrefix(ct, "application/grpc+") || strings.HasPrefix(ct, "application/grpc;")
+}
+
+// matchGRPC is a cmux matcher for HTTP/2 connections whose first request
+// is native gRPC. Like cmux's SendSettings matchers it answers the client's
+// SETTINGS while sniffing, since some gRPC clients send no request before
+// they got the server's; connections handed to net/http must then go
+// through dropSettingsAck.
+func matchGRPC(w io.Writer, r io.Reader) bool {
+	if !hasPreface(r) {
+		return false
+	}
+	framer := http2.NewFramer(w, r)
+	var ct string
+	done := false
+	hdec := hpack.NewDecoder(4<<10, func(hf hpack.HeaderField) {
+		if hf.Name == "content-type" {
+			ct = hf.Value
+		}
+	})
+	for !done {
+		f, err := framer.ReadFrame()
+		if err != nil {
+			return false
+		}
+		switch f := f.(type) {
+		case *http2.SettingsFrame:
+			if !f.IsAck() {
+				if err := framer.WriteSettings(); err != nil {
+					return false
+				}
+			}
+		case *http2.HeadersFrame:
+			if _, err := hdec.Write(f.HeaderBlockFragment()); err != nil {
+				return false
+			}
+			done = f.HeadersEnded()
+		case *http2.ContinuationFrame:
+			if _, err := hdec.Write(f.HeaderBlockFragment()); err != nil {
+				return false
+			}
+			done = f.HeadersEnded()
+		}
+	}
+	ct, _, _ = strings.Cut(strings.ToLower(ct), ";")
+	return isGRPCContentType(strings.TrimSpace(ct))
+}
+
+// hasPreface reads the HTTP/2 client preface, giving up at the first byte
+// that differs so that a short HTTP/1.1 request is not left waiting for
+// more.
+func hasPreface(r io.Reader) bool {
+	b := make([]byte, len(http2.ClientPreface))
+	for read := 0; read < len(b); {
+		n, err := r.Read(b[read:])
+		if string(b[read:read+n]) != http2.ClientPreface[read:read+n] {
+			return false
+		}
+		read += n
+		if err != nil && read < len(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// dropSettingsAck wraps the connections of a listener fed by matchGRPC.
+// On HTTP/2 connections the client acknowledges the SETTINGS matchGRPC
+// sent, which net/http, having never sent them, treats as a protocol
+// error; the first SETTINGS ACK is removed from the stream.
+type dropSettingsAck struct {
+	net.Listener
+}
+
+func (l dropSettingsAck) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &ackFilterConn{Conn: c, r: bufio.NewReader(c)}, nil
+}
+
+type ackFilterConn struct {
+	net.Conn
+	r       *bufio.Reader
+	started bool
+	done    bool   // passing bytes through
+	pending []byte // bytes of the current frame not yet returned
+}
+
+func (c *ackFilterConn) NetConn() net.Conn { return c.Conn }
+
+func (c *ackFilterConn) Read(p []byte) (int, error) {
+	for len(c.pending) == 0 && !c.done {
+		if err := c.advance(); err != nil {
+			// reported by the passthrough read below, if it persists
+			c.done = true
+		}
+	}
+	if len(c.pending) > 0 {
+		n := copy(p, c.pending)
+		c.pending = c.pending[n:]
+		return n, nil
+	}
+	return c.r.Read(p)
+}
+
+// advance queues the preface or the next frame, dropping the first
+// SETTINGS ACK.
+func (c *ackFilterConn) advance() error {
+	if !c.started {
+		c.started = true
+		// peeked a byte at a time, as HTTP/1.1 requests may be shorter
+		for n := 1; n <= len(http2.ClientPreface); n++ {
+			b, err := c.r.Peek(n)
+			if err != nil || string(b) != http2.ClientPreface[:n] {
+				c.done = true // HTTP/1.1: nothing was sent to acknowledge
+				return nil
+			}
+		}
+		c.pending, _ = c.take(len(http2.ClientPreface))
+		return nil
+	}
+	hdr, err := c.r.Peek(9)
+	if err != nil {
+		return err
+	}
+	n := 9 + int(binary.BigEndian.Uint32(append([]byte{0}, hdr[:3]...)))
+	typ, flags := http2.FrameType(hdr[3]), http2.Flags(hdr[4])
+	frame, err := c.take(n)
+	if err != nil {
+		c.pending = frame
+		return err
+	}
+	if typ == http2.FrameSettings && flags.Has(http2.FlagSettingsAck) {
+		c.done = true
+		return nil
+	}
+	c.pending = frame
+	return nil
+}
+
+func (c *ackFilterConn) take(n int) ([]byte, error) {
+	b := make([]byte, n)
+	m, err := io.ReadFull(c.r, b)
+	return b[:m], err
+}
+
+// tlsState returns the TLS state of a connection accepted through the TLS
+// listener and cmux, once its handshake completed.
+func tlsState(c net.Conn) (tls.ConnectionState, bool) {
+	for {
+		switch t := c.(type) {
+		case *tls.Conn:
+			if err := t.Handshake(); err != nil {
+				return tls.ConnectionState{}, false
+			}
+			return t.ConnectionState(), true
+		case *cmux.MuxConn:
+			c = t.Conn
+		case interface{ NetConn() net.Conn }:
+			c = t.NetConn()
+		default:
+			return tls.ConnectionState{}, false
+		}
+	}
+}
+
+// tlsInfo lets grpc.Server, served on connections TLS was already
+// terminated on, report the client certificate to the auth interceptors.
+type tlsInfo struct{}
+
+func (tlsInfo) ServerHandshake(c net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	st, ok := tlsState(c)
+	if !ok {
+		return nil, nil, errors.New("grpcapi: connection is not TLS")
+	}
+	return c, credentials.TLSInfo{State: st, CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.PrivacyAndIntegrity}}, nil
+}
+
+func (tlsInfo) ClientHandshake(context.Context, string, net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, errors.New("grpcapi: server-side credentials")
+}
+
+func (tlsInfo) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: "tls"}
+}
+
+func (t tlsInfo) Clone() credentials.TransportCredentials { return t }
+
+func (tlsInfo) OverrideServerName(string) error { return nil }
+
+type tlsStateKey struct{}
+
+// withTLSState sets r.TLS from the state ConnContext stored, as net/http
+// only does when it terminates TLS itself.
+func withTLSState(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if st, ok := r.Context().Value(tlsStateKey{}).(*tls.ConnectionState); ok && r.TLS == nil {
+			r.TLS = st
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func tlsConnContext(ctx context.Context, c net.Conn) context.Context {
+	if st, ok := tlsState(c); ok {
+		return context.WithValue(ctx, tlsStateKey{}, &st)
+	}
+	return ctx
+}
+
+// isClosed reports whether err is a listener closed during shutdown.
+func isClosed(err error) bool {
+	return err == nil || errors.Is(err, net.ErrClosed) || errors.Is(err, http.ErrServerClosed) ||
+		errors.Is(err, cmux.ErrListenerClosed) || errors.Is(err, cmux.ErrServerClosed)
+}
@@ -0,0 +1,75 @@
+// path: pkg/grpcapi/orderbook.go
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/binaridigital/price-engine/pkg/orderbook"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// DefaultBookDepth is the per-side depth sent when a request leaves it unset.
+const DefaultBookDepth = 20
+
+// WithOrderBooks enables StreamOrderBook from books published on t.
+func WithOrderBooks(t *Topic[orderbook.Snapshot]) ServerOption {
+	return func(s *Server) { s.books = t }
+}
+
+func (s *Server) StreamOrderBook(req *pricev1.OrderBookRequest, stream pricev1.PriceStream_StreamOrderBookServer) error {
+	if req.GetSymbol() == "" {
+		return errors.New("symbol required")
+	}
+	if s.books == nil {
+		return errors.New("order books not available on this engine instance")
+	}
+	if err := s.authorize(stream.Context(), pricev1.PriceStream_StreamOrderBook_FullMethodName, req.GetSymbol(), 0); err != nil {
+		return err
+	}
+	depth := int(req.GetDepth())
+	if depth <= 0 {
+		depth = DefaultBookDepth
+	}
+	ch, unsub := s.books.Subscribe(req.GetSymbol())
+	defer unsub()
+	if snap, ok := s.books.Last(req.GetSymbol()); ok {
+		if err := stream.Send(orderBookProto(snap, depth)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return context.Canceled
+		case snap := <-ch:
+			if err := stream.Send(orderBookProto(snap, depth)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func orderBookProto(snap orderbook.Snapshot, depth int) *pricev1.OrderBook {
+	snap = snap.Truncate(depth)
+	bb, ba := snap.BestBid(), snap.BestAsk()
+	return &pricev1.OrderBook{
+		Symbol:       snap.Symbol,
+		Exchange:     snap.Exchange,
+		LastUpdateId: snap.LastUpdateID,
+		Ts:           snap.TS.UnixMilli(),
+		BestBid:      &pricev1.PriceLevel{Price: bb.Price, Qty: bb.Qty},
+		BestAsk:      &pricev1.PriceLevel{Price: ba.Price, Qty: ba.Qty},
+		Spread:       snap.Spread(),
+		Bids:         priceLevels(snap.Bids),
+		Asks:         priceLevels(snap.Asks),
+	}
+}
+
+func priceLevels(ls []orderbook.Level) []*pricev1.PriceLevel {
+	out := make([]*pricev1.PriceLevel, len(ls))
+	for i, l := range ls {
+		out[i] = &pricev1.PriceLevel{Price: l.Price, Qty: l.Qty}
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+// path: pkg/grpcapi/profile.go
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// WithProfiles enables StreamVolumeProfile for profiles built every interval.
+func WithProfiles(t *Topic[*pricev1.VolumeProfile], interval time.Duration) ServerOption {
+	return func(s *Server) {
+		s.profiles = t
+		s.profileMs = interval.Milliseconds()
+	}
+}
+
+func (s *Server) StreamVolumeProfile(req *pricev1.SubscribeRequest, stream pricev1.PriceStream_StreamVolumeProfileServer) error {
+	if req.GetSymbol() == "" {
+		return errors.New("symbol required")
+	}
+	if s.profiles == nil {
+		return errors.New("volume profiles not available on this engine instance")
+	}
+	if req.GetIntervalMs() != 0 && req.GetIntervalMs() != s.profileMs {
+		return errors.New("requested interval not supported by current engine instance")
+	}
+	if err := s.authorize(stream.Context(), pricev1.PriceStream_StreamVolumeProfile_FullMethodName, req.GetSymbol(), 0); err != nil {
+		return err
+	}
+	ch, unsub := s.profiles.Subscribe(req.GetSymbol())
+	defer unsub()
+	if p, ok := s.profiles.Last(req.GetSymbol()); ok {
+		if err := stream.Send(p); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return context.Canceled
+		case p := <-ch:
+			if err := stream.Send(p); err != nil {
+				return err
+			}
+		}
+	}
+}
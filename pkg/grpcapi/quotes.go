@@ -0,0 +1,58 @@
+// path: pkg/grpcapi/quotes.go
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/binaridigital/price-engine/pkg/common"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// WithQuotes enables StreamQuotes from top-of-book quotes published on t.
+func WithQuotes(t *Topic[common.Quote]) ServerOption {
+	return func(s *Server) { s.quotes = t }
+}
+
+func (s *Server) StreamQuotes(req *pricev1.QuoteRequest, stream pricev1.PriceStream_StreamQuotesServer) error {
+	if req.GetSymbol() == "" {
+		return errors.New("symbol required")
+	}
+	if s.quotes == nil {
+		return errors.New("quotes not available on this engine instance")
+	}
+	if err := s.authorize(stream.Context(), pricev1.PriceStream_StreamQuotes_FullMethodName, req.GetSymbol(), 0); err != nil {
+		return err
+	}
+	ch, unsub := s.quotes.Subscribe(req.GetSymbol())
+	defer unsub()
+	if q, ok := s.quotes.Last(req.GetSymbol()); ok {
+		if err := stream.Send(quoteProto(q)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return context.Canceled
+		case q := <-ch:
+			if err := stream.Send(quoteProto(q)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func quoteProto(q common.Quote) *pricev1.Quote {
+	return &pricev1.Quote{
+		Symbol:   q.Symbol,
+		Exchange: q.Exchange,
+		Bid:      q.Bid,
+		BidQty:   q.BidQty,
+		Ask:      q.Ask,
+		AskQty:   q.AskQty,
+		Spread:   q.Spread(),
+		Mid:      q.Mid(),
+		Ts:       q.TS.UnixMilli(),
+	}
+}
@@ -0,0 +1,299 @@
+// path: pkg/grpcapi/serve.go
+package grpcapi
+
+import (
+	"context"
+	"crypto/tls"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/soheilhy/cmux"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/binaridigital/price-engine/pkg/auth"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// ServeOption configures the listener started by Serve.
+type ServeOption func(*serveConfig)
+
+type serveConfig struct {
+	corsOrigins []string
+	auth        *auth.Authenticator
+	tls         *tls.Config
+
+	keepaliveTime, keepaliveTimeout time.Duration
+	keepaliveMinTime                time.Duration
+	keepaliveWithoutStream          bool
+	maxConnAge, maxConnAgeGrace     time.Duration
+	sendTimeout                     time.Duration
+}
+
+// WithCORS allows browser calls from the given origins; "*" allows any.
+// Without it, cross-origin requests get no CORS headers.
+func WithCORS(origins []string) ServeOption {
+	return func(c *serveConfig) { c.corsOrigins = origins }
+}
+
+// WithAuth requires every call to authenticate with an API key, a JWT or
+// a client certificate, and enforces the client's entitlements.
+func WithAuth(a *auth.Authenticator) ServeOption {
+	return func(c *serveConfig) { c.auth = a }
+}
+
+// WithTLS serves over TLS (HTTP/1.1 and HTTP/2 via ALPN) instead of
+// cleartext.
+func WithTLS(cfg *tls.Config) ServeOption {
+	return func(c *serveConfig) { c.tls = cfg }
+}
+
+// Serve listens on addr and serves gRPC, gRPC-Web, Connect and the JSON
+// gateway on the same port: HTTP/2 connections whose first request is
+// native gRPC go to a grpc.Server, everything else to net/http over
+// HTTP/1.1 or HTTP/2 (prior knowledge when cleartext), so browsers need
+// no proxy. The gRPC health service, /healthz and /readyz are served
+// without authentication.
+func Serve(addr string, s *Server, opts ...ServeOption) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return ServeListener(context.Background(), lis, s, opts...)
+}
+
+// shutdownGrace is how long ServeListener lets HTTP requests finish once
+// its context ends; gateway streams never go idle, so they are then closed.
+const shutdownGrace = 5 * time.Second
+
+// sniffTimeout bounds how long a new connection may take to show which
+// protocol it speaks.
+const sniffTimeout = 10 * time.Second
+
+// ServeListener is Serve on an existing listener. When ctx ends, gRPC
+// calls are cancelled (clients reconnect elsewhere), the other requests
+// get shutdownGrace to finish, and it returns nil.
+func ServeListener(ctx context.Context, lis net.Listener, s *Server, opts ...ServeOption) error {
+	cfg := serveConfig{
+		keepaliveTime:          DefaultKeepaliveTime,
+		keepaliveTimeout:       DefaultKeepaliveTimeout,
+		keepaliveMinTime:       DefaultKeepaliveMinTime,
+		keepaliveWithoutStream: true,
+		sendTimeout:            DefaultSendTimeout,
+	}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	// the send timeout runs the rest of the chain on its own goroutine, so
+	// it goes first; then panic recovery; authentication before metering,
+	// which books usage to the authenticated client
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	if cfg.sendTimeout > 0 {
+		stream = append(stream, sendTimeoutStream(cfg.sendTimeout))
+	}
+	unary = append(unary, recoverUnary)
+	stream = append(stream, recoverStream)
+	if cfg.auth != nil {
+		unary = append(unary, unaryAuth(cfg.auth))
+		stream = append(stream, streamAuth(cfg.auth))
+	}
+	if s.meter != nil {
+		unary = append(unary, meterUnary(s.meter))
+		stream = append(stream, meterStream(s.meter))
+	}
+	grpcOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+		grpc.KeepaliveParams(cfg.keepaliveParams()),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.keepaliveMinTime,
+			PermitWithoutStream: cfg.keepaliveWithoutStream,
+		}),
+	}
+	if cfg.tls != nil {
+		grpcOpts = append(grpcOpts, grpc.Creds(tlsInfo{}))
+	}
+	grpcServer := grpc.NewServer(grpcOpts...)
+	pricev1.RegisterPriceStreamServer(grpcServer, s)
+	reflection.Register(grpcServer)
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, hs)
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.watchHealth(hs, stop)
+
+	// gRPC-Web and Connect are translated to gRPC and served by the same
+	// grpc.Server through its http.Handler
+	web := newWebHandler(grpcServer)
+	var gw http.Handler = newGateway(s, cfg.corsOrigins)
+	if cfg.auth != nil {
+		gw = httpAuth(cfg.auth, gw)
+	}
+	var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			healthz(w, r)
+			return
+		case "/readyz":
+			s.readyz(w, r)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/v1/") {
+			gw.ServeHTTP(w, r)
+			return
+		}
+		if isWebRequest(r) {
+			web.ServeHTTP(w, r)
+			return
+		}
+		ct, _, _ := strings.Cut(strings.ToLower(r.Header.Get("Content-Type")), ";")
+		if isGRPCContentType(strings.TrimSpace(ct)) {
+			// a gRPC call on a connection that started with another protocol
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	})
+	if cfg.sendTimeout > 0 {
+		h = sendTimeout(cfg.sendTimeout, h)
+	}
+	if len(cfg.corsOrigins) > 0 {
+		h = cors(cfg.corsOrigins, h)
+	}
+
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true) // TLS, if any, is terminated below
+	srv := &http.Server{
+		Protocols:         &protocols,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       DefaultIdleTimeout,
+		HTTP2: &http.HTTP2Config{
+			SendPingTimeout: cfg.keepaliveTime,
+			PingTimeout:     cfg.keepaliveTimeout,
+		},
+	}
+	var connContext []func(context.Context, net.Conn) context.Context
+	if cfg.maxConnAge > 0 {
+		age := newConnAge(cfg.maxConnAge, cfg.maxConnAgeGrace)
+		h = age.handler(h)
+		connContext = append(connContext, age.connContext)
+		srv.ConnState = age.connState
+	}
+	if cfg.tls != nil {
+		tc := cfg.tls.Clone()
+		if len(tc.NextProtos) == 0 {
+			tc.NextProtos = []string{"h2", "http/1.1"}
+		}
+		lis = tls.NewListener(lis, tc)
+		h = withTLSState(h)
+		connContext = append(connContext, tlsConnContext)
+	}
+	if len(connContext) > 0 {
+		srv.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+			for _, fn := range connContext {
+				ctx = fn(ctx, c)
+			}
+			return ctx
+		}
+	}
+	srv.Handler = h
+
+	m := cmux.New(lis)
+	m.SetReadTimeout(sniffTimeout)
+	grpcL := m.MatchWithWriters(matchGRPC)
+	httpL := dropSettingsAck{m.Match(cmux.Any())}
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		select {
+		case <-ctx.Done():
+		case <-stop:
+			return
+		}
+		grpcServer.Stop()
+		sctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+		defer cancel()
+		if srv.Shutdown(sctx) != nil {
+			srv.Close()
+		}
+		lis.Close()
+	}()
+	errc := make(chan error, 3)
+	go func() { errc <- grpcServer.Serve(grpcL) }()
+	go func() { errc <- srv.Serve(httpL) }()
+	go func() { errc <- m.Serve() }()
+	err := <-errc
+	if ctx.Err() != nil && isClosed(err) {
+		<-closed
+		return nil
+	}
+	// one of them failed: stop the others
+	grpcServer.Stop()
+	srv.Close()
+	lis.Close()
+	return err
+}
+
+// keepaliveParams are the connection limits for grpc.Server.
+func (c *serveConfig) keepaliveParams() keepalive.ServerParameters {
+	p := keepalive.ServerParameters{
+		Time:                  c.keepaliveTime,
+		Timeout:               c.keepaliveTimeout,
+		MaxConnectionAge:      c.maxConnAge,
+		MaxConnectionAgeGrace: c.maxConnAgeGrace,
+	}
+	if p.Time == 0 {
+		p.Time = time.Duration(math.MaxInt64) // grpc's "never"; zero means its default
+	}
+	return p
+}
+
+// Request and response headers browsers may use across origins.
+var (
+	corsAllowHeaders = []string{
+		"Content-Type", "Authorization", "X-Api-Key", "X-User-Agent", "X-Grpc-Web",
+		"Grpc-Timeout", "Connect-Protocol-Version", "Connect-Timeout-Ms",
+	}
+	corsExposeHeaders = []string{"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin"}
+)
+
+// cors answers preflights and adds CORS headers for allowed origins.
+func cors(origins []string, next http.Handler) http.Handler {
+	anyOrigin := false
+	allowed := make(map[string]bool, len(origins))
+	for _, o := range origins {
+		if o == "*" {
+			anyOrigin = true
+		}
+		allowed[strings.TrimSuffix(o, "/")] = true
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !(anyOrigin || allowed[origin]) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		hdr := w.Header()
+		hdr.Add("Vary", "Origin")
+		hdr.Set("Access-Control-Allow-Origin", origin)
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			hdr.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			hdr.Set("Access-Control-Allow-Headers", strings.Join(corsAllowHeaders, ", "))
+			hdr.Set("Access-Control-Max-Age", strconv.Itoa(int((2 * time.Hour).Seconds())))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		hdr.Set("Access-Control-Expose-Headers", strings.Join(corsExposeHeaders, ", "))
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,285 @@
+package grpcapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// serve runs ServeListener on a loopback port until the test ends and
+// returns its address and the hub behind it. The hub holds one candle for
+// BTCUSDT.
+func serve(t *testing.T, opts ...ServeOption) (string, *Hub) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hub := NewHub()
+	hub.Publish(&pricev1.Candle{Symbol: "BTCUSDT", WindowStartMs: 0, WindowEndMs: 1000, Close: 100, IsFinal: true})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- ServeListener(ctx, lis, NewServer(hub, time.Second), opts...) }()
+	t.Cleanup(func() {
+		cancel()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("ServeListener: %v", err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Error("ServeListener did not return")
+		}
+	})
+	return lis.Addr().String(), hub
+}
+
+// countDials counts the connections tr opens.
+func countDials(tr *http.Transport) *atomic.Int32 {
+	var n atomic.Int32
+	var d net.Dialer
+	tr.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		n.Add(1)
+		return d.DialContext(ctx, network, addr)
+	}
+	return &n
+}
+
+// checkGRPC makes a unary call, a failing unary call and a server stream
+// over conn.
+func checkGRPC(t *testing.T, conn *grpc.ClientConn) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c := pricev1.NewPriceStreamClient(conn)
+
+	var hdr metadata.MD
+	resp, err := c.GetCandles(ctx, &pricev1.CandlesRequest{Symbol: "BTCUSDT"}, grpc.Header(&hdr))
+	if err != nil {
+		t.Fatalf("GetCandles: %v", err)
+	}
+	if len(resp.GetCandles()) != 1 || resp.GetCandles()[0].GetClose() != 100 {
+		t.Fatalf("GetCandles = %v", resp)
+	}
+	if ct := hdr.Get("content-type"); len(ct) == 0 || ct[0] != "application/grpc" {
+		t.Errorf("content-type %q, want application/grpc from grpc.Server", ct)
+	}
+
+	_, err = c.GetCandles(ctx, &pricev1.CandlesRequest{Symbol: "NOPE"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetCandles(NOPE) = %v, want NotFound", err)
+	}
+
+	// the stream opens with the market status
+	stream, err := c.StreamAggregateEvents(ctx, &pricev1.SubscribeRequest{Symbol: "BTCUSDT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("StreamAggregateEvents: %v", err)
+	}
+}
+
+// checkHTTP makes requests on one connection, which must outlive the
+// SETTINGS exchange of the protocol sniffing: clients retry on a new
+// connection when the server drops it.
+func checkHTTP(t *testing.T, tr *http.Transport, base string, wantProto int) {
+	t.Helper()
+	dials := countDials(tr)
+	client := &http.Client{Transport: tr, Timeout: 5 * time.Second}
+	for range 3 {
+		resp, err := client.Get(base + "/healthz")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.ProtoMajor != wantProto {
+			t.Fatalf("GET /healthz: %s over HTTP/%d, want 200 over HTTP/%d", resp.Status, resp.ProtoMajor, wantProto)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if n := dials.Load(); n != 1 {
+		t.Errorf("%d connections, want 1", n)
+	}
+}
+
+func TestServeCleartext(t *testing.T) {
+	addr, _ := serve(t)
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	checkGRPC(t, conn)
+
+	checkHTTP(t, &http.Transport{}, "http://"+addr, 1)
+	var h2c http.Protocols
+	h2c.SetUnencryptedHTTP2(true)
+	checkHTTP(t, &http.Transport{Protocols: &h2c}, "http://"+addr, 2)
+}
+
+func TestServeTLS(t *testing.T) {
+	cert := selfSigned(t)
+	addr, _ := serve(t, WithTLS(&tls.Config{Certificates: []tls.Certificate{cert}}))
+	clientTLS := &tls.Config{InsecureSkipVerify: true}
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(credentials.NewTLS(clientTLS)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	checkGRPC(t, conn)
+
+	h1 := &http.Transport{TLSClientConfig: clientTLS, TLSNextProto: map[string]func(string, *tls.Conn) http.RoundTripper{}}
+	checkHTTP(t, h1, "https://"+addr, 1)
+	checkHTTP(t, &http.Transport{TLSClientConfig: clientTLS, ForceAttemptHTTP2: true}, "https://"+addr, 2)
+}
+
+func selfSigned(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// stuckStream is a ServerStream whose client never reads.
+type stuckStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s stuckStream) Context() context.Context { return s.ctx }
+
+func (s stuckStream) SendMsg(any) error {
+	<-s.ctx.Done()
+	return s.ctx.Err()
+}
+
+func TestSendTimeoutStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	intercept := sendTimeoutStream(50 * time.Millisecond)
+	info := &grpc.StreamServerInfo{FullMethod: "/test/Stream"}
+
+	// a handler that returns ends the call with its own result
+	err := intercept(nil, stuckStream{ctx: ctx}, info, func(any, grpc.ServerStream) error { return nil })
+	if err != nil {
+		t.Fatalf("handler without sends: %v", err)
+	}
+
+	start := time.Now()
+	err = intercept(nil, stuckStream{ctx: ctx}, info, func(_ any, ss grpc.ServerStream) error {
+		return ss.SendMsg(nil)
+	})
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("stalled send: %v, want DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("stalled send took %v to fail", d)
+	}
+}
+
+func TestServeShortRequest(t *testing.T) {
+	addr, _ := serve(t)
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+	// shorter than the HTTP/2 preface the sniffing looks for
+	if _, err := io.WriteString(conn, "GET / HTTP/1.0\r\n\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatalf("no response: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /: %s, want 404", resp.Status)
+	}
+}
+
+func TestServeKeepalivePolicy(t *testing.T) {
+	addr, _ := serve(t, WithKeepalivePolicy(time.Hour, true))
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// open a gRPC stream, then ping far more often than allowed
+	if _, err := io.WriteString(conn, http2.ClientPreface); err != nil {
+		t.Fatal(err)
+	}
+	fr := http2.NewFramer(conn, conn)
+	fr.WriteSettings()
+	var hdr bytes.Buffer
+	enc := hpack.NewEncoder(&hdr)
+	for _, f := range []hpack.HeaderField{
+		{Name: ":method", Value: "POST"},
+		{Name: ":scheme", Value: "http"},
+		{Name: ":path", Value: pricev1.PriceStream_StreamAggregates_FullMethodName},
+		{Name: ":authority", Value: addr},
+		{Name: "content-type", Value: "application/grpc"},
+		{Name: "te", Value: "trailers"},
+	} {
+		enc.WriteField(f)
+	}
+	fr.WriteHeaders(http2.HeadersFrameParam{StreamID: 1, BlockFragment: hdr.Bytes(), EndHeaders: true})
+	for i := range 5 {
+		fr.WritePing(false, [8]byte{byte(i)})
+	}
+	for {
+		f, err := fr.ReadFrame()
+		if err != nil {
+			t.Fatalf("connection ended without GOAWAY: %v", err)
+		}
+		if ga, ok := f.(*http2.GoAwayFrame); ok {
+			if ga.ErrCode != http2.ErrCodeEnhanceYourCalm || string(ga.DebugData()) != "too_many_pings" {
+				t.Errorf("GOAWAY %v %q, want ENHANCE_YOUR_CALM too_many_pings", ga.ErrCode, ga.DebugData())
+			}
+			return
+		}
+	}
+}
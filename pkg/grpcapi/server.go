@@ -2,100 +2,220 @@
 package grpcapi
 
 import (
+	"cmp"
 	"context"
 	"errors"
-	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/binaridigital/price-engine/pkg/alert"
+	"github.com/binaridigital/price-engine/pkg/calendar"
+	"github.com/binaridigital/price-engine/pkg/common"
+	"github.com/binaridigital/price-engine/pkg/ingest"
+	"github.com/binaridigital/price-engine/pkg/metering"
+	"github.com/binaridigital/price-engine/pkg/orderbook"
 	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
 )
 
 type Hub struct {
-	mu   sync.RWMutex
-	subs map[string]map[chan *pricev1.Candle]struct{}
+	mu    sync.RWMutex
+	subs  map[string]map[*conflator]struct{}
+	cache *Cache
+	last  atomic.Int64 // unix nanoseconds of the latest Publish
 }
 
-func NewHub() *Hub {
-	return &Hub{subs: make(map[string]map[chan *pricev1.Candle]struct{})}
+// HubOption configures a Hub.
+type HubOption func(*hubConfig)
+
+type hubConfig struct {
+	history int
+}
+
+// WithHistory sets how many closed windows per symbol the Hub keeps for
+// GetCandles and the HTTP gateway (DefaultHistory by default, 0 = none).
+func WithHistory(n int) HubOption {
+	return func(c *hubConfig) { c.history = n }
+}
+
+func NewHub(opts ...HubOption) *Hub {
+	cfg := hubConfig{history: DefaultHistory}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	return &Hub{subs: make(map[string]map[*conflator]struct{}), cache: NewCache(cfg.history)}
+}
+
+// Cache returns the last-value cache updated by Publish.
+func (h *Hub) Cache() *Cache { return h.cache }
+
+// LastPublish returns when a candle was last published (zero if never).
+func (h *Hub) LastPublish() time.Time {
+	if n := h.last.Load(); n != 0 {
+		return time.Unix(0, n)
+	}
+	return time.Time{}
 }
 
 func (h *Hub) Publish(c *pricev1.Candle) {
+	h.last.Store(time.Now().UnixNano())
+	h.cache.put(c)
 	h.mu.RLock()
 	defer h.mu.RUnlock()
-	group := h.subs[c.Symbol]
-	for ch := range group {
-		select {
-		case ch <- c:
-		default:
-		}
+	for sub := range h.subs[c.Symbol] {
+		sub.offer(c)
 	}
 }
 
-func (h *Hub) Subscribe(symbol string) (chan *pricev1.Candle, func()) {
-	ch := make(chan *pricev1.Candle, 1024)
+func (h *Hub) Subscribe(symbol string) (<-chan *pricev1.Candle, func()) {
+	return h.SubscribeWith(symbol, SubscribeOptions{})
+}
+
+// SubscribeWith subscribes with per-subscriber filtering and conflation.
+func (h *Hub) SubscribeWith(symbol string, o SubscribeOptions) (<-chan *pricev1.Candle, func()) {
+	sub := newConflator(1024, o)
 	h.mu.Lock()
 	if _, ok := h.subs[symbol]; !ok {
-		h.subs[symbol] = make(map[chan *pricev1.Candle]struct{})
+		h.subs[symbol] = make(map[*conflator]struct{})
 	}
-	h.subs[symbol][ch] = struct{}{}
+	h.subs[symbol][sub] = struct{}{}
 	h.mu.Unlock()
 	unsub := func() {
 		h.mu.Lock()
 		if group, ok := h.subs[symbol]; ok {
-			delete(group, ch)
-			close(ch)
+			delete(group, sub)
 			if len(group) == 0 {
 				delete(h.subs, symbol)
 			}
 		}
 		h.mu.Unlock()
+		sub.close()
 	}
-	return ch, unsub
+	return sub.ch, unsub
 }
 
 type Server struct {
 	pricev1.UnimplementedPriceStreamServer
 	hub              *Hub
 	engineIntervalMs int64
+	monitor          *ingest.Monitor
+	books            *Topic[orderbook.Snapshot]
+	quotes           *Topic[common.Quote]
+	profiles         *Topic[*pricev1.VolumeProfile]
+	profileMs        int64
+	trades           *Topic[common.Trade]
+	indicators       *Topic[*pricev1.IndicatorUpdate]
+	fixings          *Topic[*pricev1.Fixing]
+	alerts           *alert.Engine
+	meter            *metering.Meter
+	maxLag           time.Duration
+	calendars        *calendar.Registry
+	heartbeat        time.Duration
+
+	exchMu sync.Mutex
+	exch   map[string][]string // symbolExchanges, computed at exchAt
+	exchAt time.Time
 }
 
-func NewServer(hub *Hub, engineInterval time.Duration) *Server {
-	return &Server{hub: hub, engineIntervalMs: engineInterval.Milliseconds()}
+// ServerOption wires optional engine components into the Server.
+type ServerOption func(*Server)
+
+// WithMonitor enables the exchange status RPCs.
+func WithMonitor(m *ingest.Monitor) ServerOption {
+	return func(s *Server) { s.monitor = m }
+}
+
+func NewServer(hub *Hub, engineInterval time.Duration, opts ...ServerOption) *Server {
+	s := &Server{
+		hub:              hub,
+		engineIntervalMs: engineInterval.Milliseconds(),
+		maxLag:           DefaultMaxLag,
+		calendars:        calendar.Default(),
+		heartbeat:        DefaultHeartbeat,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
 }
 
 func (s *Server) StreamAggregates(req *pricev1.SubscribeRequest, stream pricev1.PriceStream_StreamAggregatesServer) error {
+	return s.streamAggregates(req, stream, pricev1.PriceStream_StreamAggregates_FullMethodName, false)
+}
+
+func (s *Server) StreamAggregateEvents(req *pricev1.SubscribeRequest, stream pricev1.PriceStream_StreamAggregateEventsServer) error {
+	return s.streamAggregates(req, stream, pricev1.PriceStream_StreamAggregateEvents_FullMethodName, true)
+}
+
+// streamAggregates serves both candle streams; envelope selects the
+// AggregateEvents of StreamAggregateEvents.
+func (s *Server) streamAggregates(req *pricev1.SubscribeRequest, stream grpc.ServerStream, method string, envelope bool) error {
 	if req.GetSymbol() == "" {
 		return errors.New("symbol required")
 	}
-	if req.GetIntervalMs() != 0 && req.GetIntervalMs() != s.engineIntervalMs {
-		return errors.New("requested interval not supported by current engine instance")
+	// entitlements cover the symbol, its exchanges and, for time bars, the interval
+	var intervalMs int64
+	if req.GetBarType() == pricev1.BarType_BAR_TIME {
+		intervalMs = cmp.Or(req.GetIntervalMs(), s.engineIntervalMs)
+	}
+	ctx := stream.Context()
+	if err := s.authorize(ctx, method, req.GetSymbol(), intervalMs); err != nil {
+		return err
+	}
+	if req.GetBarType() != pricev1.BarType_BAR_TIME {
+		return s.streamBars(req, stream, method, envelope)
+	}
+	ch, unsub, mask, err := s.subscribe(req)
+	if err != nil {
+		return err
 	}
-	ch, unsub := s.hub.Subscribe(req.GetSymbol())
 	defer unsub()
+	ev, err := s.newEvents(req, stream, envelope)
+	if err != nil {
+		return err
+	}
+	defer ev.stop()
 
 	for {
 		select {
-		case <-stream.Context().Done():
+		case <-ctx.Done():
 			return context.Canceled
-		case c := <-ch:
-			if err := stream.Send(c); err != nil {
+		case now := <-ev.C():
+			if err := ev.tick(now); err != nil {
+				return err
+			}
+		case c, ok := <-ch:
+			if !ok {
+				return ev.resubscribe("subscriber fell behind")
+			}
+			if err := authorizeCandle(ctx, method, c); err != nil {
+				return err
+			}
+			if err := ev.candle(mask.apply(c)); err != nil {
 				return err
 			}
 		}
 	}
 }
 
-func Serve(addr string, s *Server) error {
-	lis, err := net.Listen("tcp", addr)
+// subscribe validates a time-bar request and subscribes to the Hub. The
+// gRPC stream and the HTTP gateway share it.
+func (s *Server) subscribe(req *pricev1.SubscribeRequest) (<-chan *pricev1.Candle, func(), fieldMask, error) {
+	if req.GetIntervalMs() != 0 && req.GetIntervalMs() != s.engineIntervalMs {
+		return nil, nil, nil, status.Error(codes.InvalidArgument, "requested interval not supported by current engine instance")
+	}
+	opts, err := subscribeOptions(req)
 	if err != nil {
-		return err
+		return nil, nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	mask, err := newFieldMask(req.GetFields())
+	if err != nil {
+		return nil, nil, nil, status.Error(codes.InvalidArgument, err.Error())
 	}
-	grpcServer := grpc.NewServer()
-	pricev1.RegisterPriceStreamServer(grpcServer, s)
-	reflection.Register(grpcServer)
-	return grpcServer.Serve(lis)
+	ch, unsub := s.hub.SubscribeWith(req.GetSymbol(), opts)
+	return ch, unsub, mask, nil
 }
@@ -0,0 +1,125 @@
+// path: pkg/grpcapi/status.go
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/binaridigital/price-engine/pkg/ingest"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+var errNoMonitor = errors.New("exchange status not available on this engine instance")
+
+func (s *Server) GetExchangeStatus(ctx context.Context, req *pricev1.ExchangeStatusRequest) (*pricev1.ExchangeStatusResponse, error) {
+	if s.monitor == nil {
+		return nil, errNoMonitor
+	}
+	if err := s.authorizeStatus(ctx, pricev1.PriceStream_GetExchangeStatus_FullMethodName, req); err != nil {
+		return nil, err
+	}
+	resp := &pricev1.ExchangeStatusResponse{}
+	for _, st := range s.monitor.Snapshot() {
+		if matchStatus(req, st) && allowed(ctx, st.Symbol) {
+			resp.Statuses = append(resp.Statuses, exchangeStatus(st))
+		}
+	}
+	return resp, nil
+}
+
+func (s *Server) StreamExchangeStatus(req *pricev1.ExchangeStatusRequest, stream pricev1.PriceStream_StreamExchangeStatusServer) error {
+	if s.monitor == nil {
+		return errNoMonitor
+	}
+	ctx := stream.Context()
+	if err := s.authorizeStatus(ctx, pricev1.PriceStream_StreamExchangeStatus_FullMethodName, req); err != nil {
+		return err
+	}
+	// subscribe before the snapshot so no transition falls in between
+	ch, unsub := s.monitor.Subscribe()
+	defer unsub()
+	for _, st := range s.monitor.Snapshot() {
+		if !matchStatus(req, st) || !allowed(ctx, st.Symbol) {
+			continue
+		}
+		if err := stream.Send(exchangeStatus(st)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return context.Canceled
+		case st, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if !matchStatus(req, st) || !allowed(ctx, st.Symbol) {
+				continue
+			}
+			if err := stream.Send(exchangeStatus(st)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// authorizeStatus checks a request for one symbol's status; without one,
+// statuses of symbols the caller may not see are left out instead.
+func (s *Server) authorizeStatus(ctx context.Context, method string, req *pricev1.ExchangeStatusRequest) error {
+	if req.GetSymbol() == "" {
+		return nil
+	}
+	return s.authorize(ctx, method, strings.ToUpper(req.GetSymbol()), 0)
+}
+
+func matchStatus(req *pricev1.ExchangeStatusRequest, st ingest.Status) bool {
+	return (req.GetExchange() == "" || req.GetExchange() == st.Exchange) &&
+		(req.GetSymbol() == "" || req.GetSymbol() == st.Symbol)
+}
+
+func exchangeStatus(st ingest.Status) *pricev1.ExchangeStatus {
+	out := &pricev1.ExchangeStatus{
+		Exchange:       st.Exchange,
+		Symbol:         st.Symbol,
+		Replica:        uint32(st.Replica),
+		Feed:           feedKind(st.Kind),
+		State:          connectorState(st.State),
+		StateSinceTs:   st.Since.UnixMilli(),
+		ReconnectCount: st.Reconnects,
+		LastError:      st.LastError,
+	}
+	if !st.LastMessage.IsZero() {
+		out.LastMessageTs = st.LastMessage.UnixMilli()
+	}
+	return out
+}
+
+func feedKind(k ingest.FeedKind) pricev1.FeedKind {
+	switch k {
+	case ingest.FeedTrades:
+		return pricev1.FeedKind_FK_TRADES
+	case ingest.FeedQuotes:
+		return pricev1.FeedKind_FK_QUOTES
+	case ingest.FeedDepth:
+		return pricev1.FeedKind_FK_DEPTH
+	}
+	return pricev1.FeedKind_FK_UNSPECIFIED
+}
+
+func connectorState(s ingest.HealthState) pricev1.ConnectorState {
+	switch s {
+	case ingest.StateConnecting:
+		return pricev1.ConnectorState_CS_CONNECTING
+	case ingest.StateLive:
+		return pricev1.ConnectorState_CS_LIVE
+	case ingest.StateStale:
+		return pricev1.ConnectorState_CS_STALE
+	case ingest.StateBackingOff:
+		return pricev1.ConnectorState_CS_BACKING_OFF
+	case ingest.StateAuthFailed:
+		return pricev1.ConnectorState_CS_AUTH_FAILED
+	}
+	return pricev1.ConnectorState_CS_UNSPECIFIED
+}
@@ -0,0 +1,36 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/ingest"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+func TestExchangeStatusFeed(t *testing.T) {
+	mon := ingest.NewMonitor()
+	mon.Track("binance", "BTCUSDT", 0).Live()
+	mon.TrackFeed(ingest.FeedQuotes, "binance-bbo", "BTCUSDT", 0)
+	mon.TrackFeed(ingest.FeedDepth, "binance-depth", "BTCUSDT", 1)
+	s := NewServer(NewHub(), time.Second, WithMonitor(mon))
+
+	resp, err := s.GetExchangeStatus(context.Background(), &pricev1.ExchangeStatusRequest{Symbol: "BTCUSDT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]pricev1.FeedKind{
+		"binance":       pricev1.FeedKind_FK_TRADES,
+		"binance-bbo":   pricev1.FeedKind_FK_QUOTES,
+		"binance-depth": pricev1.FeedKind_FK_DEPTH,
+	}
+	if len(resp.GetStatuses()) != len(want) {
+		t.Fatalf("statuses %v", resp.GetStatuses())
+	}
+	for _, st := range resp.GetStatuses() {
+		if st.GetFeed() != want[st.GetExchange()] {
+			t.Errorf("%s: feed %v, want %v", st.GetExchange(), st.GetFeed(), want[st.GetExchange()])
+		}
+	}
+}
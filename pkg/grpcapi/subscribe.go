@@ -0,0 +1,189 @@
+// path: pkg/grpcapi/subscribe.go
+package grpcapi
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// SubscribeOptions shape what a candle subscriber receives.
+type SubscribeOptions struct {
+	FinalOnly bool    // drop partial updates
+	MaxRate   float64 // max partial updates per second, 0 = unlimited
+}
+
+func subscribeOptions(req *pricev1.SubscribeRequest) (SubscribeOptions, error) {
+	if req.GetMaxUpdatesPerSec() < 0 {
+		return SubscribeOptions{}, fmt.Errorf("max_updates_per_sec must be >= 0")
+	}
+	return SubscribeOptions{FinalOnly: req.GetFinalOnly(), MaxRate: req.GetMaxUpdatesPerSec()}, nil
+}
+
+// conflator delivers candles to one subscriber. Partial updates beyond
+// MaxRate are conflated: only the latest partial of a window is kept and
+// sent when the rate allows, or dropped when the window's final candle
+// arrives first. Final candles are never held back.
+type conflator struct {
+	ch    chan *pricev1.Candle
+	final bool
+	every time.Duration
+
+	mu      sync.Mutex
+	last    time.Time
+	pending *pricev1.Candle
+	timer   *time.Timer
+	closed  bool
+}
+
+func newConflator(buf int, o SubscribeOptions) *conflator {
+	c := &conflator{ch: make(chan *pricev1.Candle, buf), final: o.FinalOnly}
+	if o.MaxRate > 0 {
+		c.every = time.Duration(float64(time.Second) / o.MaxRate)
+	}
+	return c
+}
+
+func (c *conflator) offer(cd *pricev1.Candle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	if cd.GetIsFinal() {
+		if p := c.pending; p != nil && p.GetWindowStartMs() != cd.GetWindowStartMs() {
+			c.send(p)
+		}
+		c.pending = nil
+		c.send(cd)
+		return
+	}
+	if c.final {
+		return
+	}
+	if c.every == 0 {
+		c.send(cd)
+		return
+	}
+	now := time.Now()
+	if p := c.pending; p != nil && p.GetWindowStartMs() != cd.GetWindowStartMs() {
+		// a newer window started before the older partial went out
+		c.send(p)
+		c.pending = nil
+	}
+	if wait := c.every - now.Sub(c.last); wait > 0 {
+		if c.pending == nil {
+			c.timer = time.AfterFunc(wait, c.release)
+		}
+		c.pending = cd
+		return
+	}
+	c.last = now
+	c.pending = nil // superseded; the timer finds nothing to release
+	c.send(cd)
+}
+
+func (c *conflator) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || c.pending == nil {
+		return
+	}
+	if wait := c.every - time.Since(c.last); wait > 0 {
+		// an earlier timer whose partial was superseded by a direct send
+		c.timer = time.AfterFunc(wait, c.release)
+		return
+	}
+	c.last = time.Now()
+	c.send(c.pending)
+	c.pending = nil
+}
+
+// send must be called with c.mu held. A subscriber whose buffer is full
+// has fallen too far behind to be caught up: it is cut off by closing its
+// channel, so it can resubscribe and backfill instead of silently missing
+// candles.
+func (c *conflator) send(cd *pricev1.Candle) {
+	if c.closed {
+		return
+	}
+	select {
+	case c.ch <- cd:
+	default:
+		c.closeLocked()
+	}
+}
+
+func (c *conflator) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closeLocked()
+}
+
+func (c *conflator) closeLocked() {
+	if c.closed {
+		return
+	}
+	c.closed = true
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	close(c.ch)
+}
+
+// Fields every masked candle keeps so clients can still tell updates apart.
+var candleKeyFields = []protoreflect.Name{"symbol", "window_start_ms", "window_end_ms", "is_final", "revision"}
+
+// fieldMask restricts a candle to the requested top-level fields.
+type fieldMask []protoreflect.FieldDescriptor
+
+// newFieldMask validates names against Candle's proto field names; no names
+// means the full candle.
+func newFieldMask(names []string) (fieldMask, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	fields := (&pricev1.Candle{}).ProtoReflect().Descriptor().Fields()
+	seen := make(map[protoreflect.Name]bool)
+	var m fieldMask
+	add := func(n protoreflect.Name) error {
+		if seen[n] {
+			return nil
+		}
+		fd := fields.ByName(n)
+		if fd == nil {
+			return fmt.Errorf("unknown candle field %q", n)
+		}
+		seen[n] = true
+		m = append(m, fd)
+		return nil
+	}
+	for _, n := range candleKeyFields {
+		_ = add(n)
+	}
+	for _, n := range names {
+		if err := add(protoreflect.Name(n)); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m fieldMask) apply(c *pricev1.Candle) *pricev1.Candle {
+	if m == nil {
+		return c
+	}
+	src := c.ProtoReflect()
+	out := &pricev1.Candle{}
+	dst := out.ProtoReflect()
+	for _, fd := range m {
+		if src.Has(fd) {
+			dst.Set(fd, src.Get(fd))
+		}
+	}
+	return out
+}
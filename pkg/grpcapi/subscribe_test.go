@@ -0,0 +1,153 @@
+package grpcapi
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// cd is a candle of window w at revision rev; final when rev is negative.
+func cd(w int64, rev int32) *pricev1.Candle {
+	c := &pricev1.Candle{Symbol: "X", WindowStartMs: w, WindowEndMs: w + 1000, Revision: uint32(max(rev, -rev))}
+	c.IsFinal = rev < 0
+	return c
+}
+
+func label(c *pricev1.Candle) string {
+	if c.IsFinal {
+		return fmt.Sprintf("%d/%dF", c.WindowStartMs, c.Revision)
+	}
+	return fmt.Sprintf("%d/%d", c.WindowStartMs, c.Revision)
+}
+
+// received collects what c delivers until it has been quiet for settle.
+func received(c *conflator, settle time.Duration) (got []string, closed bool) {
+	for {
+		select {
+		case v, ok := <-c.ch:
+			if !ok {
+				return got, true
+			}
+			got = append(got, label(v))
+		case <-time.After(settle):
+			return got, false
+		}
+	}
+}
+
+func TestConflator(t *testing.T) {
+	const every = 40 * time.Millisecond
+	rate := float64(time.Second / every)
+	tests := []struct {
+		name   string
+		opts   SubscribeOptions
+		buf    int
+		offers []*pricev1.Candle
+		want   []string
+		closed bool
+	}{
+		{
+			name:   "unlimited passes everything",
+			offers: []*pricev1.Candle{cd(0, 1), cd(0, 2), cd(0, -3)},
+			want:   []string{"0/1", "0/2", "0/3F"},
+		},
+		{
+			name:   "final only",
+			opts:   SubscribeOptions{FinalOnly: true},
+			offers: []*pricev1.Candle{cd(0, 1), cd(0, -2), cd(1000, 1)},
+			want:   []string{"0/2F"},
+		},
+		{
+			name:   "burst keeps the first and the latest partial",
+			opts:   SubscribeOptions{MaxRate: rate},
+			offers: []*pricev1.Candle{cd(0, 1), cd(0, 2), cd(0, 3), cd(0, 4)},
+			want:   []string{"0/1", "0/4"},
+		},
+		{
+			name:   "final replaces the held partial of its window",
+			opts:   SubscribeOptions{MaxRate: rate},
+			offers: []*pricev1.Candle{cd(0, 1), cd(0, 2), cd(0, -3)},
+			want:   []string{"0/1", "0/3F"},
+		},
+		{
+			name:   "held partial of an older window goes out before the next",
+			opts:   SubscribeOptions{MaxRate: rate},
+			offers: []*pricev1.Candle{cd(0, 1), cd(0, 2), cd(1000, 1)},
+			want:   []string{"0/1", "0/2", "1000/1"},
+		},
+		{
+			name:   "final of a new window flushes the held partial",
+			opts:   SubscribeOptions{MaxRate: rate},
+			offers: []*pricev1.Candle{cd(0, 1), cd(0, 2), cd(1000, -1)},
+			want:   []string{"0/1", "0/2", "1000/1F"},
+		},
+		{
+			name:   "overflow cuts the subscriber off",
+			buf:    2,
+			offers: []*pricev1.Candle{cd(0, 1), cd(0, 2), cd(0, 3), cd(0, -4)},
+			want:   []string{"0/1", "0/2"},
+			closed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := tt.buf
+			if buf == 0 {
+				buf = 16
+			}
+			c := newConflator(buf, tt.opts)
+			defer c.close()
+			for _, o := range tt.offers {
+				c.offer(o)
+			}
+			got, closed := received(c, 3*every)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("received %v, want %v", got, tt.want)
+			}
+			if closed != tt.closed {
+				t.Errorf("closed = %v, want %v", closed, tt.closed)
+			}
+		})
+	}
+}
+
+func TestConflatorRate(t *testing.T) {
+	const every = 20 * time.Millisecond
+	c := newConflator(64, SubscribeOptions{MaxRate: float64(time.Second / every)})
+	defer c.close()
+	start := time.Now()
+	for i := range 50 {
+		c.offer(cd(0, int32(i+1)))
+		time.Sleep(2 * time.Millisecond)
+	}
+	got, _ := received(c, 3*every)
+	elapsed := time.Since(start)
+	if max := int(elapsed/every) + 1; len(got) > max {
+		t.Errorf("%d updates in %v, want at most %d", len(got), elapsed, max)
+	}
+	if len(got) == 0 || got[len(got)-1] != "0/50" {
+		t.Errorf("last update = %v, want 0/50", got)
+	}
+}
+
+func TestFieldMask(t *testing.T) {
+	if _, err := newFieldMask([]string{"close", "nope"}); err == nil {
+		t.Error("unknown field accepted")
+	}
+	m, err := newFieldMask([]string{"close"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := cd(0, -3)
+	c.Open, c.Close = 1, 2
+	got := m.apply(c)
+	if got.Close != 2 || got.Open != 0 || got.Symbol != "X" || !got.IsFinal || got.Revision != 3 {
+		t.Errorf("apply = %v", got)
+	}
+	if nilMask, _ := newFieldMask(nil); nilMask.apply(c) != c {
+		t.Error("empty mask must return the candle as is")
+	}
+}
@@ -0,0 +1,133 @@
+// path: pkg/grpcapi/topic.go
+package grpcapi
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Topic is a per-symbol fan-out like Hub for the non-candle streams. It
+// also keeps the last value per symbol so new subscribers start from the
+// current state instead of waiting for the next update.
+type Topic[T any] struct {
+	mu   sync.RWMutex
+	subs map[string]map[chan T]bool // value: lossless
+	last map[string]T
+	key  func(T) string // nil: one last value per symbol
+	buf  int
+}
+
+func NewTopic[T any](buf int) *Topic[T] {
+	return &Topic[T]{
+		subs: make(map[string]map[chan T]bool),
+		last: make(map[string]T),
+		buf:  buf,
+	}
+}
+
+// NewKeyedTopic is NewTopic for values that are independent series within
+// a symbol, such as named fixings: it keeps the last value per symbol and
+// key(v), for LastAll.
+func NewKeyedTopic[T any](buf int, key func(T) string) *Topic[T] {
+	t := NewTopic[T](buf)
+	t.key = key
+	return t
+}
+
+func (t *Topic[T]) Publish(symbol string, v T) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.key != nil {
+		t.last[symbol+"|"+t.key(v)] = v
+	} else {
+		t.last[symbol] = v
+	}
+	for ch, lossless := range t.subs[symbol] {
+		select {
+		case ch <- v:
+		default:
+			if lossless {
+				t.drop(symbol, ch)
+			}
+		}
+	}
+}
+
+// Last returns the most recent value published for symbol; on a keyed
+// topic, pass "symbol|key".
+func (t *Topic[T]) Last(symbol string) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	v, ok := t.last[symbol]
+	return v, ok
+}
+
+// LastAll returns the most recent value of every key of symbol on a keyed
+// topic, ordered by key, or Last(symbol) on an unkeyed one.
+func (t *Topic[T]) LastAll(symbol string) []T {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.key == nil {
+		if v, ok := t.last[symbol]; ok {
+			return []T{v}
+		}
+		return nil
+	}
+	var keys []string
+	for k := range t.last {
+		if strings.HasPrefix(k, symbol+"|") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	out := make([]T, len(keys))
+	for i, k := range keys {
+		out[i] = t.last[k]
+	}
+	return out
+}
+
+// Subscribe returns a channel of symbol's values. A subscriber that falls
+// behind misses values.
+func (t *Topic[T]) Subscribe(symbol string) (chan T, func()) {
+	return t.subscribe(symbol, false)
+}
+
+// SubscribeLossless is Subscribe for consumers that need every value: a
+// subscriber that falls behind is unsubscribed instead, and its channel
+// closes after the values it did receive.
+func (t *Topic[T]) SubscribeLossless(symbol string) (chan T, func()) {
+	return t.subscribe(symbol, true)
+}
+
+func (t *Topic[T]) subscribe(symbol string, lossless bool) (chan T, func()) {
+	ch := make(chan T, t.buf)
+	t.mu.Lock()
+	if _, ok := t.subs[symbol]; !ok {
+		t.subs[symbol] = make(map[chan T]bool)
+	}
+	t.subs[symbol][ch] = lossless
+	t.mu.Unlock()
+	unsub := func() {
+		t.mu.Lock()
+		t.drop(symbol, ch)
+		t.mu.Unlock()
+	}
+	return ch, unsub
+}
+
+// drop removes and closes ch; t.mu must be held.
+func (t *Topic[T]) drop(symbol string, ch chan T) {
+	group, ok := t.subs[symbol]
+	if !ok {
+		return
+	}
+	if _, ok := group[ch]; ok {
+		delete(group, ch)
+		close(ch)
+	}
+	if len(group) == 0 {
+		delete(t.subs, symbol)
+	}
+}
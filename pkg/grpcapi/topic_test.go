@@ -0,0 +1,52 @@
+package grpcapi
+
+import "testing"
+
+func TestTopicOverflow(t *testing.T) {
+	top := NewTopic[int](2)
+	lossy, unsubLossy := top.Subscribe("X")
+	defer unsubLossy()
+	lossless, unsubLossless := top.SubscribeLossless("X")
+	defer unsubLossless()
+
+	for i := range 3 {
+		top.Publish("X", i)
+	}
+
+	if got := []int{<-lossy, <-lossy}; got[0] != 0 || got[1] != 1 {
+		t.Errorf("lossy subscriber got %v, want [0 1]", got)
+	}
+	top.Publish("X", 3)
+	if v := <-lossy; v != 3 {
+		t.Errorf("lossy subscriber got %d after catching up, want 3", v)
+	}
+
+	var got []int
+	for v := range lossless {
+		got = append(got, v)
+	}
+	if len(got) != 2 || got[0] != 0 || got[1] != 1 {
+		t.Errorf("lossless subscriber got %v before closing, want [0 1]", got)
+	}
+	unsubLossless() // already dropped: must not close twice
+	if v, ok := top.Last("X"); !ok || v != 3 {
+		t.Errorf("Last = %d, %v; want 3, true", v, ok)
+	}
+}
+
+func TestKeyedTopicLast(t *testing.T) {
+	type fix struct{ name, v string }
+	top := NewKeyedTopic(4, func(f fix) string { return f.name })
+	top.Publish("X", fix{"noon", "1"})
+	top.Publish("X", fix{"close", "2"})
+	top.Publish("X", fix{"noon", "3"})
+	top.Publish("XY", fix{"noon", "4"})
+
+	got := top.LastAll("X")
+	if want := []fix{{"close", "2"}, {"noon", "3"}}; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("LastAll(X) = %v, want %v", got, want)
+	}
+	if f, ok := top.Last("XY|noon"); !ok || f.v != "4" {
+		t.Errorf("Last(XY|noon) = %v, %v; want 4", f, ok)
+	}
+}
@@ -0,0 +1,477 @@
+// path: pkg/grpcapi/web.go
+package grpcapi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// maxUnaryRequest bounds Connect unary bodies, matching gRPC's default
+// receive limit.
+const maxUnaryRequest = 4 << 20
+
+type webProtocol int
+
+const (
+	protoGRPCWeb webProtocol = iota + 1
+	protoConnectUnary
+	protoConnectStream
+)
+
+// webHandler serves gRPC-Web and Connect through the gRPC server's
+// ServeHTTP, so the gRPC interceptors apply to both. gRPC-Web (binary and
+// text) goes through improbable-eng's grpcweb wrapper. There is no
+// maintained Connect-to-gRPC translator short of vanguard-go, so Connect
+// requests are translated here: messages are re-framed (and transcoded for
+// the JSON codec) on the fly, so server streams stay streaming.
+// Compression is not supported on Connect.
+type webHandler struct {
+	grpc    http.Handler
+	grpcWeb *grpcweb.WrappedGrpcServer
+}
+
+func newWebHandler(g *grpc.Server) *webHandler {
+	// CORS is handled in front of every protocol, see cors
+	return &webHandler{grpc: g, grpcWeb: grpcweb.WrapServer(g)}
+}
+
+func classify(contentType string) (webProtocol, bool, bool) {
+	ct, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+	switch strings.TrimSpace(ct) {
+	case "application/grpc-web", "application/grpc-web+proto",
+		"application/grpc-web-text", "application/grpc-web-text+proto":
+		return protoGRPCWeb, false, true
+	case "application/proto":
+		return protoConnectUnary, false, true
+	case "application/json":
+		return protoConnectUnary, true, true
+	case "application/connect+proto":
+		return protoConnectStream, false, true
+	case "application/connect+json":
+		return protoConnectStream, true, true
+	}
+	return 0, false, false
+}
+
+// isWebRequest reports whether r is gRPC-Web or Connect rather than gRPC.
+func isWebRequest(r *http.Request) bool {
+	_, _, ok := classify(r.Header.Get("Content-Type"))
+	return ok
+}
+
+func lookupMethod(path string) (protoreflect.MethodDescriptor, error) {
+	svc, method, ok := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if !ok {
+		return nil, fmt.Errorf("malformed method path %q", path)
+	}
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(svc + "." + method))
+	if err != nil {
+		return nil, fmt.Errorf("unknown method %q", path)
+	}
+	md, ok := d.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("unknown method %q", path)
+	}
+	return md, nil
+}
+
+func newMessage(desc protoreflect.MessageDescriptor) (proto.Message, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+	if err != nil {
+		return nil, err
+	}
+	return mt.New().Interface(), nil
+}
+
+// jsonToBinary and binaryToJSON transcode one message of the given type.
+func jsonToBinary(desc protoreflect.MessageDescriptor, b []byte) ([]byte, error) {
+	m, err := newMessage(desc)
+	if err != nil {
+		return nil, err
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	return proto.Marshal(m)
+}
+
+func binaryToJSON(desc protoreflect.MessageDescriptor, b []byte) ([]byte, error) {
+	m, err := newMessage(desc)
+	if err != nil {
+		return nil, err
+	}
+	if err := proto.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	return protojson.Marshal(m)
+}
+
+func frame(flags byte, payload []byte) []byte {
+	out := make([]byte, 5+len(payload))
+	out[0] = flags
+	binary.BigEndian.PutUint32(out[1:5], uint32(len(payload)))
+	copy(out[5:], payload)
+	return out
+}
+
+func readFrame(r io.Reader) (byte, []byte, error) {
+	var hdr [5]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	n := binary.BigEndian.Uint32(hdr[1:])
+	if n > maxUnaryRequest {
+		return 0, nil, fmt.Errorf("message of %d bytes exceeds limit", n)
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	return hdr[0], payload, nil
+}
+
+func (h *webHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p, isJSON, ok := classify(r.Header.Get("Content-Type"))
+	if !ok {
+		http.Error(w, "unsupported content-type", http.StatusUnsupportedMediaType)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if p == protoGRPCWeb {
+		h.grpcWeb.HandleGrpcWebRequest(w, r)
+		return
+	}
+	md, err := lookupMethod(r.URL.Path)
+	if err != nil {
+		writeConnectErrorFor(w, p, codes.Unimplemented, err.Error())
+		return
+	}
+	for _, hdr := range []string{"Connect-Content-Encoding", "Content-Encoding"} {
+		if enc := r.Header.Get(hdr); enc != "" && enc != "identity" {
+			writeConnectErrorFor(w, p, codes.Unimplemented, "compression is not supported")
+			return
+		}
+	}
+
+	inner := r.Clone(r.Context())
+	inner.Proto, inner.ProtoMajor, inner.ProtoMinor = "HTTP/2.0", 2, 0
+	inner.Header.Set("Content-Type", "application/grpc+proto")
+	inner.Header.Set("Te", "trailers")
+	inner.Header.Del("Content-Length")
+	inner.ContentLength = -1
+	if ms := r.Header.Get("Connect-Timeout-Ms"); ms != "" {
+		if n, err := strconv.ParseInt(ms, 10, 64); err == nil && n > 0 {
+			inner.Header.Set("Grpc-Timeout", strconv.FormatInt(n, 10)+"m")
+		}
+	}
+	inner.Header.Del("Connect-Timeout-Ms")
+	inner.Header.Del("Connect-Protocol-Version")
+
+	switch p {
+	case protoConnectUnary:
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxUnaryRequest+1))
+		if err != nil || len(body) > maxUnaryRequest {
+			writeConnectError(w, codes.ResourceExhausted, "request body too large or unreadable")
+			return
+		}
+		if isJSON {
+			if body, err = jsonToBinary(md.Input(), body); err != nil {
+				writeConnectError(w, codes.InvalidArgument, err.Error())
+				return
+			}
+		}
+		inner.Body = io.NopCloser(bytes.NewReader(frame(0, body)))
+	case protoConnectStream:
+		pr, pw := io.Pipe()
+		go func() {
+			for {
+				flags, payload, err := readFrame(r.Body)
+				if errors.Is(err, io.EOF) {
+					pw.Close()
+					return
+				}
+				if err == nil && flags&0x01 != 0 {
+					err = errors.New("compressed messages are not supported")
+				}
+				if err == nil && isJSON {
+					payload, err = jsonToBinary(md.Input(), payload)
+				}
+				if err != nil {
+					pw.CloseWithError(err)
+					return
+				}
+				if _, err := pw.Write(frame(0, payload)); err != nil {
+					return
+				}
+			}
+		}()
+		inner.Body = pr
+	}
+
+	rw := &webResponse{w: w, proto: p, json: isJSON, out: md.Output(), hdr: make(http.Header), code: http.StatusOK}
+	h.grpc.ServeHTTP(rw, inner)
+	rw.finish()
+}
+
+// webResponse receives the gRPC response and writes it in the client's
+// Connect protocol.
+type webResponse struct {
+	w     http.ResponseWriter
+	proto webProtocol
+	json  bool
+	out   protoreflect.MessageDescriptor
+
+	hdr        http.Header // what the gRPC handler sets, trailers included
+	code       int
+	headerSent bool
+	pending    []byte // partial frame awaiting the rest of its bytes
+	unary      []byte // Connect unary: the single response message
+	failed     bool   // a frame could not be transcoded
+}
+
+func (rw *webResponse) Header() http.Header { return rw.hdr }
+
+func (rw *webResponse) WriteHeader(code int) { rw.code = code }
+
+func (rw *webResponse) Write(b []byte) (int, error) {
+	if rw.code != http.StatusOK {
+		// the gRPC server rejected the request before it reached a handler
+		return len(b), nil
+	}
+	rw.pending = append(rw.pending, b...)
+	for len(rw.pending) >= 5 {
+		n := int(binary.BigEndian.Uint32(rw.pending[1:5]))
+		if len(rw.pending) < 5+n {
+			break
+		}
+		flags, payload := rw.pending[0], rw.pending[5:5+n]
+		if err := rw.message(flags, payload); err != nil {
+			return 0, err
+		}
+		rw.pending = rw.pending[5+n:]
+	}
+	return len(b), nil
+}
+
+// message forwards one complete gRPC message.
+func (rw *webResponse) message(flags byte, payload []byte) error {
+	switch rw.proto {
+	case protoConnectUnary:
+		rw.unary = append([]byte(nil), payload...)
+		return nil
+	case protoConnectStream:
+		if rw.json {
+			js, err := binaryToJSON(rw.out, payload)
+			if err != nil {
+				rw.failed = true
+				return err
+			}
+			payload = js
+		}
+		rw.sendHeader()
+		_, err := rw.w.Write(frame(0, payload))
+		return err
+	}
+	return nil
+}
+
+// Flush passes flushes on once a message went out. Before that, the
+// headers wait: grpc.Server flushes them ahead of the status, which would
+// commit a Connect unary error to a 200 response.
+func (rw *webResponse) Flush() {
+	if rw.proto == protoConnectUnary || rw.code != http.StatusOK || !rw.headerSent {
+		return
+	}
+	if f, ok := rw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (rw *webResponse) contentType() string {
+	switch {
+	case rw.proto == protoConnectUnary && rw.json:
+		return "application/json"
+	case rw.proto == protoConnectUnary:
+		return "application/proto"
+	case rw.json:
+		return "application/connect+json"
+	}
+	return "application/connect+proto"
+}
+
+// sendHeader writes the response headers once, without the trailers the
+// gRPC handler declares or sets.
+func (rw *webResponse) sendHeader() {
+	if rw.headerSent {
+		return
+	}
+	rw.headerSent = true
+	out := rw.w.Header()
+	for k, vv := range rw.hdr {
+		if isTrailerKey(k) || k == "Content-Type" || k == "Trailer" {
+			continue
+		}
+		out[k] = vv
+	}
+	out.Set("Content-Type", rw.contentType())
+	out.Del("Content-Length")
+	rw.w.WriteHeader(http.StatusOK)
+}
+
+func isTrailerKey(k string) bool {
+	switch http.CanonicalHeaderKey(k) {
+	case "Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin":
+		return true
+	}
+	return strings.HasPrefix(k, http.TrailerPrefix)
+}
+
+// status returns the gRPC status and the remaining trailers.
+func (rw *webResponse) status() (codes.Code, string, http.Header) {
+	code := codes.Unknown
+	if s := rw.hdr.Get("Grpc-Status"); s != "" {
+		if n, err := strconv.Atoi(s); err == nil {
+			code = codes.Code(n)
+		}
+	} else if rw.code != http.StatusOK {
+		code = codes.Internal
+	}
+	msg, err := url.PathUnescape(rw.hdr.Get("Grpc-Message"))
+	if err != nil {
+		msg = rw.hdr.Get("Grpc-Message")
+	}
+	if rw.failed {
+		code, msg = codes.Internal, "response could not be encoded"
+	}
+	trailers := make(http.Header)
+	for k, vv := range rw.hdr {
+		if name, ok := strings.CutPrefix(k, http.TrailerPrefix); ok {
+			trailers[http.CanonicalHeaderKey(name)] = vv
+		}
+	}
+	if d := rw.hdr.Get("Grpc-Status-Details-Bin"); d != "" {
+		trailers.Set("Grpc-Status-Details-Bin", d)
+	}
+	return code, msg, trailers
+}
+
+// finish writes the status in the client's protocol once the gRPC handler
+// has returned.
+func (rw *webResponse) finish() {
+	code, msg, trailers := rw.status()
+	switch rw.proto {
+	case protoConnectStream:
+		rw.sendHeader()
+		end := map[string]any{}
+		if code != codes.OK {
+			end["error"] = connectError(code, msg)
+		}
+		if len(trailers) > 0 {
+			end["metadata"] = trailers
+		}
+		b, _ := json.Marshal(end)
+		rw.w.Write(frame(0x02, b))
+
+	case protoConnectUnary:
+		if code == codes.OK && rw.unary == nil {
+			code, msg = codes.Internal, "no response message"
+		}
+		body := rw.unary
+		if code == codes.OK && rw.json {
+			js, err := binaryToJSON(rw.out, body)
+			if err != nil {
+				code, msg = codes.Internal, "response could not be encoded"
+			}
+			body = js
+		}
+		for k, vv := range trailers {
+			rw.w.Header()["Trailer-"+k] = vv
+		}
+		if code != codes.OK {
+			rw.headerSent = true
+			writeConnectError(rw.w, code, msg)
+			return
+		}
+		rw.sendHeader()
+		rw.w.Write(body)
+	}
+}
+
+// writeConnectErrorFor answers a Connect request of protocol p with an
+// error before it reached the gRPC server.
+func writeConnectErrorFor(w http.ResponseWriter, p webProtocol, code codes.Code, msg string) {
+	if p == protoConnectUnary {
+		writeConnectError(w, code, msg)
+		return
+	}
+	rw := &webResponse{w: w, proto: p, hdr: make(http.Header), code: http.StatusOK}
+	rw.hdr.Set("Grpc-Status", strconv.Itoa(int(code)))
+	rw.hdr.Set("Grpc-Message", url.PathEscape(msg))
+	rw.finish()
+}
+
+// Connect error codes and their HTTP statuses for unary calls.
+var connectCodes = map[codes.Code]struct {
+	name string
+	http int
+}{
+	codes.Canceled:           {"canceled", 499},
+	codes.Unknown:            {"unknown", http.StatusInternalServerError},
+	codes.InvalidArgument:    {"invalid_argument", http.StatusBadRequest},
+	codes.DeadlineExceeded:   {"deadline_exceeded", http.StatusGatewayTimeout},
+	codes.NotFound:           {"not_found", http.StatusNotFound},
+	codes.AlreadyExists:      {"already_exists", http.StatusConflict},
+	codes.PermissionDenied:   {"permission_denied", http.StatusForbidden},
+	codes.ResourceExhausted:  {"resource_exhausted", http.StatusTooManyRequests},
+	codes.FailedPrecondition: {"failed_precondition", http.StatusBadRequest},
+	codes.Aborted:            {"aborted", http.StatusConflict},
+	codes.OutOfRange:         {"out_of_range", http.StatusBadRequest},
+	codes.Unimplemented:      {"unimplemented", http.StatusNotImplemented},
+	codes.Internal:           {"internal", http.StatusInternalServerError},
+	codes.Unavailable:        {"unavailable", http.StatusServiceUnavailable},
+	codes.DataLoss:           {"data_loss", http.StatusInternalServerError},
+	codes.Unauthenticated:    {"unauthenticated", http.StatusUnauthorized},
+}
+
+func connectError(code codes.Code, msg string) map[string]string {
+	c, ok := connectCodes[code]
+	if !ok {
+		c = connectCodes[codes.Unknown]
+	}
+	e := map[string]string{"code": c.name}
+	if msg != "" {
+		e["message"] = msg
+	}
+	return e
+}
+
+func writeConnectError(w http.ResponseWriter, code codes.Code, msg string) {
+	c, ok := connectCodes[code]
+	if !ok {
+		c = connectCodes[codes.Unknown]
+	}
+	b, _ := json.Marshal(connectError(code, msg))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(c.http)
+	w.Write(b)
+}
@@ -0,0 +1,297 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// webService answers the calls the conformance tests make. Symbol "NOPE"
+// fails before any message (a trailers-only response), "FAIL" fails after
+// the first one.
+type webService struct {
+	pricev1.UnimplementedPriceStreamServer
+}
+
+func (webService) GetCandles(ctx context.Context, req *pricev1.CandlesRequest) (*pricev1.CandlesResponse, error) {
+	if req.GetSymbol() == "NOPE" {
+		return nil, status.Error(codes.NotFound, "no data for symbol NOPE")
+	}
+	grpc.SetTrailer(ctx, metadata.Pairs("x-trace", "abc"))
+	return &pricev1.CandlesResponse{Candles: []*pricev1.Candle{{Symbol: req.GetSymbol(), Close: 100}}}, nil
+}
+
+func (webService) StreamFixings(req *pricev1.FixingRequest, stream pricev1.PriceStream_StreamFixingsServer) error {
+	if req.GetSymbol() == "NOPE" {
+		return status.Error(codes.InvalidArgument, "unknown symbol")
+	}
+	for _, name := range []string{"LDN4PM", "NY10AM"} {
+		if err := stream.Send(&pricev1.Fixing{Name: name, Symbol: req.GetSymbol(), Value: 1.5}); err != nil {
+			return err
+		}
+		if req.GetSymbol() == "FAIL" {
+			return status.Error(codes.Unavailable, "feed lost: 100% down")
+		}
+	}
+	stream.SetTrailer(metadata.Pairs("x-trace", "abc"))
+	return nil
+}
+
+// webServer serves webService through webHandler, as ServeListener does
+// for gRPC-Web and Connect requests.
+func webServer(t *testing.T) string {
+	t.Helper()
+	g := grpc.NewServer()
+	pricev1.RegisterPriceStreamServer(g, webService{})
+	srv := httptest.NewServer(newWebHandler(g))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestWebConformance(t *testing.T) {
+	base := webServer(t)
+	protocols := []struct {
+		name string
+		opts []connect.ClientOption
+	}{
+		{"connect proto", nil},
+		{"connect json", []connect.ClientOption{connect.WithProtoJSON()}},
+		{"grpc-web", []connect.ClientOption{connect.WithGRPCWeb()}},
+	}
+	for _, p := range protocols {
+		t.Run(p.name, func(t *testing.T) {
+			ctx := context.Background()
+			candles := connect.NewClient[pricev1.CandlesRequest, pricev1.CandlesResponse](
+				http.DefaultClient, base+pricev1.PriceStream_GetCandles_FullMethodName, p.opts...)
+			fixings := connect.NewClient[pricev1.FixingRequest, pricev1.Fixing](
+				http.DefaultClient, base+pricev1.PriceStream_StreamFixings_FullMethodName, p.opts...)
+
+			t.Run("unary", func(t *testing.T) {
+				res, err := candles.CallUnary(ctx, connect.NewRequest(&pricev1.CandlesRequest{Symbol: "BTCUSDT"}))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := res.Msg.GetCandles(); len(got) != 1 || got[0].GetClose() != 100 {
+					t.Errorf("response %v", res.Msg)
+				}
+				if got := res.Trailer().Get("X-Trace"); got != "abc" {
+					t.Errorf("trailer x-trace = %q, want abc", got)
+				}
+			})
+
+			t.Run("unary error", func(t *testing.T) {
+				_, err := candles.CallUnary(ctx, connect.NewRequest(&pricev1.CandlesRequest{Symbol: "NOPE"}))
+				if connect.CodeOf(err) != connect.CodeNotFound {
+					t.Fatalf("error %v, want not_found", err)
+				}
+				var ce *connect.Error
+				if errors.As(err, &ce) && ce.Message() != "no data for symbol NOPE" {
+					t.Errorf("message %q", ce.Message())
+				}
+			})
+
+			t.Run("server stream", func(t *testing.T) {
+				stream, err := fixings.CallServerStream(ctx, connect.NewRequest(&pricev1.FixingRequest{Symbol: "BTCUSDT"}))
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer stream.Close()
+				var names []string
+				for stream.Receive() {
+					names = append(names, stream.Msg().GetName())
+				}
+				if err := stream.Err(); err != nil {
+					t.Fatal(err)
+				}
+				if strings.Join(names, ",") != "LDN4PM,NY10AM" {
+					t.Errorf("received %v", names)
+				}
+				if got := stream.ResponseTrailer().Get("X-Trace"); got != "abc" {
+					t.Errorf("trailer x-trace = %q, want abc", got)
+				}
+			})
+
+			t.Run("trailers-only error", func(t *testing.T) {
+				stream, err := fixings.CallServerStream(ctx, connect.NewRequest(&pricev1.FixingRequest{Symbol: "NOPE"}))
+				if err == nil {
+					defer stream.Close()
+					if stream.Receive() {
+						t.Fatal("received a message")
+					}
+					err = stream.Err()
+				}
+				if connect.CodeOf(err) != connect.CodeInvalidArgument {
+					t.Fatalf("error %v, want invalid_argument", err)
+				}
+			})
+
+			t.Run("error after a message", func(t *testing.T) {
+				stream, err := fixings.CallServerStream(ctx, connect.NewRequest(&pricev1.FixingRequest{Symbol: "FAIL"}))
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer stream.Close()
+				n := 0
+				for stream.Receive() {
+					n++
+				}
+				if n != 1 {
+					t.Errorf("received %d messages, want 1", n)
+				}
+				var ce *connect.Error
+				if !errors.As(stream.Err(), &ce) || ce.Code() != connect.CodeUnavailable || ce.Message() != "feed lost: 100% down" {
+					t.Fatalf("error %v, want unavailable: feed lost: 100%% down", stream.Err())
+				}
+			})
+		})
+	}
+}
+
+// webTextCall makes a grpc-web-text call and returns the decoded frames
+// of the response, and its headers.
+func webTextCall(t *testing.T, url string, req proto.Message) ([][]byte, [][]byte, http.Header) {
+	t.Helper()
+	b, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := base64.StdEncoding.EncodeToString(frame(0, b))
+	resp, err := http.Post(url, "application/grpc-web-text+proto", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %s", resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/grpc-web-text+proto" {
+		t.Errorf("content-type %q", ct)
+	}
+	text, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// each message is encoded on its own, padding included: decode it one
+	// 4-byte quantum at a time
+	var raw []byte
+	for i := 0; i+4 <= len(text); i += 4 {
+		q, err := base64.StdEncoding.DecodeString(string(text[i : i+4]))
+		if err != nil {
+			t.Fatalf("body is not base64: %v", err)
+		}
+		raw = append(raw, q...)
+	}
+	var msgs, trailers [][]byte
+	r := bytes.NewReader(raw)
+	for r.Len() > 0 {
+		var hdr [5]byte
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			t.Fatal(err)
+		}
+		payload := make([]byte, binary.BigEndian.Uint32(hdr[1:]))
+		if _, err := io.ReadFull(r, payload); err != nil {
+			t.Fatal(err)
+		}
+		if hdr[0]&0x80 != 0 {
+			trailers = append(trailers, payload)
+		} else {
+			msgs = append(msgs, payload)
+		}
+	}
+	return msgs, trailers, resp.Header
+}
+
+func TestWebText(t *testing.T) {
+	base := webServer(t)
+	tests := []struct {
+		name         string
+		method       string
+		req          proto.Message
+		messages     int
+		trailers     []string // "key: value", in the headers when trailers-only
+		trailersOnly bool
+	}{
+		{
+			name:     "unary",
+			method:   pricev1.PriceStream_GetCandles_FullMethodName,
+			req:      &pricev1.CandlesRequest{Symbol: "BTCUSDT"},
+			messages: 1,
+			trailers: []string{"grpc-status: 0", "x-trace: abc"},
+		},
+		{
+			name:     "server stream",
+			method:   pricev1.PriceStream_StreamFixings_FullMethodName,
+			req:      &pricev1.FixingRequest{Symbol: "BTCUSDT"},
+			messages: 2,
+			trailers: []string{"grpc-status: 0", "x-trace: abc"},
+		},
+		{
+			name:         "trailers-only error",
+			method:       pricev1.PriceStream_StreamFixings_FullMethodName,
+			req:          &pricev1.FixingRequest{Symbol: "NOPE"},
+			trailers:     []string{"grpc-status: 3", "grpc-message: unknown symbol"},
+			trailersOnly: true,
+		},
+		{
+			name:     "error after a message",
+			method:   pricev1.PriceStream_StreamFixings_FullMethodName,
+			req:      &pricev1.FixingRequest{Symbol: "FAIL"},
+			messages: 1,
+			trailers: []string{"grpc-status: 14", "grpc-message: feed lost: 100%25 down"},
+		},
+		{
+			name:         "unknown method",
+			method:       "/price.v1.PriceStream/Nope",
+			req:          &pricev1.FixingRequest{},
+			trailers:     []string{"grpc-status: 12"},
+			trailersOnly: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs, trailers, hdr := webTextCall(t, base+tt.method, tt.req)
+			if len(msgs) != tt.messages {
+				t.Errorf("%d messages, want %d", len(msgs), tt.messages)
+			}
+			if tt.trailersOnly {
+				if len(trailers) != 0 {
+					t.Errorf("%d trailer frames in a trailers-only response", len(trailers))
+				}
+				for _, want := range tt.trailers {
+					k, v, _ := strings.Cut(want, ": ")
+					if got := hdr.Get(k); got != v {
+						t.Errorf("header %s %q, want %q", k, got, v)
+					}
+				}
+				return
+			}
+			if len(trailers) != 1 {
+				t.Fatalf("%d trailer frames, want 1", len(trailers))
+			}
+			for _, want := range tt.trailers {
+				if !strings.Contains(string(trailers[0]), want+"\r\n") {
+					t.Errorf("trailers %q lack %q", trailers[0], want)
+				}
+			}
+			if got := hdr.Get("Grpc-Status"); got != "" {
+				t.Errorf("grpc-status header %q outside the trailers", got)
+			}
+		})
+	}
+}
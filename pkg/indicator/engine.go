@@ -0,0 +1,124 @@
+// path: pkg/indicator/engine.go
+package indicator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// Config selects the indicators per symbol; symbols without an entry in
+// PerSymbol use Default.
+type Config struct {
+	Default   []Spec
+	PerSymbol map[string][]Spec
+}
+
+// Empty reports whether no indicator is configured at all.
+func (c Config) Empty() bool {
+	if len(c.Default) > 0 {
+		return false
+	}
+	for _, s := range c.PerSymbol {
+		if len(s) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ParsePerSymbol reads "BTCUSDT=rsi:14,bb:20:2;EURUSD=sma:10".
+func ParsePerSymbol(s string) (map[string][]Spec, error) {
+	out := make(map[string][]Spec)
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		sym, list, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(sym) == "" {
+			return nil, fmt.Errorf("symbol indicators %q: want SYMBOL=spec,...", entry)
+		}
+		specs, err := ParseSpecs(list)
+		if err != nil {
+			return nil, err
+		}
+		out[strings.ToUpper(strings.TrimSpace(sym))] = specs
+	}
+	return out, nil
+}
+
+func (c Config) specs(symbol string) []Spec {
+	if s, ok := c.PerSymbol[symbol]; ok {
+		return s
+	}
+	return c.Default
+}
+
+// Run feeds every final candle into that symbol and interval's indicator
+// set and emits one IndicatorUpdate per candle. Partial candles are ignored
+// so values only move on closed windows.
+func Run(ctx context.Context, candles <-chan *pricev1.Candle, cfg Config) <-chan *pricev1.IndicatorUpdate {
+	out := make(chan *pricev1.IndicatorUpdate, 256)
+	type key struct {
+		symbol   string
+		interval int64
+	}
+	sets := make(map[key][]Indicator)
+
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case c, ok := <-candles:
+				if !ok {
+					return
+				}
+				if !c.GetIsFinal() {
+					continue
+				}
+				k := key{c.GetSymbol(), c.GetWindowEndMs() - c.GetWindowStartMs()}
+				set, seen := sets[k]
+				if !seen {
+					for _, s := range cfg.specs(c.GetSymbol()) {
+						ind, _ := s.New() // specs are validated when parsed
+						set = append(set, ind)
+					}
+					sets[k] = set
+				}
+				if len(set) == 0 {
+					continue
+				}
+				u := &pricev1.IndicatorUpdate{
+					Symbol:        k.symbol,
+					IntervalMs:    k.interval,
+					WindowStartMs: c.GetWindowStartMs(),
+					WindowEndMs:   c.GetWindowEndMs(),
+					Close:         c.GetClose(),
+					Values:        make([]*pricev1.IndicatorValue, len(set)),
+				}
+				for i, ind := range set {
+					v := ind.Update(c)
+					u.Values[i] = &pricev1.IndicatorValue{
+						Name:  v.Name,
+						Value: v.Value,
+						Upper: v.Upper,
+						Lower: v.Lower,
+						Ready: v.Ready,
+					}
+				}
+				select {
+				case out <- u:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,245 @@
+// path: pkg/indicator/indicator.go
+package indicator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// Value is one indicator reading after a final candle. Bands (Bollinger)
+// fill Upper/Lower; Ready is false during the warm-up period.
+type Value struct {
+	Name         string
+	Value        float64
+	Upper, Lower float64
+	Ready        bool
+}
+
+// Indicator is updated incrementally with each final candle.
+type Indicator interface {
+	Name() string
+	Update(c *pricev1.Candle) Value
+}
+
+// Spec describes an indicator as parsed from "kind:period[:param]".
+type Spec struct {
+	Kind   string
+	Period int
+	Param  float64
+}
+
+func (s Spec) String() string {
+	if s.Param != 0 {
+		return fmt.Sprintf("%s:%d:%s", s.Kind, s.Period, strconv.FormatFloat(s.Param, 'f', -1, 64))
+	}
+	return fmt.Sprintf("%s:%d", s.Kind, s.Period)
+}
+
+// ParseSpecs reads a comma-separated list such as
+// "sma:20,ema:50,rsi:14,bb:20:2,atr:14".
+func ParseSpecs(csv string) ([]Spec, error) {
+	var out []Spec
+	for _, part := range strings.Split(csv, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		f := strings.Split(part, ":")
+		if len(f) < 2 || len(f) > 3 {
+			return nil, fmt.Errorf("indicator %q: want kind:period[:param]", part)
+		}
+		n, err := strconv.Atoi(f[1])
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("indicator %q: bad period", part)
+		}
+		s := Spec{Kind: f[0], Period: n}
+		if len(f) == 3 {
+			if s.Param, err = strconv.ParseFloat(f[2], 64); err != nil {
+				return nil, fmt.Errorf("indicator %q: bad parameter", part)
+			}
+		}
+		if _, err := s.New(); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// New creates a fresh indicator for the spec.
+func (s Spec) New() (Indicator, error) {
+	name := s.String()
+	switch s.Kind {
+	case "sma":
+		return &sma{name: name, win: newRing(s.Period)}, nil
+	case "ema":
+		return &ema{name: name, n: s.Period, k: 2 / float64(s.Period+1)}, nil
+	case "rsi":
+		return &rsi{name: name, n: s.Period}, nil
+	case "bb":
+		k := s.Param
+		if k == 0 {
+			k = 2
+		}
+		return &bollinger{name: name, win: newRing(s.Period), k: k}, nil
+	case "atr":
+		return &atr{name: name, n: s.Period}, nil
+	}
+	return nil, fmt.Errorf("unknown indicator %q (sma|ema|rsi|bb|atr)", s.Kind)
+}
+
+// ring is a fixed-size window. Its statistics are computed over the
+// window on each call rather than kept as running sums: periods are short,
+// and sums of squares of prices in the tens of thousands cancel when the
+// window's variance is small.
+type ring struct {
+	buf        []float64
+	next, size int
+}
+
+func newRing(n int) *ring { return &ring{buf: make([]float64, n)} }
+
+func (r *ring) push(v float64) {
+	if r.size < len(r.buf) {
+		r.size++
+	}
+	r.buf[r.next] = v
+	r.next = (r.next + 1) % len(r.buf)
+}
+
+func (r *ring) full() bool { return r.size == len(r.buf) }
+
+func (r *ring) mean() float64 {
+	var sum float64
+	for _, v := range r.buf[:r.size] {
+		sum += v
+	}
+	return sum / float64(r.size)
+}
+
+// variance is the population variance around m, the window's mean.
+func (r *ring) variance(m float64) float64 {
+	var sum float64
+	for _, v := range r.buf[:r.size] {
+		sum += (v - m) * (v - m)
+	}
+	return sum / float64(r.size)
+}
+
+type sma struct {
+	name string
+	win  *ring
+}
+
+func (i *sma) Name() string { return i.name }
+
+func (i *sma) Update(c *pricev1.Candle) Value {
+	i.win.push(c.GetClose())
+	return Value{Name: i.name, Value: i.win.mean(), Ready: i.win.full()}
+}
+
+// ema seeds with the SMA of the first n closes.
+type ema struct {
+	name  string
+	n     int
+	k     float64
+	seen  int
+	seed  float64
+	value float64
+}
+
+func (i *ema) Name() string { return i.name }
+
+func (i *ema) Update(c *pricev1.Candle) Value {
+	i.seen++
+	if i.seen <= i.n {
+		i.seed += c.GetClose()
+		i.value = i.seed / float64(i.seen)
+	} else {
+		i.value += i.k * (c.GetClose() - i.value)
+	}
+	return Value{Name: i.name, Value: i.value, Ready: i.seen >= i.n}
+}
+
+// rsi uses Wilder's smoothing of average gains and losses.
+type rsi struct {
+	name             string
+	n                int
+	seen             int
+	prev             float64
+	avgGain, avgLoss float64
+}
+
+func (i *rsi) Name() string { return i.name }
+
+func (i *rsi) Update(c *pricev1.Candle) Value {
+	cl := c.GetClose()
+	i.seen++
+	if i.seen == 1 {
+		i.prev = cl
+		return Value{Name: i.name}
+	}
+	gain, loss := math.Max(cl-i.prev, 0), math.Max(i.prev-cl, 0)
+	i.prev = cl
+	changes := i.seen - 1
+	if changes <= i.n {
+		i.avgGain += (gain - i.avgGain) / float64(changes)
+		i.avgLoss += (loss - i.avgLoss) / float64(changes)
+	} else {
+		i.avgGain = (i.avgGain*float64(i.n-1) + gain) / float64(i.n)
+		i.avgLoss = (i.avgLoss*float64(i.n-1) + loss) / float64(i.n)
+	}
+	v := 100.0
+	if i.avgLoss > 0 {
+		v = 100 - 100/(1+i.avgGain/i.avgLoss)
+	} else if i.avgGain == 0 {
+		v = 50
+	}
+	return Value{Name: i.name, Value: v, Ready: changes >= i.n}
+}
+
+// bollinger: SMA middle band ± k population standard deviations.
+type bollinger struct {
+	name string
+	win  *ring
+	k    float64
+}
+
+func (i *bollinger) Name() string { return i.name }
+
+func (i *bollinger) Update(c *pricev1.Candle) Value {
+	i.win.push(c.GetClose())
+	m := i.win.mean()
+	sd := math.Sqrt(i.win.variance(m))
+	return Value{Name: i.name, Value: m, Upper: m + i.k*sd, Lower: m - i.k*sd, Ready: i.win.full()}
+}
+
+// atr uses Wilder's smoothing of the true range.
+type atr struct {
+	name      string
+	n         int
+	seen      int
+	prevClose float64
+	value     float64
+}
+
+func (i *atr) Name() string { return i.name }
+
+func (i *atr) Update(c *pricev1.Candle) Value {
+	tr := c.GetHigh() - c.GetLow()
+	if i.seen > 0 {
+		tr = math.Max(tr, math.Max(math.Abs(c.GetHigh()-i.prevClose), math.Abs(c.GetLow()-i.prevClose)))
+	}
+	i.prevClose = c.GetClose()
+	i.seen++
+	if i.seen <= i.n {
+		i.value += (tr - i.value) / float64(i.seen)
+	} else {
+		i.value = (i.value*float64(i.n-1) + tr) / float64(i.n)
+	}
+	return Value{Name: i.name, Value: i.value, Ready: i.seen >= i.n}
+}
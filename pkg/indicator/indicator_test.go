@@ -0,0 +1,108 @@
+package indicator
+
+import (
+	"math"
+	"testing"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// reference series: closes with highs and lows, and each indicator with
+// period 3 computed from its textbook definition; NaN marks warm-up
+var (
+	closes = []float64{10, 11, 12, 11, 13, 14, 12, 15}
+	highs  = []float64{10.5, 11.5, 12.5, 12, 13.5, 14.5, 13.5, 15.5}
+	lows   = []float64{9.5, 10, 11, 10.5, 11, 13, 11.5, 12}
+	nan    = math.NaN()
+)
+
+func TestIndicators(t *testing.T) {
+	sd := func(m float64, w ...float64) float64 {
+		var s float64
+		for _, v := range w {
+			s += (v - m) * (v - m)
+		}
+		return math.Sqrt(s / float64(len(w)))
+	}
+	bbSD := []float64{nan, nan, sd(11, 10, 11, 12), sd(34.0/3, 11, 12, 11), sd(12, 12, 11, 13), sd(38.0/3, 11, 13, 14), sd(13, 13, 14, 12), sd(41.0/3, 14, 12, 15)}
+
+	tests := []struct {
+		spec  string
+		value []float64
+		band  []float64 // distance of Upper and Lower from Value
+	}{
+		{"sma:3", []float64{nan, nan, 11, 34.0 / 3, 12, 38.0 / 3, 13, 41.0 / 3}, nil},
+		{"ema:3", []float64{nan, nan, 11, 11, 12, 13, 12.5, 13.75}, nil},
+		// Wilder: average gain and loss over the first 3 changes, then
+		// smoothed; the average gains over losses are 2, 5, 29/4, 58/62, 359/124
+		{"rsi:3", []float64{nan, nan, nan, 100 - 100/(1+2.0), 100 - 100/(1+5.0),
+			100 - 100/(1+29.0/4), 100 - 100/(1+58.0/62), 100 - 100/(1+359.0/124)}, nil},
+		{"bb:3:2", []float64{nan, nan, 11, 34.0 / 3, 12, 38.0 / 3, 13, 41.0 / 3}, []float64{nan, nan, 2 * bbSD[2], 2 * bbSD[3], 2 * bbSD[4], 2 * bbSD[5], 2 * bbSD[6], 2 * bbSD[7]}},
+		// true ranges 1, 1.5, 1.5, 1.5, 2.5, 1.5, 2.5, 3.5
+		{"atr:3", []float64{nan, nan, 4.0 / 3, 1.3888888888888886, 1.7592592592592589, 1.6728395061728392, 1.9485596707818928, 2.4657064471879284}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			specs, err := ParseSpecs(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ind, err := specs[0].New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, cl := range closes {
+				v := ind.Update(&pricev1.Candle{Symbol: "BTCUSDT", High: highs[i], Low: lows[i], Close: cl, IsFinal: true})
+				want := tt.value[i]
+				if v.Ready != !math.IsNaN(want) {
+					t.Errorf("candle %d: ready %v", i, v.Ready)
+				}
+				if !v.Ready {
+					continue
+				}
+				if math.Abs(v.Value-want) > 1e-9 {
+					t.Errorf("candle %d: %v, want %v", i, v.Value, want)
+				}
+				if tt.band != nil && (math.Abs(v.Upper-v.Value-tt.band[i]) > 1e-9 || math.Abs(v.Value-v.Lower-tt.band[i]) > 1e-9) {
+					t.Errorf("candle %d: bands %v–%v around %v, want ±%v", i, v.Lower, v.Upper, v.Value, tt.band[i])
+				}
+			}
+		})
+	}
+}
+
+// TestBollingerPrecision checks the bands of a narrow window at BTC price
+// levels after a long run of wide moves.
+func TestBollingerPrecision(t *testing.T) {
+	spec := Spec{Kind: "bb", Period: 20, Param: 2}
+	ind, err := spec.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 100000 {
+		ind.Update(&pricev1.Candle{Close: 60000 + float64(i%997)*10.37})
+	}
+	// alternating 65000.00 and 65000.01: standard deviation 0.005
+	var v Value
+	for i := range 20 {
+		v = ind.Update(&pricev1.Candle{Close: 65000 + float64(i%2)*0.01})
+	}
+	if math.Abs(v.Value-65000.005) > 1e-9 || math.Abs(v.Upper-v.Value-0.01) > 1e-9 || math.Abs(v.Value-v.Lower-0.01) > 1e-9 {
+		t.Errorf("bands %.9f–%.9f around %.9f, want ±0.01", v.Lower, v.Upper, v.Value)
+	}
+}
+
+func TestParseSpecs(t *testing.T) {
+	specs, err := ParseSpecs(" SMA:20, ,bb:20:2.5,atr:14")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs) != 3 || specs[0].String() != "sma:20" || specs[1].String() != "bb:20:2.5" || specs[2].String() != "atr:14" {
+		t.Errorf("specs %v", specs)
+	}
+	for _, s := range []string{"sma", "sma:0", "sma:x", "bb:20:wide", "sma:20:1:2", "macd:12"} {
+		if _, err := ParseSpecs(s); err == nil {
+			t.Errorf("ParseSpecs(%q) accepted", s)
+		}
+	}
+}
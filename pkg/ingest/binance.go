@@ -5,20 +5,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
-	"math"
 	"strconv"
 	"strings"
 	"time"
 
-	"nhooyr.io/websocket"
-
 	"github.com/binaridigital/price-engine/pkg/common"
 )
 
-type binanceConnector struct{}
+// binanceStream is Binance's market data websocket.
+const binanceStream = "wss://stream.binance.com:9443"
+
+type binanceConnector struct {
+	monitored
+	base string // websocket base URL
+}
 
-func NewBinance() Connector { return &binanceConnector{} }
+func NewBinance() Connector              { return &binanceConnector{base: binanceStream} }
 func (b *binanceConnector) Name() string { return "binance" }
 
 type binanceTradeMsg struct {
@@ -42,73 +44,50 @@ func (b *binanceConnector) Start(ctx context.Context, symbol string) (<-chan com
 		defer close(trades)
 		defer close(errc)
 
-		sym := strings.ToLower(symbol)
-		url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s@trade", sym)
-
-		backoff := time.Millisecond * 500
-		for {
+		url := fmt.Sprintf("%s/ws/%s@trade", b.base, strings.ToLower(symbol))
+		health := b.track(b.Name(), strings.ToUpper(symbol))
+		report := func(err error) {
 			select {
-			case <-ctx.Done():
-				return
+			case errc <- err:
 			default:
 			}
+		}
 
-			c, _, err := websocket.Dial(ctx, url, nil)
-			if err != nil {
-				errc <- fmt.Errorf("binance dial: %w", err)
-				time.Sleep(backoff)
-				backoff = time.Duration(math.Min(float64(backoff*2), float64(30*time.Second)))
-				continue
-			}
-			backoff = time.Millisecond * 500
-
-			readCtx, cancel := context.WithCancel(ctx)
-			readerErr := make(chan error, 1)
-			go func() {
-				defer cancel()
-				for {
-					typ, data, rerr := c.Read(readCtx)
-					if rerr != nil {
-						readerErr <- rerr
-						return
-					}
-					if typ != websocket.MessageText {
-						continue
-					}
-					var m binanceTradeMsg
-					if err := json.Unmarshal(data, &m); err != nil {
-						errc <- fmt.Errorf("binance unmarshal: %w", err)
-						continue
-					}
-					price, _ := strconv.ParseFloat(m.Price, 64)
-					qty, _ := strconv.ParseFloat(m.Qty, 64)
-					t := common.Trade{
-						Symbol:   strings.ToUpper(m.Symbol),
-						Price:    price,
-						Qty:      qty,
-						Exchange: "binance",
-						TS:       time.UnixMilli(m.TradeTime),
-					}
-					select {
-					case trades <- t:
-					case <-readCtx.Done():
-						return
-					}
+		wsLoop(ctx, wsEndpoint{label: "binance", url: url}, health, report, func(ctx context.Context, frames <-chan wsFrame) error {
+			for f := range frames {
+				var m binanceTradeMsg
+				if err := json.Unmarshal(f.data, &m); err != nil {
+					report(fmt.Errorf("binance unmarshal: %w", err))
+					continue
+				}
+				price, _ := strconv.ParseFloat(m.Price, 64)
+				qty, _ := strconv.ParseFloat(m.Qty, 64)
+				t := common.Trade{
+					Symbol:   strings.ToUpper(m.Symbol),
+					Price:    price,
+					Qty:      qty,
+					Exchange: "binance",
+					TS:       time.UnixMilli(m.TradeTime),
+					TradeID:  strconv.FormatInt(m.TradeID, 10),
+					Side:     aggressor(m.IsMaker),
+				}
+				select {
+				case trades <- t:
+				case <-ctx.Done():
+					return nil
 				}
-			}()
-
-			select {
-			case <-ctx.Done():
-				_ = c.Close(websocket.StatusNormalClosure, "context done")
-				return
-			case re := <-readerErr:
-				_ = c.Close(websocket.StatusAbnormalClosure, "reconnect")
-				log.Printf("binance reconnect: %v", re)
-				time.Sleep(backoff)
-				backoff = time.Duration(math.Min(float64(backoff*2), float64(30*time.Second)))
 			}
-		}
+			return nil
+		})
 	}()
 
 	return trades, errc
 }
+
+// aggressor maps Binance's "buyer is maker" flag to the taker side.
+func aggressor(buyerIsMaker bool) common.Side {
+	if buyerIsMaker {
+		return common.SideSell
+	}
+	return common.SideBuy
+}
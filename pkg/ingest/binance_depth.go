@@ -0,0 +1,168 @@
+// path: pkg/ingest/binance_depth.go
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/orderbook"
+)
+
+// DefaultBookLevels is how many levels per side BinanceDepth publishes.
+const DefaultBookLevels = 100
+
+// BinanceDepth maintains a local L2 book per symbol from Binance's
+// diff-depth stream, synchronised against REST snapshots.
+// Docs: binance-docs "How to manage a local order book correctly".
+type BinanceDepth struct {
+	monitored
+	levels int
+	httpc  *http.Client
+}
+
+func NewBinanceDepth(levels int) *BinanceDepth {
+	if levels <= 0 {
+		levels = DefaultBookLevels
+	}
+	return &BinanceDepth{levels: levels, httpc: &http.Client{Timeout: 5 * time.Second}}
+}
+
+func (b *BinanceDepth) Name() string { return "binance-depth" }
+
+type binanceDepthMsg struct {
+	EventType string      `json:"e"`
+	EventTime int64       `json:"E"`
+	Symbol    string      `json:"s"`
+	FirstID   uint64      `json:"U"`
+	FinalID   uint64      `json:"u"`
+	Bids      [][2]string `json:"b"`
+	Asks      [][2]string `json:"a"`
+}
+
+type binanceDepthSnapshot struct {
+	LastUpdateID uint64      `json:"lastUpdateId"`
+	Bids         [][2]string `json:"bids"`
+	Asks         [][2]string `json:"asks"`
+}
+
+// Start streams a book snapshot (top levels) after every applied diff.
+// Sequence gaps trigger a resync from a fresh REST snapshot without
+// dropping the websocket.
+func (b *BinanceDepth) Start(ctx context.Context, symbol string) (<-chan orderbook.Snapshot, <-chan error) {
+	out := make(chan orderbook.Snapshot, 256)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errc)
+
+		sym := strings.ToUpper(symbol)
+		url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s@depth@100ms", strings.ToLower(sym))
+		health := b.trackFeed(FeedDepth, b.Name(), sym)
+		book := orderbook.New("binance", sym)
+		report := func(err error) {
+			select {
+			case errc <- err:
+			default:
+			}
+		}
+
+		wsLoop(ctx, wsEndpoint{label: "binance depth " + sym, url: url}, health, report, func(ctx context.Context, frames <-chan wsFrame) error {
+			// frames buffer while the snapshot is fetched
+			if err := b.sync(ctx, sym, book); err != nil {
+				return err
+			}
+			for f := range frames {
+				var m binanceDepthMsg
+				if err := json.Unmarshal(f.data, &m); err != nil {
+					report(fmt.Errorf("binance depth unmarshal: %w", err))
+					continue
+				}
+				if err := b.apply(ctx, sym, book, m, out); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}()
+
+	return out, errc
+}
+
+// apply merges one diff into book, resyncing on a sequence gap, and
+// publishes the updated top of the book.
+func (b *BinanceDepth) apply(ctx context.Context, sym string, book *orderbook.Book, m binanceDepthMsg, out chan<- orderbook.Snapshot) error {
+	bids, err := orderbook.ParseLevels(m.Bids)
+	if err != nil {
+		return err
+	}
+	asks, err := orderbook.ParseLevels(m.Asks)
+	if err != nil {
+		return err
+	}
+	changed, err := book.Apply(m.FirstID, m.FinalID, bids, asks, time.UnixMilli(m.EventTime))
+	var gap orderbook.ErrGap
+	if errors.As(err, &gap) {
+		log.Printf("binance depth %s: %v; resyncing", sym, gap)
+		if err := b.sync(ctx, sym, book); err != nil {
+			return err
+		}
+		// the diff may now be applicable against the newer snapshot
+		changed, err = book.Apply(m.FirstID, m.FinalID, bids, asks, time.UnixMilli(m.EventTime))
+		if errors.As(err, &gap) {
+			// still ahead of the snapshot; later diffs will either line up or resync again
+			return nil
+		}
+	}
+	if err != nil || !changed {
+		return err
+	}
+	select {
+	case out <- book.Snapshot(b.levels):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// sync resets book from the REST depth snapshot.
+func (b *BinanceDepth) sync(ctx context.Context, sym string, book *orderbook.Book) error {
+	url := fmt.Sprintf("https://api.binance.com/api/v3/depth?symbol=%s&limit=1000", sym)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := b.httpc.Do(req)
+	if err != nil {
+		return fmt.Errorf("binance depth snapshot: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("binance depth snapshot: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("binance depth snapshot: %s: %s", resp.Status, body)
+	}
+	var snap binanceDepthSnapshot
+	if err := json.Unmarshal(body, &snap); err != nil {
+		return fmt.Errorf("binance depth snapshot: %w", err)
+	}
+	bids, err := orderbook.ParseLevels(snap.Bids)
+	if err != nil {
+		return err
+	}
+	asks, err := orderbook.ParseLevels(snap.Asks)
+	if err != nil {
+		return err
+	}
+	book.Reset(snap.LastUpdateID, bids, asks)
+	return nil
+}
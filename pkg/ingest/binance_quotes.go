@@ -0,0 +1,84 @@
+// path: pkg/ingest/binance_quotes.go
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/binaridigital/price-engine/pkg/common"
+)
+
+// BinanceQuotes streams best bid/offer updates from Binance's @bookTicker.
+type BinanceQuotes struct {
+	monitored
+}
+
+func NewBinanceQuotes() *BinanceQuotes { return &BinanceQuotes{} }
+
+func (b *BinanceQuotes) Name() string { return "binance-quotes" }
+
+type binanceBookTickerMsg struct {
+	UpdateID int64  `json:"u"`
+	Symbol   string `json:"s"`
+	Bid      string `json:"b"`
+	BidQty   string `json:"B"`
+	Ask      string `json:"a"`
+	AskQty   string `json:"A"`
+}
+
+func (b *BinanceQuotes) Start(ctx context.Context, symbol string) (<-chan common.Quote, <-chan error) {
+	quotes := make(chan common.Quote, 2048)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(quotes)
+		defer close(errc)
+
+		sym := strings.ToUpper(symbol)
+		url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s@bookTicker", strings.ToLower(sym))
+		health := b.trackFeed(FeedQuotes, b.Name(), sym)
+		report := func(err error) {
+			select {
+			case errc <- err:
+			default:
+			}
+		}
+
+		wsLoop(ctx, wsEndpoint{label: "binance quotes " + sym, url: url}, health, report, func(ctx context.Context, frames <-chan wsFrame) error {
+			for f := range frames {
+				var m binanceBookTickerMsg
+				if err := json.Unmarshal(f.data, &m); err != nil {
+					report(fmt.Errorf("binance quotes unmarshal: %w", err))
+					continue
+				}
+				bid, _ := strconv.ParseFloat(m.Bid, 64)
+				bidQty, _ := strconv.ParseFloat(m.BidQty, 64)
+				ask, _ := strconv.ParseFloat(m.Ask, 64)
+				askQty, _ := strconv.ParseFloat(m.AskQty, 64)
+				if bid <= 0 || ask <= 0 {
+					continue
+				}
+				q := common.Quote{
+					Symbol:   strings.ToUpper(m.Symbol),
+					Exchange: "binance",
+					Bid:      bid,
+					BidQty:   bidQty,
+					Ask:      ask,
+					AskQty:   askQty,
+					TS:       f.at, // bookTicker carries no event time
+				}
+				select {
+				case quotes <- q:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+			return nil
+		})
+	}()
+
+	return quotes, errc
+}
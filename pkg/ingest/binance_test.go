@@ -0,0 +1,80 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+// tradeFlood serves a websocket that sends trades, with a malformed
+// message every tenth, as fast as the client reads them.
+func tradeFlood(t *testing.T) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.CloseNow()
+		// answers the client's close handshake
+		ctx := c.CloseRead(r.Context())
+		for i := 0; ; i++ {
+			msg := fmt.Sprintf(`{"e":"trade","s":"BTCUSDT","t":%d,"p":"100.5","q":"0.1","T":%d,"m":true}`, i, time.Now().UnixMilli())
+			if i%10 == 9 {
+				msg = "{"
+			}
+			if err := c.Write(ctx, websocket.MessageText, []byte(msg)); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+// TestBinanceStop stops the connector while its reader is delivering:
+// the channels must close only after the reader is done with them.
+func TestBinanceStop(t *testing.T) {
+	base := tradeFlood(t)
+	for range 20 {
+		b := &binanceConnector{base: base}
+		mon := NewMonitor()
+		b.SetMonitor(mon, 0)
+		ctx, cancel := context.WithCancel(context.Background())
+		trades, errc := b.Start(ctx, "btcusdt")
+
+		tr, ok := <-trades
+		if !ok {
+			t.Fatal("trades closed before the first trade")
+		}
+		if tr.Symbol != "BTCUSDT" || tr.Price != 100.5 || tr.Exchange != "binance" {
+			t.Errorf("trade %+v", tr)
+		}
+		if st := mon.Snapshot()[0]; st.State != StateLive {
+			t.Errorf("state %v, want live", st.State)
+		}
+		// stop with the reader mid-stream and nobody reading
+		cancel()
+		timeout := time.After(5 * time.Second)
+		for trades != nil || errc != nil {
+			select {
+			case _, ok := <-trades:
+				if !ok {
+					trades = nil
+				}
+			case _, ok := <-errc:
+				if !ok {
+					errc = nil
+				}
+			case <-timeout:
+				t.Fatal("channels not closed after cancel")
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// path: pkg/ingest/dedup.go
+package ingest
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/common"
+)
+
+// DefaultDedupWindow bounds how long a venue trade ID is remembered.
+const DefaultDedupWindow = 2 * time.Minute
+
+// Deduper drops trades whose venue+symbol+trade ID was already seen within
+// its window, e.g. replays after a reconnect or copies from hot-standby
+// connections. Trades without a TradeID pass through untouched.
+type Deduper struct {
+	window  time.Duration
+	dropped atomic.Uint64
+}
+
+func NewDeduper(window time.Duration) *Deduper {
+	if window <= 0 {
+		window = DefaultDedupWindow
+	}
+	return &Deduper{window: window}
+}
+
+// Dropped returns the number of duplicates removed so far.
+func (d *Deduper) Dropped() uint64 { return d.dropped.Load() }
+
+type seenKey struct {
+	exchange, symbol, id string
+}
+
+type seenEntry struct {
+	key seenKey
+	at  time.Time
+}
+
+func (d *Deduper) Run(ctx context.Context, in <-chan common.Trade) <-chan common.Trade {
+	out := make(chan common.Trade, 2048)
+	go func() {
+		defer close(out)
+		seen := make(map[seenKey]struct{})
+		var order []seenEntry // insertion order == arrival order, oldest first
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case t, ok := <-in:
+				if !ok {
+					return
+				}
+				if t.TradeID != "" {
+					now := time.Now()
+					i := 0
+					for ; i < len(order) && now.Sub(order[i].at) > d.window; i++ {
+						delete(seen, order[i].key)
+					}
+					order = order[i:]
+
+					k := seenKey{t.Exchange, t.Symbol, t.TradeID}
+					if _, dup := seen[k]; dup {
+						d.dropped.Add(1)
+						continue
+					}
+					seen[k] = struct{}{}
+					order = append(order, seenEntry{k, now})
+				}
+				select {
+				case out <- t:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
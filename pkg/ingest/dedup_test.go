@@ -0,0 +1,87 @@
+package ingest
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/common"
+)
+
+func TestDeduper(t *testing.T) {
+	const window = 500 * time.Millisecond
+	d := NewDeduper(window)
+	in := make(chan common.Trade)
+	out := d.Run(t.Context(), in)
+
+	markers := 0
+	// passes sends tr followed by a marker trade and reports whether tr came
+	// out before the marker
+	passes := func(tr common.Trade) bool {
+		t.Helper()
+		markers++
+		marker := common.Trade{Exchange: "marker", Symbol: "X", TradeID: strconv.Itoa(markers)}
+		in <- tr
+		in <- marker
+		passed := false
+		for {
+			select {
+			case got := <-out:
+				if got.Exchange == "marker" {
+					return passed
+				}
+				passed = true
+			case <-time.After(5 * time.Second):
+				t.Fatal("deduper stalled")
+			}
+		}
+	}
+	trade := func(exchange, symbol, id string) common.Trade {
+		return common.Trade{Exchange: exchange, Symbol: symbol, TradeID: id, Price: 100, Qty: 1, TS: time.Now()}
+	}
+
+	steps := []struct {
+		name  string
+		trade common.Trade
+		pass  bool
+	}{
+		{"first", trade("binance", "BTCUSDT", "1"), true},
+		{"copy from a standby replica", trade("binance", "BTCUSDT", "1"), false},
+		{"same ID, other symbol", trade("binance", "ETHUSDT", "1"), true},
+		{"same ID, other venue", trade("kraken", "BTCUSDT", "1"), true},
+		{"next ID", trade("binance", "BTCUSDT", "2"), true},
+		{"no ID", trade("oanda", "EURUSD", ""), true},
+		{"no ID again", trade("oanda", "EURUSD", ""), true},
+		{"replay of the first", trade("binance", "BTCUSDT", "1"), false},
+	}
+	for _, st := range steps {
+		if got := passes(st.trade); got != st.pass {
+			t.Errorf("%s: passed %v, want %v", st.name, got, st.pass)
+		}
+	}
+	if got := d.Dropped(); got != 2 {
+		t.Errorf("Dropped() = %d, want 2", got)
+	}
+
+	// once the window has passed, the ID is forgotten
+	time.Sleep(window + 50*time.Millisecond)
+	if !passes(trade("binance", "BTCUSDT", "1")) {
+		t.Error("ID still remembered after the window")
+	}
+	if passes(trade("binance", "BTCUSDT", "1")) {
+		t.Error("ID seen again after expiry was not remembered")
+	}
+	if got := d.Dropped(); got != 3 {
+		t.Errorf("Dropped() = %d, want 3", got)
+	}
+}
+
+func TestDeduperClose(t *testing.T) {
+	in := make(chan common.Trade)
+	out := NewDeduper(0).Run(context.Background(), in)
+	close(in)
+	if _, ok := <-out; ok {
+		t.Error("output open after the input closed")
+	}
+}
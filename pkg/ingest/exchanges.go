@@ -14,13 +14,25 @@ type Connector interface {
   Start(ctx context.Context, symbol string) (<-chan common.Trade, <-chan error)
 }
 
+// QuoteConnector streams top-of-book quotes instead of trades.
+type QuoteConnector interface {
+  Name() string
+  // Start returns a quote channel and an error channel. Both close on ctx.Done().
+  Start(ctx context.Context, symbol string) (<-chan common.Quote, <-chan error)
+}
+
 // MergeTrades fans in multiple trade channels into one output channel.
 func MergeTrades(ctx context.Context, inputs ...<-chan common.Trade) <-chan common.Trade {
-  out := make(chan common.Trade, 2048)
+  return Merge(ctx, inputs...)
+}
+
+// Merge fans in multiple channels of any feed type into one output channel.
+func Merge[T any](ctx context.Context, inputs ...<-chan T) <-chan T {
+  out := make(chan T, 2048)
   var wg sync.WaitGroup
   wg.Add(len(inputs))
   for _, ch := range inputs {
-    go func(c <-chan common.Trade) {
+    go func(c <-chan T) {
       defer wg.Done()
       for {
         select {
@@ -43,3 +55,35 @@ func MergeTrades(ctx context.Context, inputs ...<-chan common.Trade) <-chan comm
   }()
   return out
 }
+
+// Tee duplicates one feed into n output channels for independent consumers.
+// A slow consumer backs up the others once its buffer is full.
+func Tee[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+  outs := make([]chan T, n)
+  ro := make([]<-chan T, n)
+  for i := range outs {
+    outs[i] = make(chan T, 2048)
+    ro[i] = outs[i]
+  }
+  go func() {
+    defer func() {
+      for _, o := range outs { close(o) }
+    }()
+    for {
+      select {
+      case <-ctx.Done():
+        return
+      case v, ok := <-in:
+        if !ok { return }
+        for _, o := range outs {
+          select {
+          case o <- v:
+          case <-ctx.Done():
+            return
+          }
+        }
+      }
+    }
+  }()
+  return ro
+}
@@ -0,0 +1,240 @@
+// path: pkg/ingest/filter.go
+package ingest
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/common"
+)
+
+// Rejection reasons reported by TickFilter.
+const (
+	RejectNonPositive = "non_positive_price"
+	RejectFuture      = "future_timestamp"
+	RejectOutlier     = "outlier"
+)
+
+// FilterConfig tunes TickFilter. Zero fields take the defaults.
+type FilterConfig struct {
+	Window     int           // accepted prices per symbol the band is computed from
+	MinSamples int           // band is not enforced until this many prices were accepted
+	MaxZ       float64       // max robust z-score |p - median| / (1.4826 × MAD)
+	MinBandBps float64       // floor on the band scale so a flat market does not reject every tick
+	MaxFuture  time.Duration // trades stamped further ahead of the local clock are rejected
+	ResetAfter int           // consecutive outliers after which the band is rebuilt from them
+}
+
+var DefaultFilterConfig = FilterConfig{
+	Window:     200,
+	MinSamples: 20,
+	MaxZ:       10,
+	MinBandBps: 10,
+	MaxFuture:  2 * time.Second,
+	ResetAfter: 20,
+}
+
+// Rejected is a trade removed by TickFilter.
+type Rejected struct {
+	Trade     common.Trade
+	Reason    string
+	Reference float64 // rolling median at rejection time (outliers only)
+	Score     float64 // robust z-score (outliers only)
+	At        time.Time
+}
+
+// TickFilter drops bad prints before aggregation: non-positive prices,
+// future timestamps and prices outside a rolling median ± MAD band per
+// symbol. A run of ResetAfter consecutive outliers is taken as a genuine
+// level change and rebuilds the band from those prices.
+type TickFilter struct {
+	cfg FilterConfig
+
+	mu       sync.Mutex
+	rejected map[string]uint64 // by reason
+}
+
+func NewTickFilter(cfg FilterConfig) *TickFilter {
+	d := DefaultFilterConfig
+	if cfg.Window <= 0 {
+		cfg.Window = d.Window
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = d.MinSamples
+	}
+	if cfg.MinSamples > cfg.Window {
+		cfg.MinSamples = cfg.Window
+	}
+	if cfg.MaxZ <= 0 {
+		cfg.MaxZ = d.MaxZ
+	}
+	if cfg.MinBandBps < 0 {
+		cfg.MinBandBps = d.MinBandBps
+	}
+	if cfg.MaxFuture <= 0 {
+		cfg.MaxFuture = d.MaxFuture
+	}
+	if cfg.ResetAfter <= 0 {
+		cfg.ResetAfter = d.ResetAfter
+	}
+	return &TickFilter{cfg: cfg, rejected: make(map[string]uint64)}
+}
+
+// Rejected returns the number of trades removed so far by reason.
+func (f *TickFilter) Rejected() map[string]uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[string]uint64, len(f.rejected))
+	for k, v := range f.rejected {
+		out[k] = v
+	}
+	return out
+}
+
+type band struct {
+	prices   []float64 // ring of accepted prices
+	next     int
+	sorted   []float64 // the same prices in order, so scoring needs no sort
+	outliers []float64 // current run of consecutive outliers
+}
+
+func (b *band) push(p float64, size int) {
+	if len(b.prices) < size {
+		b.prices = append(b.prices, p)
+	} else {
+		old := b.prices[b.next]
+		i := sort.SearchFloat64s(b.sorted, old)
+		b.sorted = slices.Delete(b.sorted, i, i+1)
+		b.prices[b.next] = p
+		b.next = (b.next + 1) % size
+	}
+	b.sorted = slices.Insert(b.sorted, sort.SearchFloat64s(b.sorted, p), p)
+}
+
+// reset rebuilds the band from prices.
+func (b *band) reset(prices []float64) {
+	b.prices, b.next = append(b.prices[:0], prices...), 0
+	b.sorted = append(b.sorted[:0], prices...)
+	sort.Float64s(b.sorted)
+}
+
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// mad is the median absolute deviation of sorted from its median med. The
+// deviations below and above med are each in order already, so they are
+// merged up to the middle one instead of sorted.
+func mad(sorted []float64, med float64) float64 {
+	n := len(sorted)
+	j := sort.SearchFloat64s(sorted, med)
+	i := j - 1
+	var prev, cur float64
+	for k := 0; k <= n/2; k++ {
+		prev = cur
+		if j >= n || (i >= 0 && med-sorted[i] <= sorted[j]-med) {
+			cur = med - sorted[i]
+			i--
+		} else {
+			cur = sorted[j] - med
+			j++
+		}
+	}
+	if n%2 == 1 {
+		return cur
+	}
+	return (prev + cur) / 2
+}
+
+// score returns the rolling median and p's robust z-score against it.
+func (f *TickFilter) score(b *band, p float64) (float64, float64) {
+	med := median(b.sorted)
+	scale := 1.4826 * mad(b.sorted, med)
+	if floor := med * f.cfg.MinBandBps / 1e4; scale < floor {
+		scale = floor
+	}
+	if scale <= 0 {
+		return med, 0
+	}
+	return med, math.Abs(p-med) / scale
+}
+
+// Run passes clean trades on the first channel. Rejected trades go to the
+// second channel, which is never blocked on: if nobody drains it they are
+// only counted. Synthetic trades are built from filtered legs and are not
+// checked again.
+func (f *TickFilter) Run(ctx context.Context, in <-chan common.Trade) (<-chan common.Trade, <-chan Rejected) {
+	out := make(chan common.Trade, 2048)
+	quarantine := make(chan Rejected, 1024)
+	go func() {
+		defer close(out)
+		defer close(quarantine)
+		bands := make(map[string]*band)
+		reject := func(r Rejected) {
+			f.mu.Lock()
+			f.rejected[r.Reason]++
+			f.mu.Unlock()
+			select {
+			case quarantine <- r:
+			default:
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case t, ok := <-in:
+				if !ok {
+					return
+				}
+				now := time.Now()
+				if !t.Synthetic {
+					if t.Price <= 0 || math.IsNaN(t.Price) || math.IsInf(t.Price, 0) {
+						reject(Rejected{Trade: t, Reason: RejectNonPositive, At: now})
+						continue
+					}
+					if t.TS.After(now.Add(f.cfg.MaxFuture)) {
+						reject(Rejected{Trade: t, Reason: RejectFuture, At: now})
+						continue
+					}
+					b := bands[t.Symbol]
+					if b == nil {
+						b = &band{}
+						bands[t.Symbol] = b
+					}
+					if len(b.prices) >= f.cfg.MinSamples {
+						if med, z := f.score(b, t.Price); z > f.cfg.MaxZ {
+							b.outliers = append(b.outliers, t.Price)
+							if len(b.outliers) < f.cfg.ResetAfter {
+								reject(Rejected{Trade: t, Reason: RejectOutlier, Reference: med, Score: z, At: now})
+								continue
+							}
+							// sustained move: the band was wrong, not the market
+							b.reset(b.outliers)
+							b.outliers = b.outliers[:0]
+						} else {
+							b.outliers = b.outliers[:0]
+							b.push(t.Price, f.cfg.Window)
+						}
+					} else {
+						b.push(t.Price, f.cfg.Window)
+					}
+				}
+				select {
+				case out <- t:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, quarantine
+}
@@ -0,0 +1,112 @@
+package ingest
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/common"
+)
+
+func TestTickFilter(t *testing.T) {
+	f := NewTickFilter(FilterConfig{Window: 10, MinSamples: 5, MaxZ: 10, MinBandBps: 10, MaxFuture: 2 * time.Second, ResetAfter: 3})
+	now := time.Now()
+	trade := func(price float64) common.Trade {
+		return common.Trade{Symbol: "BTCUSDT", Exchange: "binance", Price: price, Qty: 1, TS: now}
+	}
+	future := trade(100)
+	future.TS = now.Add(time.Minute)
+	soon := trade(100.03)
+	soon.TS = now.Add(time.Second)
+	synthetic := trade(0)
+	synthetic.Synthetic = true
+
+	var trades []common.Trade
+	for _, p := range []float64{100, 100.1, 99.9, 100, 100.05} {
+		trades = append(trades, trade(p))
+	}
+	trades = append(trades,
+		trade(0), trade(math.NaN()), future, soon, synthetic,
+		trade(150),                            // outlier
+		trade(100.02),                         // accepted: the run of outliers ends
+		trade(150), trade(151), trade(100.01), // two outliers, then back in the band
+		trade(150), trade(150.5), trade(150.2), // third in a row: the band moves
+		trade(150.1), trade(150.3), trade(150.4),
+		trade(100), // an outlier against the new band
+	)
+
+	in := make(chan common.Trade, len(trades))
+	for _, tr := range trades {
+		in <- tr
+	}
+	close(in)
+	out, quarantine := f.Run(context.Background(), in)
+	var passed []float64
+	for tr := range out {
+		passed = append(passed, tr.Price)
+	}
+	var rejected []Rejected
+	for r := range quarantine {
+		rejected = append(rejected, r)
+	}
+
+	want := []float64{100, 100.1, 99.9, 100, 100.05, 100.03, 0, 100.02, 100.01, 150.2, 150.1, 150.3, 150.4}
+	if !slices.Equal(passed, want) {
+		t.Errorf("passed %v, want %v", passed, want)
+	}
+	var reasons []string
+	for _, r := range rejected {
+		reasons = append(reasons, r.Reason)
+	}
+	wantReasons := []string{RejectNonPositive, RejectNonPositive, RejectFuture,
+		RejectOutlier, RejectOutlier, RejectOutlier, RejectOutlier, RejectOutlier, RejectOutlier}
+	if !slices.Equal(reasons, wantReasons) {
+		t.Fatalf("rejected %v, want %v", reasons, wantReasons)
+	}
+	// the band holds the five warm-up prices and 100.03
+	if r := rejected[3]; r.Trade.Price != 150 || math.Abs(r.Reference-100.015) > 1e-9 || r.Score <= 10 {
+		t.Errorf("first outlier %+v, want 150 against 100.015", r)
+	}
+	if r := rejected[8]; r.Trade.Price != 100 || math.Abs(r.Reference-150.25) > 1e-9 {
+		t.Errorf("last outlier %+v, want 100 against 150.25", r)
+	}
+	counts := f.Rejected()
+	if len(counts) != 3 || counts[RejectNonPositive] != 2 || counts[RejectFuture] != 1 || counts[RejectOutlier] != 6 {
+		t.Errorf("Rejected() = %v", counts)
+	}
+}
+
+func TestBand(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	var b band
+	check := func(step string) {
+		t.Helper()
+		want := slices.Clone(b.prices)
+		sort.Float64s(want)
+		if !slices.Equal(b.sorted, want) {
+			t.Fatalf("%s: sorted %v, want %v", step, b.sorted, want)
+		}
+		med := median(want)
+		dev := make([]float64, len(want))
+		for i, x := range want {
+			dev[i] = math.Abs(x - med)
+		}
+		sort.Float64s(dev)
+		if got := mad(b.sorted, med); got != median(dev) {
+			t.Fatalf("%s: mad %v, want %v", step, got, median(dev))
+		}
+	}
+	for i := range 500 {
+		// few distinct prices, so the window holds ties
+		b.push(100+float64(r.IntN(20))/10, 25)
+		check("push")
+		if i == 300 {
+			b.reset([]float64{150.2, 150, 150.5})
+			check("reset")
+		}
+	}
+}
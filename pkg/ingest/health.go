@@ -0,0 +1,332 @@
+// path: pkg/ingest/health.go
+package ingest
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HealthState is the lifecycle state of one connector stream (exchange+symbol).
+type HealthState int
+
+const (
+	StateConnecting HealthState = iota
+	StateLive
+	StateStale
+	StateBackingOff
+	StateAuthFailed
+)
+
+func (s HealthState) String() string {
+	switch s {
+	case StateConnecting:
+		return "connecting"
+	case StateLive:
+		return "live"
+	case StateStale:
+		return "stale"
+	case StateBackingOff:
+		return "backing_off"
+	case StateAuthFailed:
+		return "auth_failed"
+	}
+	return "unknown"
+}
+
+// FeedKind tells what a connector stream carries.
+type FeedKind int
+
+const (
+	FeedTrades FeedKind = iota
+	FeedQuotes
+	FeedDepth
+)
+
+func (k FeedKind) String() string {
+	switch k {
+	case FeedTrades:
+		return "trades"
+	case FeedQuotes:
+		return "quotes"
+	case FeedDepth:
+		return "depth"
+	}
+	return "unknown"
+}
+
+// Status is a point-in-time view of a connector stream.
+type Status struct {
+	Exchange    string
+	Symbol      string
+	Kind        FeedKind
+	Replica     int // 0 = primary connection, 1.. = hot standby
+	State       HealthState
+	Since       time.Time // when State was entered
+	LastMessage time.Time
+	Reconnects  uint64
+	LastError   string
+}
+
+// Monitorable is implemented by connectors that report health to a Monitor.
+// replica distinguishes hot-standby instances of the same venue.
+type Monitorable interface {
+	SetMonitor(m *Monitor, replica int)
+}
+
+// Monitor collects the health of every connector stream and fans out
+// state changes to subscribers.
+type Monitor struct {
+	mu       sync.RWMutex
+	trackers map[string]*Tracker
+	bySymbol map[string][]*Tracker
+	down     map[string][]string // StaleSources per symbol, updated on state changes
+	subs     map[chan Status]struct{}
+	stale    StaleConfig
+}
+
+// MonitorOption configures a Monitor.
+type MonitorOption func(*Monitor)
+
+// WithStaleConfig overrides DefaultStaleConfig for every tracked stream.
+func WithStaleConfig(c StaleConfig) MonitorOption {
+	return func(m *Monitor) { m.stale = c }
+}
+
+func NewMonitor(opts ...MonitorOption) *Monitor {
+	m := &Monitor{
+		trackers: make(map[string]*Tracker),
+		bySymbol: make(map[string][]*Tracker),
+		down:     make(map[string][]string),
+		subs:     make(map[chan Status]struct{}),
+		stale:    DefaultStaleConfig,
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
+}
+
+// Track returns the tracker for the exchange+symbol trade stream, creating
+// it in the connecting state. A nil Monitor returns a detached Tracker that
+// still measures activity but reports to no one.
+func (m *Monitor) Track(exchange, symbol string, replica int) *Tracker {
+	return m.TrackFeed(FeedTrades, exchange, symbol, replica)
+}
+
+// TrackFeed is Track for a stream of the given kind. Only trade streams
+// count towards StaleSources: candles are built from trades alone.
+func (m *Monitor) TrackFeed(kind FeedKind, exchange, symbol string, replica int) *Tracker {
+	st := Status{Exchange: exchange, Symbol: symbol, Kind: kind, Replica: replica, State: StateConnecting, Since: time.Now()}
+	if m == nil {
+		return &Tracker{st: st, stale: DefaultStaleConfig}
+	}
+	key := fmt.Sprintf("%s/%s/%s/%d", kind, exchange, symbol, replica)
+	m.mu.Lock()
+	t, ok := m.trackers[key]
+	if !ok {
+		t = &Tracker{m: m, st: st, stale: m.stale}
+		m.trackers[key] = t
+		if kind == FeedTrades {
+			m.bySymbol[symbol] = append(m.bySymbol[symbol], t)
+		}
+	}
+	m.mu.Unlock()
+	if !ok {
+		m.refresh(symbol)
+		m.notify(t.Status())
+	}
+	return t
+}
+
+// Snapshot returns the status of every tracked stream, sorted by exchange and symbol.
+func (m *Monitor) Snapshot() []Status {
+	m.mu.RLock()
+	out := make([]Status, 0, len(m.trackers))
+	for _, t := range m.trackers {
+		out = append(out, t.Status())
+	}
+	m.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Exchange != out[j].Exchange {
+			return out[i].Exchange < out[j].Exchange
+		}
+		if out[i].Symbol != out[j].Symbol {
+			return out[i].Symbol < out[j].Symbol
+		}
+		return out[i].Replica < out[j].Replica
+	})
+	return out
+}
+
+// Subscribe streams status changes (state transitions, reconnects and errors).
+// Slow subscribers miss updates rather than block connectors.
+func (m *Monitor) Subscribe() (<-chan Status, func()) {
+	ch := make(chan Status, 64)
+	m.mu.Lock()
+	m.subs[ch] = struct{}{}
+	m.mu.Unlock()
+	return ch, func() {
+		m.mu.Lock()
+		if _, ok := m.subs[ch]; ok {
+			delete(m.subs, ch)
+			close(ch)
+		}
+		m.mu.Unlock()
+	}
+}
+
+// StaleSources lists the exchanges whose trade streams for symbol are all stale
+// or backing off, i.e. not delivering data on any replica, sorted. It reads
+// a set kept up to date on state changes, so it is cheap enough for every
+// candle; the slice must not be modified.
+func (m *Monitor) StaleSources(symbol string) []string {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.down[symbol]
+}
+
+// refresh recomputes StaleSources for symbol.
+func (m *Monitor) refresh(symbol string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	down := make(map[string]bool)
+	for _, t := range m.bySymbol[symbol] {
+		st := t.Status()
+		isDown := st.State == StateStale || st.State == StateBackingOff
+		if prev, seen := down[st.Exchange]; seen {
+			isDown = prev && isDown
+		}
+		down[st.Exchange] = isDown
+	}
+	var out []string
+	for ex, d := range down {
+		if d {
+			out = append(out, ex)
+		}
+	}
+	sort.Strings(out)
+	if len(out) == 0 {
+		delete(m.down, symbol)
+		return
+	}
+	m.down[symbol] = slices.Clip(out) // appending to it copies
+}
+
+func (m *Monitor) notify(s Status) {
+	if m == nil {
+		return
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for ch := range m.subs {
+		select {
+		case ch <- s:
+		default:
+		}
+	}
+}
+
+// Tracker records the health of a single connector stream. All methods
+// are safe on a nil receiver so connectors work without a Monitor.
+type Tracker struct {
+	m     *Monitor
+	mu    sync.Mutex
+	st    Status
+	stale StaleConfig
+	gap   time.Duration // EWMA of the interval between messages
+}
+
+func (t *Tracker) Status() Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.st
+}
+
+// Connecting marks a (re)connection attempt.
+func (t *Tracker) Connecting() { t.transition(StateConnecting, nil, false) }
+
+// Live marks the stream as connected and delivering data.
+func (t *Tracker) Live() { t.transition(StateLive, nil, false) }
+
+// Stale marks a connection that is open but has stopped delivering data.
+func (t *Tracker) Stale() { t.transition(StateStale, nil, false) }
+
+// BackingOff records a failure and counts the reconnect that will follow.
+func (t *Tracker) BackingOff(err error) { t.transition(StateBackingOff, err, true) }
+
+// AuthFailed records a credential/entitlement failure from the venue.
+func (t *Tracker) AuthFailed(err error) { t.transition(StateAuthFailed, err, false) }
+
+// Message records data received at ts, promoting the stream back to live.
+func (t *Tracker) Message(ts time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	if !t.st.LastMessage.IsZero() {
+		d := ts.Sub(t.st.LastMessage)
+		if t.gap == 0 {
+			t.gap = d
+		} else {
+			t.gap += (d - t.gap) / 16
+		}
+	}
+	t.st.LastMessage = ts
+	promote := t.st.State != StateLive
+	t.mu.Unlock()
+	if promote {
+		t.Live()
+	}
+}
+
+func (t *Tracker) transition(s HealthState, err error, reconnect bool) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	changed := t.st.State != s || err != nil || reconnect
+	if err != nil {
+		t.st.LastError = err.Error()
+	}
+	if reconnect {
+		t.st.Reconnects++
+	}
+	moved := t.st.State != s
+	if moved {
+		t.st.State = s
+		t.st.Since = time.Now()
+	}
+	st := t.st
+	t.mu.Unlock()
+	if moved && t.m != nil && st.Kind == FeedTrades {
+		t.m.refresh(st.Symbol)
+	}
+	if changed {
+		t.m.notify(st)
+	}
+}
+
+// monitored is embedded by connectors to implement Monitorable.
+type monitored struct {
+	mon     *Monitor
+	replica int
+}
+
+func (m *monitored) SetMonitor(mon *Monitor, replica int) {
+	m.mon = mon
+	m.replica = replica
+}
+
+func (m *monitored) track(exchange, symbol string) *Tracker {
+	return m.mon.Track(exchange, symbol, m.replica)
+}
+
+func (m *monitored) trackFeed(kind FeedKind, exchange, symbol string) *Tracker {
+	return m.mon.TrackFeed(kind, exchange, symbol, m.replica)
+}
@@ -0,0 +1,40 @@
+package ingest
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestStaleSources(t *testing.T) {
+	m := NewMonitor()
+	a := m.Track("a", "BTCUSDT", 0)
+	aStandby := m.Track("a", "BTCUSDT", 1)
+	b := m.Track("b", "BTCUSDT", 0)
+	other := m.Track("c", "ETHUSDT", 0)
+	depth := m.TrackFeed(FeedDepth, "a-depth", "BTCUSDT", 0)
+
+	check := func(step string, want ...string) {
+		t.Helper()
+		if got := m.StaleSources("BTCUSDT"); !slices.Equal(got, want) {
+			t.Errorf("%s: StaleSources = %v, want %v", step, got, want)
+		}
+	}
+	check("connecting")
+	depth.Stale()
+	check("depth feeds do not count")
+	b.Stale()
+	other.Stale()
+	check("b stale", "b")
+	a.BackingOff(errors.New("eof"))
+	check("a primary down, standby connecting", "b")
+	aStandby.Stale()
+	check("a down on every replica", "a", "b")
+	b.Live()
+	check("b live again", "a")
+	aStandby.Live()
+	check("a standby live")
+	if got := m.StaleSources("ETHUSDT"); !slices.Equal(got, []string{"c"}) {
+		t.Errorf("ETHUSDT: StaleSources = %v, want [c]", got)
+	}
+}
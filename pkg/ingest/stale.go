@@ -0,0 +1,118 @@
+// path: pkg/ingest/stale.go
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+// StaleConfig scales a stream's inactivity timeout to its normal activity:
+// timeout = Factor x (average gap between messages), clamped to [Min, Max].
+// Initial applies until enough messages have been seen to estimate the gap.
+type StaleConfig struct {
+	Factor       float64
+	Min          time.Duration
+	Initial      time.Duration
+	Max          time.Duration
+	PingInterval time.Duration // websocket keepalive; 0 disables
+}
+
+var DefaultStaleConfig = StaleConfig{
+	Factor:       20,
+	Min:          5 * time.Second,
+	Initial:      30 * time.Second,
+	Max:          5 * time.Minute,
+	PingInterval: 15 * time.Second,
+}
+
+var errStale = errors.New("no data within inactivity timeout")
+
+// IdleTimeout is the silence after which the stream is considered stale.
+func (t *Tracker) IdleTimeout() time.Duration {
+	if t == nil {
+		return DefaultStaleConfig.Initial
+	}
+	t.mu.Lock()
+	gap, c := t.gap, t.stale
+	t.mu.Unlock()
+	if gap <= 0 {
+		return c.Initial
+	}
+	d := time.Duration(float64(gap) * c.Factor)
+	if d < c.Min {
+		d = c.Min
+	}
+	if c.Max > 0 && d > c.Max {
+		d = c.Max
+	}
+	return d
+}
+
+// IdleSince reports how long the stream has been silent, counting from
+// since when no message arrived after it (e.g. a fresh connection).
+func (t *Tracker) IdleSince(since time.Time) time.Duration {
+	if t == nil {
+		return 0
+	}
+	t.mu.Lock()
+	last := t.st.LastMessage
+	t.mu.Unlock()
+	if last.Before(since) {
+		last = since
+	}
+	return time.Since(last)
+}
+
+func (t *Tracker) pingInterval() time.Duration {
+	if t == nil {
+		return DefaultStaleConfig.PingInterval
+	}
+	return t.stale.PingInterval
+}
+
+// keepalive pings c and watches for silence until ctx is done. When the
+// connection stops answering pings or goes quiet past the tracker's idle
+// timeout it marks the stream stale and reports on errc, which the caller
+// treats like a read error and reconnects.
+func keepalive(ctx context.Context, c *websocket.Conn, health *Tracker, errc chan<- error) {
+	since := time.Now()
+	check := time.NewTicker(time.Second)
+	defer check.Stop()
+	var ping <-chan time.Time
+	if iv := health.pingInterval(); iv > 0 {
+		pt := time.NewTicker(iv)
+		defer pt.Stop()
+		ping = pt.C
+	}
+	fail := func(err error) {
+		select {
+		case errc <- err:
+		default:
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ping:
+			pctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			err := c.Ping(pctx)
+			cancel()
+			if err != nil && ctx.Err() == nil {
+				health.Stale()
+				fail(fmt.Errorf("ping: %w", err))
+				return
+			}
+		case <-check.C:
+			if idle, limit := health.IdleSince(since), health.IdleTimeout(); idle > limit {
+				health.Stale()
+				fail(fmt.Errorf("%w (%s idle, limit %s)", errStale, idle.Round(time.Second), limit))
+				return
+			}
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
   "io"
   "net/http"
   "os"
+  "strconv"
   "time"
 
   "github.com/binaridigital/price-engine/pkg/common"
@@ -15,15 +16,24 @@ import (
 
 // Twelve Data REST price endpoint example: https://api.twelvedata.com/price\?symbol\=EUR/USD\&apikey\=...
 // We poll ~250ms for MVP. Replace with WS when you enable it on your plan.
+// While requests fail, the poll interval backs off exponentially up to
+// tdMaxBackoff.
 type TwelveData struct {
+  monitored
   apiKey string
   httpc  *http.Client
+  base   string        // API root, for tests
+  poll   time.Duration // interval between successful polls
 }
 
+const tdMaxBackoff = 30 * time.Second
+
 func NewTwelveData() *TwelveData {
   return &TwelveData{
     apiKey: os.Getenv("TWELVEDATA_API_KEY"),
     httpc:  &http.Client{ Timeout: 3 * time.Second },
+    base:   "https://api.twelvedata.com",
+    poll:   250 * time.Millisecond,
   }
 }
 
@@ -32,9 +42,12 @@ func (t *TwelveData) Name() string { return "twelvedata" }
 func (t *TwelveData) Start(ctx context.Context, symbol string) (<-chan common.Trade, <-chan error) {
   out := make(chan common.Trade, 2048)
   errc := make(chan error, 1)
+  health := t.track(t.Name(), normFXSymbol(symbol))
   if t.apiKey == "" {
+    err := fmt.Errorf("TWELVEDATA_API_KEY not set")
+    health.AuthFailed(err)
+    errc <- err
     close(out); close(errc)
-    go func(){ errc <- fmt.Errorf("TWELVEDATA_API_KEY not set") }()
     return out, errc
   }
   symslash := symbol
@@ -42,43 +55,91 @@ func (t *TwelveData) Start(ctx context.Context, symbol string) (<-chan common.Tr
 
   go func() {
     defer close(out); defer close(errc)
-    ticker := time.NewTicker(250 * time.Millisecond)
-    defer ticker.Stop()
+    timer := time.NewTimer(t.poll)
+    defer timer.Stop()
+    started := time.Now()
+    delay := t.poll
+    failing := false // in an outage: reported once, polls backing off
+    fail := func(err error, auth bool) {
+      if !failing {
+        failing = true
+        if auth {
+          health.AuthFailed(err)
+        } else {
+          health.BackingOff(err)
+        }
+      }
+      delay = min(delay*2, tdMaxBackoff)
+    }
 
     for {
       select {
       case <-ctx.Done():
         return
-      case <-ticker.C:
-        url := fmt.Sprintf("https://api.twelvedata.com/price?symbol=%s&apikey=%s", symslash, t.apiKey)
-        req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-        resp, err := t.httpc.Do(req)
-        if err != nil { continue }
-        body, _ := io.ReadAll(resp.Body)
-        _ = resp.Body.Close()
-
-        // Response can be {"price":"1.12345"} or {"price":1.12345}
-        var m map[string]json.RawMessage
-        if err := json.Unmarshal(body, &m); err != nil { continue }
-        var f float64
-        if p, ok := m["price"]; ok {
-          if err := json.Unmarshal(p, &f); err != nil { continue }
-        } else { continue }
+      case <-timer.C:
+      }
+      if !failing && health.IdleSince(started) > health.IdleTimeout() {
+        health.Stale()
+      }
+      f, auth, err := t.price(ctx, symslash)
+      if err != nil {
+        if ctx.Err() != nil { return }
+        fail(err, auth)
+        timer.Reset(delay)
+        continue
+      }
+      if failing {
+        failing, delay, started = false, t.poll, time.Now()
+      }
+      timer.Reset(delay)
+      health.Message(time.Now())
 
-        tr := common.Trade{
-          Symbol:   normFXSymbol(symbol),
-          Price:    f,
-          Qty:      1,
-          Exchange: "twelvedata",
-          TS:       time.Now(),
-        }
-        select {
-        case out <- tr:
-        case <-ctx.Done():
-          return
-        }
+      tr := common.Trade{
+        Symbol:   normFXSymbol(symbol),
+        Price:    f,
+        Qty:      1,
+        Exchange: "twelvedata",
+        TS:       time.Now(),
+      }
+      select {
+      case out <- tr:
+      case <-ctx.Done():
+        return
       }
     }
   }()
   return out, errc
 }
+
+// price polls the current price of symslash ("EUR/USD"). auth is set when
+// the API refused the key.
+func (t *TwelveData) price(ctx context.Context, symslash string) (f float64, auth bool, err error) {
+  url := fmt.Sprintf("%s/price?symbol=%s&apikey=%s", t.base, symslash, t.apiKey)
+  req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+  if err != nil { return 0, false, err }
+  resp, err := t.httpc.Do(req)
+  if err != nil { return 0, false, err }
+  body, err := io.ReadAll(resp.Body)
+  _ = resp.Body.Close()
+  if err != nil { return 0, false, err }
+
+  // Response can be {"price":"1.12345"} or {"price":1.12345}
+  // Errors come back as {"code":401,"message":"...","status":"error"}
+  var m map[string]json.RawMessage
+  if err := json.Unmarshal(body, &m); err != nil {
+    return 0, false, fmt.Errorf("twelvedata: HTTP %d: %w", resp.StatusCode, err)
+  }
+  if p, ok := m["price"]; ok {
+    if err := json.Unmarshal(p, &f); err != nil {
+      var s string
+      if json.Unmarshal(p, &s) != nil { return 0, false, fmt.Errorf("twelvedata: price %s", p) }
+      if f, err = strconv.ParseFloat(s, 64); err != nil { return 0, false, fmt.Errorf("twelvedata: price %q", s) }
+    }
+    return f, false, nil
+  }
+  var code int
+  var msg string
+  _ = json.Unmarshal(m["code"], &code)
+  _ = json.Unmarshal(m["message"], &msg)
+  return 0, code == http.StatusUnauthorized || code == http.StatusForbidden, fmt.Errorf("twelvedata %d: %s", code, msg)
+}
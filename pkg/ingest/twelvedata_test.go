@@ -0,0 +1,118 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTwelveDataMissingKey(t *testing.T) {
+	td := &TwelveData{}
+	mon := NewMonitor()
+	td.SetMonitor(mon, 0)
+	trades, errc := td.Start(context.Background(), "EURUSD")
+	if err := <-errc; err == nil {
+		t.Error("no error without an API key")
+	}
+	if _, ok := <-errc; ok {
+		t.Error("errors open")
+	}
+	if _, ok := <-trades; ok {
+		t.Error("trades open")
+	}
+	if st := mon.Snapshot()[0]; st.State != StateAuthFailed {
+		t.Errorf("state %v, want auth failed", st.State)
+	}
+}
+
+func TestTwelveDataBackoff(t *testing.T) {
+	var (
+		mode     atomic.Value // "ok", "down" or "auth"
+		requests atomic.Int32
+	)
+	mode.Store("ok")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Query().Get("symbol") != "EUR/USD" || r.URL.Query().Get("apikey") != "key" {
+			t.Errorf("query %q", r.URL.RawQuery)
+		}
+		switch mode.Load() {
+		case "ok":
+			fmt.Fprint(w, `{"price":"1.25"}`)
+		case "down":
+			http.Error(w, "upstream unavailable", http.StatusBadGateway)
+		case "auth":
+			fmt.Fprint(w, `{"code":401,"message":"invalid key","status":"error"}`)
+		}
+	}))
+	defer srv.Close()
+
+	const poll = 10 * time.Millisecond
+	td := &TwelveData{apiKey: "key", httpc: srv.Client(), base: srv.URL, poll: poll}
+	mon := NewMonitor()
+	td.SetMonitor(mon, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	trades, _ := td.Start(ctx, "EURUSD")
+
+	next := func() {
+		t.Helper()
+		select {
+		case tr := <-trades:
+			if tr.Symbol != "EURUSD" || tr.Price != 1.25 || tr.Exchange != "twelvedata" {
+				t.Errorf("trade %+v", tr)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("no trade")
+		}
+	}
+	status := func() Status {
+		t.Helper()
+		return mon.Snapshot()[0]
+	}
+
+	next()
+	mode.Store("down")
+	time.Sleep(50 * time.Millisecond) // the trades polled before the outage
+	for len(trades) > 0 {
+		<-trades
+	}
+	start := requests.Load()
+	time.Sleep(600 * time.Millisecond)
+	// at a fixed interval that would be 60 polls; backing off from 10ms it
+	// is about 6
+	if n := requests.Load() - start; n > 10 {
+		t.Errorf("%d polls in 600ms while the API was down", n)
+	}
+	if st := status(); st.State != StateBackingOff || st.Reconnects != 1 {
+		t.Errorf("during the outage: %v with %d reconnects, want backing off with 1", st.State, st.Reconnects)
+	}
+
+	mode.Store("ok")
+	next()
+	if st := status(); st.State != StateLive || st.Reconnects != 1 {
+		t.Errorf("after the outage: %v with %d reconnects, want live with 1", st.State, st.Reconnects)
+	}
+	// polls are back at the base interval
+	start = requests.Load()
+	time.Sleep(200 * time.Millisecond)
+	if n := requests.Load() - start; n < 5 {
+		t.Errorf("%d polls in 200ms after recovering", n)
+	}
+
+	mode.Store("auth")
+	deadline := time.Now().Add(5 * time.Second)
+	for status().State != StateAuthFailed {
+		if time.Now().After(deadline) {
+			t.Fatalf("state %v after the key was refused, want auth failed", status().State)
+		}
+		time.Sleep(poll)
+	}
+	if st := status(); st.Reconnects != 1 || st.LastError != "twelvedata 401: invalid key" {
+		t.Errorf("auth failure: %d reconnects, last error %q", st.Reconnects, st.LastError)
+	}
+}
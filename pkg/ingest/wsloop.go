@@ -0,0 +1,151 @@
+// path: pkg/ingest/wsloop.go
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+// Websocket reconnect backoff.
+const (
+	wsMinBackoff = 500 * time.Millisecond
+	wsMaxBackoff = 30 * time.Second
+)
+
+// wsFrame is a text message and when it was read.
+type wsFrame struct {
+	data []byte
+	at   time.Time
+}
+
+// wsEndpoint is a websocket feed: what it is called in errors and logs,
+// where it is, and the message to send once connected (nil = none), e.g.
+// a subscription.
+type wsEndpoint struct {
+	label string
+	url   string
+	hello []byte
+}
+
+// wsLoop keeps a websocket to ep open until ctx ends. Each connection
+// gets a keepalive and a reader handing text frames to session; when the
+// connection, the keepalive or session fails, it reconnects with
+// exponential backoff. Every step is reported to health, dial errors to
+// report; a 401 or 403 on dial is an authentication failure. session
+// returns when frames closes or ctx ends, and never overlaps the next
+// connection's. wsLoop returns only after the last session did, so
+// session may send on channels the caller closes afterwards.
+func wsLoop(ctx context.Context, ep wsEndpoint, health *Tracker, report func(error), session func(ctx context.Context, frames <-chan wsFrame) error) {
+	backoff := wsMinBackoff
+	wait := func() bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, wsMaxBackoff)
+		return true
+	}
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+		health.Connecting()
+		c, resp, err := websocket.Dial(ctx, ep.url, nil)
+		if err != nil {
+			if resp != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+				health.AuthFailed(err)
+			} else {
+				health.BackingOff(err)
+			}
+			report(fmt.Errorf("%s dial: %w", ep.label, err))
+			if !wait() {
+				return
+			}
+			continue
+		}
+		c.SetReadLimit(1 << 22)
+		backoff = wsMinBackoff
+		health.Live()
+
+		if ep.hello != nil {
+			err = c.Write(ctx, websocket.MessageText, ep.hello)
+		}
+		if err == nil {
+			err = wsSession(ctx, c, health, session)
+		}
+		if ctx.Err() != nil {
+			_ = c.Close(websocket.StatusNormalClosure, "context done")
+			return
+		}
+		_ = c.Close(websocket.StatusAbnormalClosure, "reconnect")
+		health.BackingOff(err)
+		log.Printf("%s reconnect: %v", ep.label, err)
+		if !wait() {
+			return
+		}
+	}
+}
+
+// wsSession runs session on one connection and returns why it ended,
+// once the reader, the keepalive and session have all returned.
+func wsSession(ctx context.Context, c *websocket.Conn, health *Tracker, session func(context.Context, <-chan wsFrame) error) error {
+	readCtx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()                   // a cancelled read closes the connection
+	readerErr := make(chan error, 2) // reader + keepalive
+	frames := make(chan wsFrame, 4096)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		keepalive(readCtx, c, health, readerErr)
+	}()
+	go func() {
+		defer wg.Done()
+		defer close(frames)
+		for {
+			typ, data, err := c.Read(readCtx)
+			if err != nil {
+				readerErr <- err
+				return
+			}
+			now := time.Now()
+			health.Message(now)
+			if typ != websocket.MessageText {
+				continue
+			}
+			select {
+			case frames <- wsFrame{data, now}:
+			case <-readCtx.Done():
+				return
+			}
+		}
+	}()
+
+	done := make(chan error, 1)
+	go func() { done <- session(readCtx, frames) }()
+	var err error
+	select {
+	case err = <-readerErr:
+		cancel()
+		<-done
+	case err = <-done:
+		cancel()
+		if err == nil {
+			select {
+			case err = <-readerErr:
+			default:
+				err = errors.New("connection closed")
+			}
+		}
+	}
+	return err
+}
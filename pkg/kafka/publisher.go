@@ -0,0 +1,445 @@
+// path: pkg/kafka/publisher.go
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/proto"
+
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// ErrBufferFull is returned by Publish when the buffer is full and no spool
+// is configured; the message is dropped.
+var ErrBufferFull = errors.New("kafka: publish buffer full")
+
+// Config tunes a Publisher. Zero fields take the defaults.
+type Config struct {
+	Brokers      []string
+	Topic        string
+	Acks         kafka.RequiredAcks // RequireAll by default
+	BatchSize    int
+	BatchTimeout time.Duration // max time a message waits for its batch
+	Buffer       int           // messages queued in memory before spilling to the spool
+	MaxRetries   int           // attempts per batch after the first, with exponential backoff
+	RetryBackoff time.Duration
+	SpoolDir     string        // "" disables the disk spool; undeliverable messages are then dropped
+	CloseTimeout time.Duration // how long Close keeps delivering before it spools the rest
+}
+
+var DefaultConfig = Config{
+	Acks:         kafka.RequireAll,
+	BatchSize:    500,
+	BatchTimeout: 50 * time.Millisecond,
+	Buffer:       10000,
+	MaxRetries:   5,
+	RetryBackoff: 200 * time.Millisecond,
+	CloseTimeout: 10 * time.Second,
+}
+
+// ParseAcks reads "none", "one" or "all".
+func ParseAcks(s string) (kafka.RequiredAcks, error) {
+	var acks kafka.RequiredAcks
+	err := acks.UnmarshalText([]byte(s))
+	return acks, err
+}
+
+// Stats are cumulative counters of a Publisher.
+type Stats struct {
+	Enqueued  uint64
+	Sent      uint64 // acked by the broker, including replayed messages
+	Retries   uint64 // batch retry attempts
+	Failed    uint64 // messages whose batch exhausted its retries
+	Spooled   uint64 // messages written to disk
+	Replayed  uint64 // spooled messages later delivered
+	Dropped   uint64 // messages lost: buffer full or undeliverable without a spool
+	Buffered  int
+	SpoolSize int64 // messages currently on disk
+	LastError string
+}
+
+func (s Stats) String() string {
+	str := fmt.Sprintf("enqueued=%d sent=%d retries=%d failed=%d spooled=%d replayed=%d dropped=%d buffered=%d on_disk=%d",
+		s.Enqueued, s.Sent, s.Retries, s.Failed, s.Spooled, s.Replayed, s.Dropped, s.Buffered, s.SpoolSize)
+	if s.LastError != "" {
+		str += " last_error=" + s.LastError
+	}
+	return str
+}
+
+// Publisher writes messages asynchronously in batches. Publish never blocks
+// on the broker: messages queue in a bounded buffer, batches are retried
+// with backoff and, if the broker stays unavailable, spilled to a disk
+// spool that is replayed in order once it is back. Delivery is at least
+// once; keys identify duplicates.
+type Publisher struct {
+	cfg    Config
+	writer *kafka.Writer
+	buf    chan kafka.Message
+	spool  *spool
+
+	mu     sync.RWMutex // guards closed against Publish racing Close
+	closed bool
+	done   chan struct{} // batcher exited
+	stop   chan struct{} // stops the replayer
+	wg     sync.WaitGroup
+
+	// ctx bounds broker writes; Close cancels it once CloseTimeout passed
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	enqueued, sent, retries, failed, spooled, replayed, dropped atomic.Uint64
+	lastErr                                                     atomic.Value // string
+	down                                                        atomic.Bool
+}
+
+func NewPublisher(cfg Config) (*Publisher, error) {
+	d := DefaultConfig
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = d.BatchSize
+	}
+	if cfg.BatchTimeout <= 0 {
+		cfg.BatchTimeout = d.BatchTimeout
+	}
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = d.Buffer
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = d.MaxRetries
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = d.RetryBackoff
+	}
+	if cfg.CloseTimeout <= 0 {
+		cfg.CloseTimeout = d.CloseTimeout
+	}
+	p := &Publisher{
+		cfg: cfg,
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Brokers...),
+			Topic:        cfg.Topic,
+			Balancer:     &symbolBalancer{},
+			BatchSize:    cfg.BatchSize,
+			BatchTimeout: time.Millisecond, // batches are formed before WriteMessages
+			RequiredAcks: cfg.Acks,
+			MaxAttempts:  1, // retries are handled here so failures can be spooled
+			WriteTimeout: 10 * time.Second,
+		},
+		buf:  make(chan kafka.Message, cfg.Buffer),
+		done: make(chan struct{}),
+		stop: make(chan struct{}),
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	if cfg.SpoolDir != "" {
+		sp, err := openSpool(spoolDir(cfg.SpoolDir, cfg.Topic))
+		if err != nil {
+			return nil, fmt.Errorf("kafka spool: %w", err)
+		}
+		p.spool = sp
+		p.wg.Add(1)
+		go p.replay()
+	}
+	go p.batch()
+	return p, nil
+}
+
+// CandleKey is the idempotent key of a candle update:
+// symbol|interval ms|window start ms|revision. Partitioning uses only the
+// symbol, so updates of one symbol stay in order.
+func CandleKey(c *pricev1.Candle) string {
+	return fmt.Sprintf("%s|%d|%d|%d", c.GetSymbol(), c.GetWindowEndMs()-c.GetWindowStartMs(), c.GetWindowStartMs(), c.GetRevision())
+}
+
+func (p *Publisher) PublishCandle(c *pricev1.Candle) error {
+	return p.Publish(CandleKey(c), c, time.UnixMilli(c.GetLastTradeTs()))
+}
+
+// Publish queues any proto message. key is "symbol" or "symbol|..."; the
+// part before the first '|' picks the partition.
+func (p *Publisher) Publish(key string, m proto.Message, ts time.Time) error {
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	msg := kafka.Message{Key: []byte(key), Value: b, Time: ts}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return errors.New("kafka: publisher closed")
+	}
+	p.enqueued.Add(1)
+	// Once one message overflowed, the following ones queue behind it
+	// until the batcher caught up (see spool).
+	if p.spool == nil || !p.spool.overflowing() {
+		select {
+		case p.buf <- msg:
+			return nil
+		default:
+		}
+	}
+	if p.spool != nil {
+		err := p.spool.spill(msg)
+		if err == nil {
+			p.spooled.Add(1)
+			return nil
+		}
+		p.lastErr.Store("spool: " + err.Error())
+	}
+	p.dropped.Add(1)
+	return ErrBufferFull
+}
+
+func (p *Publisher) Stats() Stats {
+	s := Stats{
+		Enqueued: p.enqueued.Load(),
+		Sent:     p.sent.Load(),
+		Retries:  p.retries.Load(),
+		Failed:   p.failed.Load(),
+		Spooled:  p.spooled.Load(),
+		Replayed: p.replayed.Load(),
+		Dropped:  p.dropped.Load(),
+		Buffered: len(p.buf),
+	}
+	if p.spool != nil {
+		s.SpoolSize = p.spool.size()
+	}
+	if e, ok := p.lastErr.Load().(string); ok {
+		s.LastError = e
+	}
+	return s
+}
+
+// Close flushes the buffer (spooling what cannot be delivered) and closes
+// the writer.
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.buf)
+	p.mu.Unlock()
+
+	timeout := time.AfterFunc(p.cfg.CloseTimeout, p.cancel)
+	defer timeout.Stop()
+	<-p.done
+	p.cancel()
+	close(p.stop)
+	p.wg.Wait()
+	if p.spool != nil {
+		p.spool.close()
+	}
+	return p.writer.Close()
+}
+
+func (p *Publisher) batch() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.cfg.BatchTimeout)
+	defer ticker.Stop()
+	batch := make([]kafka.Message, 0, p.cfg.BatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			p.flush(batch)
+			batch = make([]kafka.Message, 0, p.cfg.BatchSize)
+		}
+	}
+	for {
+		select {
+		case m, ok := <-p.buf:
+			if !ok {
+				flush()
+				p.catchUp()
+				return
+			}
+			batch = append(batch, m)
+			if len(batch) >= p.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+		if p.spool != nil && p.spool.overflowing() {
+			flush()
+			p.catchUp()
+		}
+	}
+}
+
+// catchUp delivers or spools what is left in the buffer, which Publish no
+// longer adds to while overflowing, then queues the overflow segment behind
+// it.
+func (p *Publisher) catchUp() {
+	if p.spool == nil || !p.spool.overflowing() {
+		return
+	}
+	for {
+		batch := make([]kafka.Message, 0, p.cfg.BatchSize)
+	fill:
+		for len(batch) < p.cfg.BatchSize {
+			select {
+			case m, ok := <-p.buf:
+				if !ok {
+					break fill
+				}
+				batch = append(batch, m)
+			default:
+				break fill
+			}
+		}
+		if len(batch) == 0 {
+			break
+		}
+		p.flush(batch)
+	}
+	if err := p.spool.adopt(); err != nil {
+		p.lastErr.Store("spool: " + err.Error())
+	}
+}
+
+// flush delivers one batch, retrying failed messages with backoff, and
+// spools whatever is left.
+func (p *Publisher) flush(batch []kafka.Message) {
+	// keep topic order: nothing goes live while older messages are on disk
+	if p.spool != nil && p.spool.pending() {
+		p.toSpool(batch)
+		return
+	}
+	backoff := p.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		batch = p.write(batch)
+		if len(batch) == 0 {
+			if p.down.CompareAndSwap(true, false) {
+				log.Printf("kafka %s: broker reachable again", p.cfg.Topic)
+			}
+			return
+		}
+		if attempt >= p.cfg.MaxRetries || p.down.Load() || p.ctx.Err() != nil {
+			break
+		}
+		p.retries.Add(1)
+		select {
+		case <-time.After(backoff):
+		case <-p.ctx.Done():
+		}
+		backoff = min(backoff*2, 5*time.Second)
+	}
+	if !p.down.Swap(true) {
+		log.Printf("kafka %s: delivery failing: %v", p.cfg.Topic, p.lastErr.Load())
+	}
+	p.failed.Add(uint64(len(batch)))
+	p.toSpool(batch)
+}
+
+func (p *Publisher) toSpool(batch []kafka.Message) {
+	if p.spool != nil {
+		err := p.spool.append(batch)
+		if err == nil {
+			p.spooled.Add(uint64(len(batch)))
+			return
+		}
+		p.lastErr.Store("spool: " + err.Error())
+	}
+	p.dropped.Add(uint64(len(batch)))
+}
+
+// write sends batch and returns the messages that were not acked.
+func (p *Publisher) write(batch []kafka.Message) []kafka.Message {
+	ctx, cancel := context.WithTimeout(p.ctx, 15*time.Second)
+	defer cancel()
+	err := p.writer.WriteMessages(ctx, batch...)
+	if err == nil {
+		p.sent.Add(uint64(len(batch)))
+		return nil
+	}
+	p.lastErr.Store(err.Error())
+	var werrs kafka.WriteErrors
+	if !errors.As(err, &werrs) || len(werrs) != len(batch) {
+		return batch
+	}
+	var left []kafka.Message
+	for i, e := range werrs {
+		if e != nil {
+			left = append(left, batch[i])
+		}
+	}
+	p.sent.Add(uint64(len(batch) - len(left)))
+	return left
+}
+
+// replay drains spool segments oldest first whenever the broker accepts
+// writes, checking every two seconds.
+func (p *Publisher) replay() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+		for {
+			name := p.spool.next()
+			if name == "" {
+				break
+			}
+			msgs, err := readSegment(name)
+			if err != nil {
+				log.Printf("kafka %s: spool segment %s unreadable, discarding: %v", p.cfg.Topic, name, err)
+				p.spool.done(name)
+				continue
+			}
+			if !p.replaySegment(msgs) {
+				break
+			}
+			p.replayed.Add(uint64(len(msgs)))
+			if err := p.spool.done(name); err != nil {
+				log.Printf("kafka %s: spool: %v", p.cfg.Topic, err)
+			}
+		}
+	}
+}
+
+// replaySegment sends msgs in batches and reports whether all were acked.
+// A partially sent segment is resent in full later; keys identify the
+// duplicates.
+func (p *Publisher) replaySegment(msgs []kafka.Message) bool {
+	for i := 0; i < len(msgs); i += p.cfg.BatchSize {
+		select {
+		case <-p.stop:
+			return false
+		default:
+		}
+		end := min(i+p.cfg.BatchSize, len(msgs))
+		if left := p.write(msgs[i:end]); len(left) > 0 {
+			p.down.Store(true)
+			return false
+		}
+	}
+	if p.down.CompareAndSwap(true, false) {
+		log.Printf("kafka %s: broker reachable again, replaying spool", p.cfg.Topic)
+	}
+	return true
+}
+
+// symbolBalancer hashes only the symbol part of "symbol|..." keys.
+type symbolBalancer struct {
+	hash kafka.Hash
+}
+
+func (b *symbolBalancer) Balance(msg kafka.Message, partitions ...int) int {
+	if i := bytes.IndexByte(msg.Key, '|'); i >= 0 {
+		msg.Key = msg.Key[:i]
+	}
+	return b.hash.Balance(msg, partitions...)
+}
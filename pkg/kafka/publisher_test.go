@@ -0,0 +1,112 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// TestPublisherOrderWhenFull publishes faster than an unreachable broker
+// can fail batches: whatever ends up on disk must keep publish order.
+func TestPublisherOrderWhenFull(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close() // connections are refused
+
+	dir := t.TempDir()
+	p, err := NewPublisher(Config{
+		Brokers:      []string{addr},
+		Topic:        "t",
+		BatchSize:    3,
+		BatchTimeout: time.Millisecond,
+		Buffer:       4,
+		RetryBackoff: 5 * time.Millisecond,
+		SpoolDir:     dir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 200
+	for i := range n {
+		if err := p.Publish(fmt.Sprintf("S|%03d", i), wrapperspb.Int64(int64(i)), time.Now()); err != nil {
+			t.Fatalf("Publish %d: %v", i, err)
+		}
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	st := p.Stats()
+	if st.Spooled != n || st.Sent != 0 || st.Dropped != 0 {
+		t.Fatalf("stats = %s, want all %d spooled", st, n)
+	}
+	names, _ := filepath.Glob(filepath.Join(spoolDir(dir, "t"), "*"))
+	sort.Strings(names)
+	var keys []string
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".seg") {
+			t.Errorf("unexpected spool file %s", name)
+		}
+		ms, err := readSegment(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range ms {
+			keys = append(keys, string(m.Key))
+		}
+	}
+	if len(keys) != n || !sort.StringsAreSorted(keys) {
+		t.Errorf("spooled %d messages, in order: %v", len(keys), sort.StringsAreSorted(keys))
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestPublisherCloseBounded checks that Close gives up on a broker that
+// accepts connections but never answers.
+func TestPublisherCloseBounded(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	go func() {
+		for {
+			c, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			defer c.Close()
+		}
+	}()
+
+	p, err := NewPublisher(Config{
+		Brokers:      []string{lis.Addr().String()},
+		Topic:        "t",
+		SpoolDir:     t.TempDir(),
+		CloseTimeout: 200 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Publish("S", wrapperspb.Int64(1), time.Now())
+	start := time.Now()
+	p.Close()
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("Close took %v", d)
+	}
+	if st := p.Stats(); st.Spooled != 1 {
+		t.Errorf("stats = %s, want the message spooled", st)
+	}
+}
@@ -0,0 +1,281 @@
+// path: pkg/kafka/spool.go
+package kafka
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// spool is an on-disk FIFO of messages that could not be delivered. Messages
+// are appended to the current segment; the replayer rotates it and sends
+// closed segments oldest first, deleting each once the broker acked it.
+//
+// Publish spills messages that do not fit the buffer to a separate overflow
+// segment, since older messages may still be in the buffer or in flight.
+// The batcher adopts it as the newest segment once those are delivered or
+// spooled; until then the replayer leaves it alone.
+//
+// Record layout: key len (u32) | key | unix nanos (i64) | value len (u32) | value.
+type spool struct {
+	dir string
+
+	mu     sync.Mutex
+	cur    *os.File
+	w      *bufio.Writer
+	over   *os.File
+	ow     *bufio.Writer
+	closed []string         // segment paths ready for replay, oldest first
+	counts map[string]int64 // messages per segment on disk
+}
+
+func openSpool(dir string) (*spool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &spool{dir: dir, counts: make(map[string]int64)}
+	// segments left over from a previous run are replayed first; an
+	// overflow segment last, where the batcher would have put it
+	var names []string
+	for _, pattern := range []string{"*.seg", "*.ovf"} {
+		m, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(m)
+		names = append(names, m...)
+	}
+	for _, name := range names {
+		n, err := countRecords(name)
+		if err != nil {
+			return nil, fmt.Errorf("kafka spool %s: %w", name, err)
+		}
+		s.counts[name] = n
+	}
+	s.closed = names
+	return s, nil
+}
+
+// segmentName returns a new segment path; names sort by creation time.
+func (s *spool) segmentName(ext string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", time.Now().UnixNano(), ext))
+}
+
+// pending reports whether anything is waiting on disk for replay. While it
+// is, new messages are spooled too so the topic keeps its order.
+func (s *spool) pending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cur != nil || len(s.closed) > 0
+}
+
+func (s *spool) size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int64
+	for _, c := range s.counts {
+		n += c
+	}
+	return n
+}
+
+func (s *spool) append(msgs []kafka.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cur == nil {
+		f, err := os.OpenFile(s.segmentName(".seg"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+		s.cur, s.w = f, bufio.NewWriter(f)
+	}
+	return s.write(s.cur, s.w, msgs)
+}
+
+// overflowing reports whether the overflow segment is open: Publish must
+// then spill every message to keep them behind it.
+func (s *spool) overflowing() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.over != nil
+}
+
+// spill appends to the overflow segment, opening it if needed.
+func (s *spool) spill(m kafka.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.over == nil {
+		f, err := os.OpenFile(s.segmentName(".ovf"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+		s.over, s.ow = f, bufio.NewWriter(f)
+	}
+	return s.write(s.over, s.ow, []kafka.Message{m})
+}
+
+// adopt queues the overflow segment for replay behind everything spooled
+// so far.
+func (s *spool) adopt() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.over == nil {
+		return nil
+	}
+	s.rotate()
+	s.ow.Flush()
+	err := s.over.Close()
+	name := s.over.Name()
+	s.over, s.ow = nil, nil
+	// renamed so that, after a restart, it still sorts after older segments
+	seg := s.segmentName(".seg")
+	if rerr := os.Rename(name, seg); rerr == nil {
+		s.counts[seg] = s.counts[name]
+		delete(s.counts, name)
+		name = seg
+	} else if err == nil {
+		err = rerr
+	}
+	s.closed = append(s.closed, name)
+	return err
+}
+
+// write appends msgs to f through w; s.mu must be held.
+func (s *spool) write(f *os.File, w *bufio.Writer, msgs []kafka.Message) error {
+	var hdr [8]byte
+	for _, m := range msgs {
+		binary.BigEndian.PutUint32(hdr[:4], uint32(len(m.Key)))
+		w.Write(hdr[:4])
+		w.Write(m.Key)
+		binary.BigEndian.PutUint64(hdr[:], uint64(m.Time.UnixNano()))
+		w.Write(hdr[:])
+		binary.BigEndian.PutUint32(hdr[:4], uint32(len(m.Value)))
+		w.Write(hdr[:4])
+		if _, err := w.Write(m.Value); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	s.counts[f.Name()] += int64(len(msgs))
+	return nil
+}
+
+// rotate closes the current segment and queues it; s.mu must be held.
+func (s *spool) rotate() {
+	if s.cur == nil {
+		return
+	}
+	s.w.Flush()
+	s.cur.Close()
+	s.closed = append(s.closed, s.cur.Name())
+	s.cur, s.w = nil, nil
+}
+
+// next returns the oldest closed segment, closing the current one once
+// no older segment is left, or "" when the spool is empty.
+func (s *spool) next() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.closed) == 0 {
+		s.rotate()
+	}
+	if len(s.closed) == 0 {
+		return ""
+	}
+	return s.closed[0]
+}
+
+// done removes a segment after it was delivered or found unreadable.
+func (s *spool) done(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.closed) > 0 && s.closed[0] == name {
+		s.closed = s.closed[1:]
+	}
+	delete(s.counts, name)
+	return os.Remove(name)
+}
+
+// close closes the open segments; an overflow segment is queued behind the
+// others, which is where the batcher would have put it.
+func (s *spool) close() error {
+	err := s.adopt()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rotate()
+	return err
+}
+
+// readSegment loads every record of a segment. A record truncated by a
+// crash mid-write ends the segment.
+func readSegment(name string) ([]kafka.Message, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	var out []kafka.Message
+	for {
+		m, err := readRecord(r)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+}
+
+func readRecord(r io.Reader) (kafka.Message, error) {
+	var hdr [8]byte
+	if _, err := io.ReadFull(r, hdr[:4]); err != nil {
+		return kafka.Message{}, err
+	}
+	key := make([]byte, binary.BigEndian.Uint32(hdr[:4]))
+	if _, err := io.ReadFull(r, key); err != nil {
+		return kafka.Message{}, unexpected(err)
+	}
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return kafka.Message{}, unexpected(err)
+	}
+	ts := time.Unix(0, int64(binary.BigEndian.Uint64(hdr[:])))
+	if _, err := io.ReadFull(r, hdr[:4]); err != nil {
+		return kafka.Message{}, unexpected(err)
+	}
+	val := make([]byte, binary.BigEndian.Uint32(hdr[:4]))
+	if _, err := io.ReadFull(r, val); err != nil {
+		return kafka.Message{}, unexpected(err)
+	}
+	return kafka.Message{Key: key, Value: val, Time: ts}, nil
+}
+
+func unexpected(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func countRecords(name string) (int64, error) {
+	msgs, err := readSegment(name)
+	return int64(len(msgs)), err
+}
+
+// spoolDir keeps per-topic segments apart under a shared spool directory.
+func spoolDir(root, topic string) string {
+	return filepath.Join(root, strings.NewReplacer("/", "_", "..", "_").Replace(topic))
+}
@@ -0,0 +1,159 @@
+package kafka
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func msgs(keys ...string) []kafka.Message {
+	out := make([]kafka.Message, len(keys))
+	for i, k := range keys {
+		out[i] = kafka.Message{Key: []byte(k), Value: []byte("v" + k), Time: time.UnixMilli(int64(i))}
+	}
+	return out
+}
+
+// drainSpool replays s like the publisher does and returns the keys in order.
+func drainSpool(t *testing.T, s *spool) []string {
+	t.Helper()
+	var keys []string
+	for name := s.next(); name != ""; name = s.next() {
+		ms, err := readSegment(name)
+		if err != nil {
+			t.Fatalf("readSegment(%s): %v", name, err)
+		}
+		for _, m := range ms {
+			keys = append(keys, string(m.Key))
+		}
+		if err := s.done(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return keys
+}
+
+func TestSpool(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  func(t *testing.T, s *spool)
+		want []string
+	}{
+		{
+			name: "segments replay in order",
+			ops: func(t *testing.T, s *spool) {
+				s.append(msgs("a", "b"))
+				if name := s.next(); name == "" {
+					t.Fatal("next: no segment")
+				}
+				s.append(msgs("c"))
+			},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "overflow waits for adopt and goes last",
+			ops: func(t *testing.T, s *spool) {
+				s.spill(msgs("c")[0])
+				s.spill(msgs("d")[0])
+				if !s.overflowing() {
+					t.Fatal("not overflowing after spill")
+				}
+				if s.pending() {
+					t.Fatal("overflow must not be pending before adopt")
+				}
+				s.append(msgs("a", "b")) // older messages flushed by the batcher
+				if err := s.adopt(); err != nil {
+					t.Fatal(err)
+				}
+				s.append(msgs("e"))
+			},
+			want: []string{"a", "b", "c", "d", "e"},
+		},
+		{
+			name: "close queues the overflow behind the current segment",
+			ops: func(t *testing.T, s *spool) {
+				s.spill(msgs("b")[0])
+				s.append(msgs("a"))
+				if err := s.close(); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := openSpool(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.ops(t, s)
+			if got := s.size(); got != int64(len(tt.want)) {
+				t.Errorf("size = %d, want %d", got, len(tt.want))
+			}
+			got := drainSpool(t, s)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("replayed %v, want %v", got, tt.want)
+			}
+			if n := s.size(); n != 0 {
+				t.Errorf("size after replay = %d, want 0", n)
+			}
+		})
+	}
+}
+
+func TestSpoolReopen(t *testing.T) {
+	dir := t.TempDir()
+	s, err := openSpool(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.spill(msgs("c")[0]) // a crash before adopt leaves an .ovf segment
+	s.append(msgs("a", "b"))
+	s.w.Flush()
+	s.ow.Flush()
+	// a record cut short by the crash
+	f, _ := os.OpenFile(s.cur.Name(), os.O_WRONLY|os.O_APPEND, 0)
+	f.Write([]byte{0, 0, 0, 9, 'x'})
+	f.Close()
+
+	s2, err := openSpool(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := s2.size(); n != 3 {
+		t.Errorf("size = %d, want 3", n)
+	}
+	if got := drainSpool(t, s2); fmt.Sprint(got) != "[a b c]" {
+		t.Errorf("replayed %v, want [a b c]", got)
+	}
+}
+
+func TestSpoolUnreadableSegment(t *testing.T) {
+	s, err := openSpool(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.append(msgs("a", "b"))
+	name := s.next()
+	s.append(msgs("c"))
+	// the replayer discards segments it cannot read
+	if err := os.Chmod(name, 0); err == nil && os.Getuid() != 0 {
+		if _, err := readSegment(name); err == nil {
+			t.Fatal("readSegment of an unreadable file succeeded")
+		}
+	}
+	if err := s.done(name); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.size(); n != 1 {
+		t.Errorf("size = %d, want 1", n)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(name), filepath.Base(name))); !os.IsNotExist(err) {
+		t.Errorf("discarded segment still on disk: %v", err)
+	}
+}
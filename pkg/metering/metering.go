@@ -0,0 +1,346 @@
+// path: pkg/metering/metering.go
+package metering
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Action is what happens to a client's streams once a quota is exceeded.
+type Action string
+
+const (
+	Degrade   Action = "degrade"   // final candles only; other streams at most one message per second
+	Terminate Action = "terminate" // streams end with RESOURCE_EXHAUSTED and new ones are refused
+)
+
+// Quota limits a client's daily (UTC) consumption. Zero limits are unlimited.
+type Quota struct {
+	MessagesPerDay      uint64
+	BytesPerDay         uint64
+	StreamSecondsPerDay float64
+	OnExceed            Action // Degrade if empty
+}
+
+func (q *Quota) exceeded(u *Usage) bool {
+	return q != nil && ((q.MessagesPerDay > 0 && u.Messages >= q.MessagesPerDay) ||
+		(q.BytesPerDay > 0 && u.Bytes >= q.BytesPerDay) ||
+		(q.StreamSecondsPerDay > 0 && u.StreamSeconds >= q.StreamSecondsPerDay))
+}
+
+// Key identifies one usage row.
+type Key struct {
+	Day    string `json:"day"` // YYYY-MM-DD, UTC
+	Client string `json:"client"`
+	Symbol string `json:"symbol"` // "" for calls without a symbol
+}
+
+// Usage is what a client consumed.
+type Usage struct {
+	Subscriptions uint64  `json:"subscriptions"`
+	Messages      uint64  `json:"messages"`
+	Bytes         uint64  `json:"bytes"`
+	StreamSeconds float64 `json:"stream_seconds"`
+}
+
+func (u *Usage) add(o Usage) {
+	u.Subscriptions += o.Subscriptions
+	u.Messages += o.Messages
+	u.Bytes += o.Bytes
+	u.StreamSeconds += o.StreamSeconds
+}
+
+// Row is a usage row in reports and the persisted file.
+type Row struct {
+	Key
+	Usage
+}
+
+func day(t time.Time) string { return t.UTC().Format(time.DateOnly) }
+
+// DefaultRetention is how many UTC days of usage a Meter keeps.
+const DefaultRetention = 90
+
+// Option configures a Meter.
+type Option func(*Meter)
+
+// WithRetention keeps usage for the last days UTC days, today included
+// (DefaultRetention by default). Older rows are dropped from memory and,
+// on the next save, from the file. Zero keeps everything.
+func WithRetention(days int) Option {
+	return func(m *Meter) { m.retention = days }
+}
+
+// Meter counts usage per UTC day, client and symbol.
+type Meter struct {
+	path      string
+	retention int
+
+	mu      sync.Mutex
+	rows    map[Key]*Usage
+	daily   map[Key]*Usage // per day and client, for quota checks
+	streams map[*Stream]struct{}
+	dirty   bool
+}
+
+// New loads usage persisted at path; "" keeps usage in memory only.
+func New(path string, opts ...Option) (*Meter, error) {
+	m := &Meter{
+		path:      path,
+		retention: DefaultRetention,
+		rows:      make(map[Key]*Usage),
+		daily:     make(map[Key]*Usage),
+		streams:   make(map[*Stream]struct{}),
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	if path == "" {
+		return m, nil
+	}
+	rows, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		m.addLocked(r.Key, r.Usage)
+	}
+	m.dirty = false
+	m.pruneLocked(time.Now())
+	return m, nil
+}
+
+// pruneLocked drops rows older than the retention window, and the daily
+// totals of past days, which quotas no longer need; m.mu must be held.
+func (m *Meter) pruneLocked(now time.Time) {
+	today := day(now)
+	for k := range m.daily {
+		if k.Day < today {
+			delete(m.daily, k)
+		}
+	}
+	if m.retention <= 0 {
+		return
+	}
+	oldest := day(now.AddDate(0, 0, 1-m.retention))
+	for k := range m.rows {
+		if k.Day < oldest {
+			delete(m.rows, k)
+			m.dirty = true
+		}
+	}
+}
+
+// addLocked must be called with m.mu held.
+func (m *Meter) addLocked(k Key, u Usage) {
+	r := m.rows[k]
+	if r == nil {
+		r = &Usage{}
+		m.rows[k] = r
+	}
+	r.add(u)
+	dk := Key{Day: k.Day, Client: k.Client}
+	d := m.daily[dk]
+	if d == nil {
+		d = &Usage{}
+		m.daily[dk] = d
+	}
+	d.add(u)
+	m.dirty = true
+}
+
+// Record counts one unary response.
+func (m *Meter) Record(client, symbol string, bytes int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.addLocked(Key{day(time.Now()), client, symbol}, Usage{Messages: 1, Bytes: uint64(bytes)})
+}
+
+// Exceeded reports whether client is over q today.
+func (m *Meter) Exceeded(client string, q *Quota) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.exceededLocked(client, q)
+}
+
+func (m *Meter) exceededLocked(client string, q *Quota) bool {
+	d := m.daily[Key{Day: day(time.Now()), Client: client}]
+	return d != nil && q.exceeded(d)
+}
+
+// Verdict tells a stream what to do with a message.
+type Verdict int
+
+const (
+	Send Verdict = iota
+	Drop         // degraded: skip this message
+	Stop         // terminated: end the stream
+)
+
+// Stream meters one subscription.
+type Stream struct {
+	m      *Meter
+	key    Key // Day is filled in on use
+	quota  *Quota
+	last   time.Time // stream time accounted up to here
+	sent   time.Time // last message let through while degraded
+	closed bool
+}
+
+// Open starts metering a subscription of client to symbol under quota
+// (nil = none).
+func (m *Meter) Open(client, symbol string, quota *Quota) *Stream {
+	now := time.Now()
+	s := &Stream{m: m, key: Key{Client: client, Symbol: symbol}, quota: quota, last: now}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.streams[s] = struct{}{}
+	m.addLocked(s.at(now), Usage{Subscriptions: 1})
+	return s
+}
+
+func (s *Stream) at(t time.Time) Key {
+	k := s.key
+	k.Day = day(t)
+	return k
+}
+
+// accrueLocked books stream time up to now; m.mu must be held.
+func (s *Stream) accrueLocked(now time.Time) {
+	if d := now.Sub(s.last); d > 0 {
+		s.m.addLocked(s.at(now), Usage{StreamSeconds: d.Seconds()})
+	}
+	s.last = now
+}
+
+// Send decides whether a message of the given size goes out and counts it
+// if so. partial marks a candle update that is not final.
+func (s *Stream) Send(bytes int, partial bool) Verdict {
+	m := s.m
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s.accrueLocked(now)
+	if m.exceededLocked(s.key.Client, s.quota) {
+		if s.quota.OnExceed == Terminate {
+			return Stop
+		}
+		if partial || now.Sub(s.sent) < time.Second {
+			return Drop
+		}
+	}
+	s.sent = now
+	m.addLocked(s.at(now), Usage{Messages: 1, Bytes: uint64(bytes)})
+	return Send
+}
+
+// Close books the remaining stream time.
+func (s *Stream) Close() {
+	m := s.m
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.accrueLocked(time.Now())
+	delete(m.streams, s)
+}
+
+// Filter selects report rows; empty fields match everything and days are
+// inclusive.
+type Filter struct {
+	Client, Symbol string
+	FromDay, ToDay string
+}
+
+// Report returns the matching rows sorted by day, client and symbol.
+func (m *Meter) Report(f Filter) []Row {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accrueAllLocked()
+	var out []Row
+	for k, u := range m.rows {
+		if (f.Client != "" && k.Client != f.Client) || (f.Symbol != "" && k.Symbol != f.Symbol) ||
+			(f.FromDay != "" && k.Day < f.FromDay) || (f.ToDay != "" && k.Day > f.ToDay) {
+			continue
+		}
+		out = append(out, Row{Key: k, Usage: *u})
+	}
+	sortRows(out)
+	return out
+}
+
+func sortRows(rows []Row) {
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i].Key, rows[j].Key
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Client != b.Client {
+			return a.Client < b.Client
+		}
+		return a.Symbol < b.Symbol
+	})
+}
+
+func (m *Meter) accrueAllLocked() {
+	now := time.Now()
+	for s := range m.streams {
+		s.accrueLocked(now)
+	}
+}
+
+// Run books the time of open streams, drops usage past the retention
+// window and saves usage every interval, and once more when ctx is done.
+func (m *Meter) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := m.Save(); err != nil {
+				log.Printf("metering: %v", err)
+			}
+			return
+		case <-ticker.C:
+			if err := m.Save(); err != nil {
+				log.Printf("metering: %v", err)
+			}
+		}
+	}
+}
+
+// Save drops usage past the retention window and writes usage to the file
+// if anything changed since the last save.
+func (m *Meter) Save() error {
+	m.mu.Lock()
+	m.accrueAllLocked()
+	m.pruneLocked(time.Now())
+	if m.path == "" || !m.dirty {
+		m.mu.Unlock()
+		return nil
+	}
+	rows := make([]Row, 0, len(m.rows))
+	for k, u := range m.rows {
+		rows = append(rows, Row{Key: k, Usage: *u})
+	}
+	m.dirty = false
+	m.mu.Unlock()
+
+	sortRows(rows)
+	data, err := json.MarshalIndent(rows, "", "  ")
+	if err == nil {
+		err = save(m.path, data)
+	}
+	if err != nil {
+		m.mu.Lock()
+		m.dirty = true // retry on the next save
+		m.mu.Unlock()
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package metering
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRetention(t *testing.T) {
+	now := time.Now()
+	ago := func(days int) string { return day(now.AddDate(0, 0, -days)) }
+	path := filepath.Join(t.TempDir(), "usage.json")
+	var saved []Row
+	for _, d := range []int{0, 1, 2, 30} {
+		saved = append(saved, Row{Key: Key{Day: ago(d), Client: "c"}, Usage: Usage{Messages: 1}})
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{"default", nil, []string{ago(30), ago(2), ago(1), ago(0)}},
+		{"two days", []Option{WithRetention(2)}, []string{ago(1), ago(0)}},
+		{"forever", []Option{WithRetention(0)}, []string{ago(30), ago(2), ago(1), ago(0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := New(path, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var days []string
+			for _, r := range m.Report(Filter{}) {
+				days = append(days, r.Day)
+			}
+			if !slices.Equal(days, tt.want) {
+				t.Errorf("days %v, want %v", days, tt.want)
+			}
+			if len(m.daily) != 1 {
+				t.Errorf("%d daily totals, want today's only", len(m.daily))
+			}
+		})
+	}
+
+	// the pruned rows leave the file on the next save
+	m, err := New(path, WithRetention(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Errorf("%d rows saved, want 2", len(rows))
+	}
+}
+
+func TestQuota(t *testing.T) {
+	m, err := New("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := &Quota{MessagesPerDay: 2, OnExceed: Terminate}
+	s := m.Open("c", "X", q)
+	defer s.Close()
+	for i, want := range []Verdict{Send, Send, Stop} {
+		if got := s.Send(10, false); got != want {
+			t.Fatalf("message %d: verdict %v, want %v", i, got, want)
+		}
+	}
+	if !m.Exceeded("c", q) || m.Exceeded("other", q) {
+		t.Error("quota applies to the wrong client")
+	}
+
+	degraded := m.Open("c", "X", &Quota{MessagesPerDay: 2})
+	defer degraded.Close()
+	if got := degraded.Send(10, true); got != Drop {
+		t.Errorf("partial candle while degraded: %v, want Drop", got)
+	}
+	if got := degraded.Send(10, false); got != Send {
+		t.Errorf("final candle while degraded: %v, want Send", got)
+	}
+	if got := degraded.Send(10, false); got != Drop {
+		t.Errorf("second message within a second while degraded: %v, want Drop", got)
+	}
+}
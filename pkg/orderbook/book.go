@@ -0,0 +1,196 @@
+// path: pkg/orderbook/book.go
+package orderbook
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Level is one price level of a book side.
+type Level struct {
+	Price float64
+	Qty   float64
+}
+
+// Snapshot is an immutable, sorted copy of a book: bids descending,
+// asks ascending.
+type Snapshot struct {
+	Symbol       string
+	Exchange     string
+	LastUpdateID uint64
+	TS           time.Time
+	Bids         []Level
+	Asks         []Level
+}
+
+// BestBid returns the top bid, or a zero Level when the side is empty.
+func (s Snapshot) BestBid() Level {
+	if len(s.Bids) == 0 {
+		return Level{}
+	}
+	return s.Bids[0]
+}
+
+// BestAsk returns the top ask, or a zero Level when the side is empty.
+func (s Snapshot) BestAsk() Level {
+	if len(s.Asks) == 0 {
+		return Level{}
+	}
+	return s.Asks[0]
+}
+
+// Spread is best ask minus best bid, or 0 when either side is empty.
+func (s Snapshot) Spread() float64 {
+	if len(s.Bids) == 0 || len(s.Asks) == 0 {
+		return 0
+	}
+	return s.Asks[0].Price - s.Bids[0].Price
+}
+
+// Truncate returns s limited to depth levels per side (0 = unlimited).
+func (s Snapshot) Truncate(depth int) Snapshot {
+	if depth > 0 {
+		if len(s.Bids) > depth {
+			s.Bids = s.Bids[:depth]
+		}
+		if len(s.Asks) > depth {
+			s.Asks = s.Asks[:depth]
+		}
+	}
+	return s
+}
+
+// ErrGap is returned by Apply when an update does not continue the book's
+// sequence; the book must be resynchronised from a fresh snapshot.
+type ErrGap struct {
+	Expected, Got uint64
+}
+
+func (e ErrGap) Error() string {
+	return fmt.Sprintf("orderbook: sequence gap: expected first update id %d, got %d", e.Expected, e.Got)
+}
+
+// Book is a local L2 order book maintained from a REST snapshot plus
+// incremental diffs, following the usual venue sync protocol:
+// updates entirely before the snapshot are ignored, the first applied
+// update must straddle it and every later one must continue the sequence.
+// Book is not safe for concurrent use.
+type Book struct {
+	symbol, exchange string
+	bids, asks       map[float64]float64
+	lastUpdateID     uint64
+	synced           bool // first post-snapshot update applied
+	ts               time.Time
+}
+
+func New(exchange, symbol string) *Book {
+	return &Book{
+		symbol:   symbol,
+		exchange: exchange,
+		bids:     make(map[float64]float64),
+		asks:     make(map[float64]float64),
+	}
+}
+
+func (b *Book) LastUpdateID() uint64 { return b.lastUpdateID }
+
+// Reset replaces the book with a snapshot taken at lastUpdateID.
+func (b *Book) Reset(lastUpdateID uint64, bids, asks []Level) {
+	b.bids = make(map[float64]float64, len(bids))
+	b.asks = make(map[float64]float64, len(asks))
+	for _, l := range bids {
+		if l.Qty > 0 {
+			b.bids[l.Price] = l.Qty
+		}
+	}
+	for _, l := range asks {
+		if l.Qty > 0 {
+			b.asks[l.Price] = l.Qty
+		}
+	}
+	b.lastUpdateID = lastUpdateID
+	b.synced = false
+	b.ts = time.Now()
+}
+
+// Apply merges a diff covering update ids [first, last]. Quantities are
+// absolute; zero removes the level. It reports whether the diff changed
+// the book (stale diffs are skipped) or ErrGap on a sequence break.
+func (b *Book) Apply(first, last uint64, bids, asks []Level, ts time.Time) (bool, error) {
+	if last <= b.lastUpdateID {
+		return false, nil
+	}
+	next := b.lastUpdateID + 1
+	if b.synced && first != next {
+		return false, ErrGap{Expected: next, Got: first}
+	}
+	if !b.synced && first > next {
+		return false, ErrGap{Expected: next, Got: first}
+	}
+	for _, l := range bids {
+		set(b.bids, l)
+	}
+	for _, l := range asks {
+		set(b.asks, l)
+	}
+	b.lastUpdateID = last
+	b.synced = true
+	b.ts = ts
+	return true, nil
+}
+
+func set(side map[float64]float64, l Level) {
+	if l.Qty == 0 {
+		delete(side, l.Price)
+		return
+	}
+	side[l.Price] = l.Qty
+}
+
+// Snapshot returns the book's top depth levels per side (0 = all).
+func (b *Book) Snapshot(depth int) Snapshot {
+	return Snapshot{
+		Symbol:       b.symbol,
+		Exchange:     b.exchange,
+		LastUpdateID: b.lastUpdateID,
+		TS:           b.ts,
+		Bids:         sorted(b.bids, depth, true),
+		Asks:         sorted(b.asks, depth, false),
+	}
+}
+
+func sorted(side map[float64]float64, depth int, desc bool) []Level {
+	out := make([]Level, 0, len(side))
+	for p, q := range side {
+		out = append(out, Level{Price: p, Qty: q})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if desc {
+			return out[i].Price > out[j].Price
+		}
+		return out[i].Price < out[j].Price
+	})
+	if depth > 0 && len(out) > depth {
+		out = out[:depth]
+	}
+	return out
+}
+
+// ParseLevels converts venue [["price","qty"], ...] pairs.
+func ParseLevels(raw [][2]string) ([]Level, error) {
+	out := make([]Level, 0, len(raw))
+	for _, r := range raw {
+		p, err := strconv.ParseFloat(r[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("orderbook: price %q: %w", r[0], err)
+		}
+		q, err := strconv.ParseFloat(r[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("orderbook: qty %q: %w", r[1], err)
+		}
+		out = append(out, Level{Price: p, Qty: q})
+	}
+	return out, nil
+}
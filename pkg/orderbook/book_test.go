@@ -0,0 +1,133 @@
+package orderbook
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBookApply(t *testing.T) {
+	type diff struct {
+		first, last uint64
+		bids, asks  []Level
+	}
+	snapBids := []Level{{100, 1}, {99, 2}}
+	snapAsks := []Level{{101, 1}, {102, 2}}
+	tests := []struct {
+		name    string
+		diffs   []diff // applied in order after Reset at id 10; only the last may fail
+		changed bool
+		gap     *ErrGap
+		lastID  uint64
+		bids    []Level
+		asks    []Level
+	}{
+		{
+			name:    "diff entirely before the snapshot is skipped",
+			diffs:   []diff{{5, 10, []Level{{100, 9}}, nil}},
+			changed: false,
+			lastID:  10,
+			bids:    snapBids,
+			asks:    snapAsks,
+		},
+		{
+			name:    "first diff straddles the snapshot",
+			diffs:   []diff{{8, 12, []Level{{100, 3}}, []Level{{101, 0}}}},
+			changed: true,
+			lastID:  12,
+			bids:    []Level{{100, 3}, {99, 2}},
+			asks:    []Level{{102, 2}},
+		},
+		{
+			name:   "first diff after the snapshot is a gap",
+			diffs:  []diff{{12, 13, []Level{{100, 3}}, nil}},
+			gap:    &ErrGap{Expected: 11, Got: 12},
+			lastID: 10,
+			bids:   snapBids,
+			asks:   snapAsks,
+		},
+		{
+			name: "contiguous diffs add and remove levels",
+			diffs: []diff{
+				{11, 11, []Level{{98, 5}}, nil},
+				{12, 14, []Level{{99, 0}}, []Level{{101.5, 4}}},
+			},
+			changed: true,
+			lastID:  14,
+			bids:    []Level{{100, 1}, {98, 5}},
+			asks:    []Level{{101, 1}, {101.5, 4}, {102, 2}},
+		},
+		{
+			name: "break in the sequence once synced",
+			diffs: []diff{
+				{11, 11, []Level{{98, 5}}, nil},
+				{13, 14, []Level{{97, 1}}, nil},
+			},
+			gap:    &ErrGap{Expected: 12, Got: 13},
+			lastID: 11,
+			bids:   []Level{{100, 1}, {99, 2}, {98, 5}},
+			asks:   snapAsks,
+		},
+		{
+			name: "overlap once synced is a gap",
+			diffs: []diff{
+				{11, 12, nil, nil},
+				{12, 13, nil, nil},
+			},
+			gap:    &ErrGap{Expected: 13, Got: 12},
+			lastID: 12,
+			bids:   snapBids,
+			asks:   snapAsks,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New("binance", "BTCUSDT")
+			b.Reset(10, append([]Level{{98.5, 0}}, snapBids...), snapAsks)
+			var changed bool
+			var err error
+			for i, d := range tt.diffs {
+				changed, err = b.Apply(d.first, d.last, d.bids, d.asks, time.UnixMilli(int64(d.last)))
+				if err != nil && i < len(tt.diffs)-1 {
+					t.Fatalf("diff %d: %v", i, err)
+				}
+			}
+			var gap ErrGap
+			switch {
+			case tt.gap == nil && err != nil:
+				t.Fatalf("Apply: %v", err)
+			case tt.gap != nil && (!errors.As(err, &gap) || gap != *tt.gap):
+				t.Fatalf("Apply error = %v, want %v", err, *tt.gap)
+			}
+			if changed != tt.changed {
+				t.Errorf("changed = %v, want %v", changed, tt.changed)
+			}
+			s := b.Snapshot(0)
+			if s.LastUpdateID != tt.lastID {
+				t.Errorf("LastUpdateID = %d, want %d", s.LastUpdateID, tt.lastID)
+			}
+			if !slices.Equal(s.Bids, tt.bids) {
+				t.Errorf("bids = %v, want %v", s.Bids, tt.bids)
+			}
+			if !slices.Equal(s.Asks, tt.asks) {
+				t.Errorf("asks = %v, want %v", s.Asks, tt.asks)
+			}
+		})
+	}
+}
+
+func TestSnapshotTruncate(t *testing.T) {
+	b := New("binance", "BTCUSDT")
+	b.Reset(1, []Level{{99, 1}, {100, 1}, {98, 1}}, []Level{{102, 1}, {101, 1}})
+	s := b.Snapshot(2)
+	if want := []Level{{100, 1}, {99, 1}}; !slices.Equal(s.Bids, want) {
+		t.Errorf("bids = %v, want %v", s.Bids, want)
+	}
+	if want := []Level{{101, 1}, {102, 1}}; !slices.Equal(s.Asks, want) {
+		t.Errorf("asks = %v, want %v", s.Asks, want)
+	}
+	if got := s.Spread(); got != 1 {
+		t.Errorf("Spread = %v, want 1", got)
+	}
+}
@@ -0,0 +1,214 @@
+// path: pkg/profile/profile.go
+package profile
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/common"
+	pricev1 "github.com/binaridigital/price-engine/proto/price/v1"
+)
+
+// DefaultValueArea is the share of window volume the value area covers.
+const DefaultValueArea = 0.70
+
+// Config controls the price bucketing.
+type Config struct {
+	Interval  time.Duration
+	TickSizes map[string]float64 // per symbol; symbols without one use AutoTick
+	ValueArea float64            // 0 = DefaultValueArea
+}
+
+// Validate checks that Interval is a positive whole number of
+// milliseconds, the resolution of profile windows.
+func (c Config) Validate() error {
+	if c.Interval < time.Millisecond || c.Interval%time.Millisecond != 0 {
+		return fmt.Errorf("profile: interval %v must be a positive whole number of milliseconds", c.Interval)
+	}
+	return nil
+}
+
+// AutoTick picks a tick of roughly 1/1000 of the price as a power of ten,
+// e.g. 100 for BTCUSDT at 100k and 0.001 for EURUSD at 1.08.
+func AutoTick(price float64) float64 {
+	if price <= 0 {
+		return 0
+	}
+	return math.Pow(10, math.Floor(math.Log10(price))-3)
+}
+
+type level struct {
+	vol, buyVol, sellVol float64
+	count                uint64
+}
+
+type window struct {
+	startMs, endMs int64
+	tick           float64
+	levels         map[int64]*level // price / tick, rounded down
+	total          float64
+}
+
+// Run buckets traded volume by price level per window and emits one final
+// VolumeProfile per symbol and window: volume at price split by aggressor
+// side (footprint), point of control and value area. It panics if cfg
+// fails Validate.
+func Run(ctx context.Context, trades <-chan common.Trade, cfg Config) <-chan *pricev1.VolumeProfile {
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
+	if cfg.ValueArea <= 0 || cfg.ValueArea > 1 {
+		cfg.ValueArea = DefaultValueArea
+	}
+	out := make(chan *pricev1.VolumeProfile, 256)
+	windows := make(map[string]*window)
+	var mu sync.Mutex
+
+	flush := func(sym string, w *window) {
+		p := build(sym, w, cfg.ValueArea)
+		select {
+		case out <- p:
+		case <-ctx.Done():
+		}
+	}
+
+	ticker := time.NewTicker(cfg.Interval / 2)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				cutoff := now.Add(-10 * time.Millisecond).UnixMilli()
+				mu.Lock()
+				for sym, w := range windows {
+					if w.endMs <= cutoff {
+						flush(sym, w)
+						delete(windows, sym)
+					}
+				}
+				mu.Unlock()
+			}
+		}
+	}()
+
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case t, ok := <-trades:
+				if !ok {
+					return
+				}
+				if t.Price <= 0 || t.Qty <= 0 || t.Synthetic {
+					continue
+				}
+				start := t.TS.Truncate(cfg.Interval)
+				mu.Lock()
+				w := windows[t.Symbol]
+				if w == nil || w.startMs != start.UnixMilli() {
+					if w != nil {
+						flush(t.Symbol, w)
+					}
+					tick := cfg.TickSizes[t.Symbol]
+					if tick <= 0 {
+						tick = AutoTick(t.Price)
+					}
+					w = &window{
+						startMs: start.UnixMilli(),
+						endMs:   start.Add(cfg.Interval).UnixMilli(),
+						tick:    tick,
+						levels:  make(map[int64]*level),
+					}
+					windows[t.Symbol] = w
+				}
+				k := int64(math.Floor(t.Price/w.tick + 1e-9))
+				l := w.levels[k]
+				if l == nil {
+					l = &level{}
+					w.levels[k] = l
+				}
+				l.vol += t.Qty
+				l.count++
+				switch t.Side {
+				case common.SideBuy:
+					l.buyVol += t.Qty
+				case common.SideSell:
+					l.sellVol += t.Qty
+				}
+				w.total += t.Qty
+				mu.Unlock()
+			}
+		}
+	}()
+
+	return out
+}
+
+func build(sym string, w *window, valueArea float64) *pricev1.VolumeProfile {
+	keys := make([]int64, 0, len(w.levels))
+	for k := range w.levels {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	p := &pricev1.VolumeProfile{
+		Symbol:        sym,
+		WindowStartMs: w.startMs,
+		WindowEndMs:   w.endMs,
+		TickSize:      w.tick,
+		TotalVolume:   w.total,
+		IsFinal:       true,
+		Levels:        make([]*pricev1.VolumeAtPrice, len(keys)),
+	}
+	poc := 0
+	for i, k := range keys {
+		l := w.levels[k]
+		p.Levels[i] = &pricev1.VolumeAtPrice{
+			Price:      float64(k) * w.tick,
+			Volume:     l.vol,
+			BuyVolume:  l.buyVol,
+			SellVolume: l.sellVol,
+			Delta:      l.buyVol - l.sellVol,
+			TradeCount: l.count,
+		}
+		if l.vol > w.levels[keys[poc]].vol {
+			poc = i
+		}
+	}
+	if len(keys) == 0 {
+		return p
+	}
+	p.PocPrice = p.Levels[poc].Price
+
+	// Value area: grow from the POC towards the heavier neighbour until the
+	// target share of volume is covered.
+	lo, hi := poc, poc
+	covered := p.Levels[poc].Volume
+	for covered < valueArea*w.total && (lo > 0 || hi < len(keys)-1) {
+		up, down := -1.0, -1.0
+		if hi < len(keys)-1 {
+			up = p.Levels[hi+1].Volume
+		}
+		if lo > 0 {
+			down = p.Levels[lo-1].Volume
+		}
+		if up >= down {
+			hi++
+			covered += up
+		} else {
+			lo--
+			covered += down
+		}
+	}
+	p.ValueAreaLow = p.Levels[lo].Price
+	p.ValueAreaHigh = p.Levels[hi].Price
+	return p
+}
@@ -0,0 +1,25 @@
+package profile
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		interval time.Duration
+		ok       bool
+	}{
+		{time.Minute, true},
+		{time.Millisecond, true},
+		{0, false},
+		{time.Nanosecond, false},
+		{-time.Minute, false},
+		{1500 * time.Microsecond, false},
+	}
+	for _, tt := range tests {
+		if err := (Config{Interval: tt.interval}).Validate(); (err == nil) != tt.ok {
+			t.Errorf("Validate(%v) = %v, want ok %v", tt.interval, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// path: pkg/synth/synth.go
+package synth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/common"
+)
+
+// Aliases maps assets that are priced as equivalent when triangulating,
+// so BTCEUR can be built from BTCUSDT and EURUSD.
+var Aliases = map[string]string{
+	"USDT":  "USD",
+	"USDC":  "USD",
+	"BUSD":  "USD",
+	"FDUSD": "USD",
+	"TUSD":  "USD",
+}
+
+// DefaultMaxLegAge is how old the slowest leg may be before a synthetic
+// price is no longer emitted.
+const DefaultMaxLegAge = time.Minute
+
+// Leg is one source instrument of a Route. Inverted legs contribute 1/price.
+type Leg struct {
+	Symbol   string
+	Inverted bool
+}
+
+// Route describes how a synthetic symbol is derived: price = Π leg prices.
+type Route struct {
+	Symbol string
+	Legs   []Leg
+}
+
+func (r Route) String() string {
+	parts := make([]string, len(r.Legs))
+	for i, l := range r.Legs {
+		parts[i] = l.Symbol
+		if l.Inverted {
+			parts[i] = "1/" + l.Symbol
+		}
+	}
+	return r.Symbol + " = " + strings.Join(parts, " * ")
+}
+
+func canon(asset string) string {
+	if a, ok := Aliases[asset]; ok {
+		return a
+	}
+	return asset
+}
+
+// Resolve finds a two-leg triangulation for target using the symbols that
+// are ingested live. Legs quoted in the target's direction are preferred
+// over inverted ones.
+func Resolve(target string, available []string) (Route, error) {
+	target = strings.ToUpper(strings.TrimSpace(target))
+	base, quote, ok := common.SplitSymbol(target)
+	if !ok {
+		return Route{}, fmt.Errorf("synth: cannot split %q into base/quote", target)
+	}
+	base, quote = canon(base), canon(quote)
+
+	type pair struct{ sym, base, quote string }
+	var pairs []pair
+	for _, s := range available {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if s == target {
+			return Route{}, fmt.Errorf("synth: %s is ingested directly", target)
+		}
+		b, q, ok := common.SplitSymbol(s)
+		if !ok {
+			continue
+		}
+		pairs = append(pairs, pair{s, canon(b), canon(q)})
+	}
+
+	// orient returns the leg converting `from` into `to`, if p connects them.
+	orient := func(p pair, from, to string) (Leg, bool) {
+		switch {
+		case p.base == from && p.quote == to:
+			return Leg{Symbol: p.sym}, true
+		case p.base == to && p.quote == from:
+			return Leg{Symbol: p.sym, Inverted: true}, true
+		}
+		return Leg{}, false
+	}
+
+	var best *Route
+	bestInv := 3
+	for _, p1 := range pairs {
+		// p1 links base with an intermediate asset x
+		var x string
+		switch base {
+		case p1.base:
+			x = p1.quote
+		case p1.quote:
+			x = p1.base
+		default:
+			continue
+		}
+		if x == quote {
+			// alias-equivalent of the target itself (e.g. BTCUSDT for BTCUSD)
+			continue
+		}
+		l1, _ := orient(p1, base, x)
+		for _, p2 := range pairs {
+			if p2.sym == p1.sym {
+				continue
+			}
+			l2, ok := orient(p2, x, quote)
+			if !ok {
+				continue
+			}
+			inv := 0
+			if l1.Inverted {
+				inv++
+			}
+			if l2.Inverted {
+				inv++
+			}
+			if inv < bestInv {
+				best = &Route{Symbol: target, Legs: []Leg{l1, l2}}
+				bestInv = inv
+			}
+		}
+	}
+	if best == nil {
+		return Route{}, fmt.Errorf("synth: no triangulation for %s from %v", target, available)
+	}
+	return *best, nil
+}
+
+// Run passes every input trade through and, whenever a leg of a route
+// updates, emits a synthetic trade for the route's symbol once all of its
+// legs have a price no older than maxLegAge.
+func Run(ctx context.Context, in <-chan common.Trade, routes []Route, maxLegAge time.Duration) <-chan common.Trade {
+	out := make(chan common.Trade, 2048)
+	byLeg := make(map[string][]int)
+	for i, r := range routes {
+		for _, l := range r.Legs {
+			byLeg[l.Symbol] = append(byLeg[l.Symbol], i)
+		}
+	}
+	last := make(map[string]common.Trade)
+
+	send := func(t common.Trade) bool {
+		select {
+		case out <- t:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case t, ok := <-in:
+				if !ok {
+					return
+				}
+				if !send(t) {
+					return
+				}
+				idx, ok := byLeg[t.Symbol]
+				if !ok || t.Synthetic || t.Price <= 0 {
+					continue
+				}
+				last[t.Symbol] = t
+				for _, i := range idx {
+					if st, ok := derive(routes[i], last, t.TS, maxLegAge); ok {
+						if !send(st) {
+							return
+						}
+					}
+				}
+			}
+		}
+	}()
+	return out
+}
+
+func derive(r Route, last map[string]common.Trade, now time.Time, maxLegAge time.Duration) (common.Trade, bool) {
+	price := 1.0
+	legs := make([]common.SourceLeg, 0, len(r.Legs))
+	for _, l := range r.Legs {
+		lt, ok := last[l.Symbol]
+		if !ok || (maxLegAge > 0 && now.Sub(lt.TS) > maxLegAge) {
+			return common.Trade{}, false
+		}
+		if l.Inverted {
+			price /= lt.Price
+		} else {
+			price *= lt.Price
+		}
+		legs = append(legs, common.SourceLeg{
+			Symbol:   lt.Symbol,
+			Exchange: lt.Exchange,
+			Price:    lt.Price,
+			Inverted: l.Inverted,
+			TS:       lt.TS,
+		})
+	}
+	return common.Trade{
+		Symbol:    r.Symbol,
+		Price:     price,
+		Qty:       1, // no traded volume behind a cross; weight ticks equally like FX quotes
+		Exchange:  "synthetic",
+		TS:        now,
+		Synthetic: true,
+		Legs:      legs,
+	}, true
+}
@@ -0,0 +1,102 @@
+package synth
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/binaridigital/price-engine/pkg/common"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		target    string
+		available []string
+		want      string // the route, or "" for an error
+	}{
+		{"BTCEUR", []string{"BTCUSDT", "EURUSD"}, "BTCEUR = BTCUSDT * 1/EURUSD"},
+		{" btceur ", []string{"btcusdt", "eurusd"}, "BTCEUR = BTCUSDT * 1/EURUSD"},
+		{"EURJPY", []string{"EURUSD", "USDJPY"}, "EURJPY = EURUSD * USDJPY"},
+		{"JPYGBP", []string{"USDJPY", "GBPUSD"}, "JPYGBP = 1/USDJPY * 1/GBPUSD"},
+		// legs in the target's direction win over inverted ones
+		{"BTCEUR", []string{"EURUSD", "BTCUSDT", "USDEUR"}, "BTCEUR = BTCUSDT * USDEUR"},
+		// stablecoins stand in for USD on either leg
+		{"ETHUSD", []string{"ETHBTC", "BTCUSDC"}, "ETHUSD = ETHBTC * BTCUSDC"},
+		{"SOLEUR", []string{"SOLUSDT", "EURUSDC"}, "SOLEUR = SOLUSDT * 1/EURUSDC"},
+		// an alias of the target is no triangulation
+		{"ETHUSD", []string{"ETHUSDT"}, ""},
+		{"BTCEUR", []string{"BTCEUR", "BTCUSDT", "EURUSD"}, ""},
+		{"BTCEUR", []string{"BTCUSDT", "ETHUSDT"}, ""},
+		{"FOO", []string{"BTCUSDT", "EURUSD"}, ""},
+	}
+	for _, tt := range tests {
+		r, err := Resolve(tt.target, tt.available)
+		switch {
+		case tt.want == "" && err == nil:
+			t.Errorf("Resolve(%q, %v) = %v, want an error", tt.target, tt.available, r)
+		case tt.want != "" && err != nil:
+			t.Errorf("Resolve(%q, %v): %v", tt.target, tt.available, err)
+		case tt.want != "" && r.String() != tt.want:
+			t.Errorf("Resolve(%q, %v) = %v, want %s", tt.target, tt.available, r, tt.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	t0 := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	trade := func(sym string, sec int, price float64) common.Trade {
+		return common.Trade{Symbol: sym, Exchange: "venue", TS: t0.Add(time.Duration(sec) * time.Second), Price: price, Qty: 2}
+	}
+	relayed := trade("BTCUSDT", 20, 1)
+	relayed.Synthetic = true
+	input := []common.Trade{
+		trade("BTCUSDT", 0, 60000), // no EURUSD yet
+		trade("EURUSD", 10, 1.2),
+		trade("BTCUSDT", 30, 66000),
+		trade("ETHUSDT", 40, 3000),  // not a leg
+		relayed,                     // synthetic trades are no legs
+		trade("BTCUSDT", 50, 0),     // neither are bad prints
+		trade("BTCUSDT", 80, 72000), // EURUSD is 70s old
+		trade("EURUSD", 90, 1.25),
+	}
+	route := Route{Symbol: "BTCEUR", Legs: []Leg{{Symbol: "BTCUSDT"}, {Symbol: "EURUSD", Inverted: true}}}
+
+	in := make(chan common.Trade, len(input))
+	for _, tr := range input {
+		in <- tr
+	}
+	close(in)
+	var passed, synthetic []common.Trade
+	for tr := range Run(context.Background(), in, []Route{route}, time.Minute) {
+		if tr.Symbol == "BTCEUR" {
+			synthetic = append(synthetic, tr)
+		} else {
+			passed = append(passed, tr)
+		}
+	}
+
+	if len(passed) != len(input) {
+		t.Errorf("%d trades passed through, want all %d", len(passed), len(input))
+	}
+	want := []struct {
+		sec   int
+		price float64
+	}{{10, 50000}, {30, 55000}, {90, 57600}}
+	if len(synthetic) != len(want) {
+		t.Fatalf("synthetic trades %v, want %d", synthetic, len(want))
+	}
+	for i, w := range want {
+		s := synthetic[i]
+		if !s.TS.Equal(t0.Add(time.Duration(w.sec)*time.Second)) || math.Abs(s.Price-w.price) > 1e-6 ||
+			!s.Synthetic || s.Exchange != "synthetic" || s.Qty != 1 {
+			t.Errorf("synthetic trade %d: %+v, want %v at %ds", i, s, w.price, w.sec)
+		}
+		if len(s.Legs) != 2 || s.Legs[0].Symbol != "BTCUSDT" || s.Legs[0].Inverted || s.Legs[1].Symbol != "EURUSD" || !s.Legs[1].Inverted {
+			t.Errorf("synthetic trade %d legs %+v", i, s.Legs)
+		}
+	}
+	if l := synthetic[2].Legs; l[0].Price != 72000 || !l[0].TS.Equal(t0.Add(80*time.Second)) || l[1].Price != 1.25 {
+		t.Errorf("last legs %+v, want the BTCUSDT trade at 80s and EURUSD at 90s", l)
+	}
+}
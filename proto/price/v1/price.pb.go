@@ -23,6 +23,58 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type BarType int32
+
+const (
+	BarType_BAR_TIME   BarType = 0 // fixed time windows (interval_ms)
+	BarType_BAR_TICK   BarType = 1 // closes every bar_size trades
+	BarType_BAR_VOLUME BarType = 2 // closes once bar_size quantity has traded
+	BarType_BAR_DOLLAR BarType = 3 // closes once bar_size notional (price x qty) has traded
+)
+
+// Enum value maps for BarType.
+var (
+	BarType_name = map[int32]string{
+		0: "BAR_TIME",
+		1: "BAR_TICK",
+		2: "BAR_VOLUME",
+		3: "BAR_DOLLAR",
+	}
+	BarType_value = map[string]int32{
+		"BAR_TIME":   0,
+		"BAR_TICK":   1,
+		"BAR_VOLUME": 2,
+		"BAR_DOLLAR": 3,
+	}
+)
+
+func (x BarType) Enum() *BarType {
+	p := new(BarType)
+	*p = x
+	return p
+}
+
+func (x BarType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BarType) Descriptor() protoreflect.EnumDescriptor {
+	return file_price_v1_price_proto_enumTypes[0].Descriptor()
+}
+
+func (BarType) Type() protoreflect.EnumType {
+	return &file_price_v1_price_proto_enumTypes[0]
+}
+
+func (x BarType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BarType.Descriptor instead.
+func (BarType) EnumDescriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{0}
+}
+
 type InstrumentType int32
 
 const (
@@ -56,11 +108,11 @@ func (x InstrumentType) String() string {
 }
 
 func (InstrumentType) Descriptor() protoreflect.EnumDescriptor {
-	return file_price_v1_price_proto_enumTypes[0].Descriptor()
+	return file_price_v1_price_proto_enumTypes[1].Descriptor()
 }
 
 func (InstrumentType) Type() protoreflect.EnumType {
-	return &file_price_v1_price_proto_enumTypes[0]
+	return &file_price_v1_price_proto_enumTypes[1]
 }
 
 func (x InstrumentType) Number() protoreflect.EnumNumber {
@@ -69,7 +121,7 @@ func (x InstrumentType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use InstrumentType.Descriptor instead.
 func (InstrumentType) EnumDescriptor() ([]byte, []int) {
-	return file_price_v1_price_proto_rawDescGZIP(), []int{0}
+	return file_price_v1_price_proto_rawDescGZIP(), []int{1}
 }
 
 type PriceType int32
@@ -111,11 +163,11 @@ func (x PriceType) String() string {
 }
 
 func (PriceType) Descriptor() protoreflect.EnumDescriptor {
-	return file_price_v1_price_proto_enumTypes[1].Descriptor()
+	return file_price_v1_price_proto_enumTypes[2].Descriptor()
 }
 
 func (PriceType) Type() protoreflect.EnumType {
-	return &file_price_v1_price_proto_enumTypes[1]
+	return &file_price_v1_price_proto_enumTypes[2]
 }
 
 func (x PriceType) Number() protoreflect.EnumNumber {
@@ -124,15 +176,254 @@ func (x PriceType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PriceType.Descriptor instead.
 func (PriceType) EnumDescriptor() ([]byte, []int) {
-	return file_price_v1_price_proto_rawDescGZIP(), []int{1}
+	return file_price_v1_price_proto_rawDescGZIP(), []int{2}
+}
+
+// Connector health (one entry per exchange+symbol stream)
+type ConnectorState int32
+
+const (
+	ConnectorState_CS_UNSPECIFIED ConnectorState = 0
+	ConnectorState_CS_CONNECTING  ConnectorState = 1
+	ConnectorState_CS_LIVE        ConnectorState = 2
+	ConnectorState_CS_STALE       ConnectorState = 3 // connected but silent
+	ConnectorState_CS_BACKING_OFF ConnectorState = 4 // failed; waiting before reconnect
+	ConnectorState_CS_AUTH_FAILED ConnectorState = 5 // missing/rejected credentials
+)
+
+// Enum value maps for ConnectorState.
+var (
+	ConnectorState_name = map[int32]string{
+		0: "CS_UNSPECIFIED",
+		1: "CS_CONNECTING",
+		2: "CS_LIVE",
+		3: "CS_STALE",
+		4: "CS_BACKING_OFF",
+		5: "CS_AUTH_FAILED",
+	}
+	ConnectorState_value = map[string]int32{
+		"CS_UNSPECIFIED": 0,
+		"CS_CONNECTING":  1,
+		"CS_LIVE":        2,
+		"CS_STALE":       3,
+		"CS_BACKING_OFF": 4,
+		"CS_AUTH_FAILED": 5,
+	}
+)
+
+func (x ConnectorState) Enum() *ConnectorState {
+	p := new(ConnectorState)
+	*p = x
+	return p
+}
+
+func (x ConnectorState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ConnectorState) Descriptor() protoreflect.EnumDescriptor {
+	return file_price_v1_price_proto_enumTypes[3].Descriptor()
+}
+
+func (ConnectorState) Type() protoreflect.EnumType {
+	return &file_price_v1_price_proto_enumTypes[3]
+}
+
+func (x ConnectorState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ConnectorState.Descriptor instead.
+func (ConnectorState) EnumDescriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{3}
+}
+
+type FeedKind int32
+
+const (
+	FeedKind_FK_UNSPECIFIED FeedKind = 0
+	FeedKind_FK_TRADES      FeedKind = 1
+	FeedKind_FK_QUOTES      FeedKind = 2 // top of book
+	FeedKind_FK_DEPTH       FeedKind = 3 // L2 order book
+)
+
+// Enum value maps for FeedKind.
+var (
+	FeedKind_name = map[int32]string{
+		0: "FK_UNSPECIFIED",
+		1: "FK_TRADES",
+		2: "FK_QUOTES",
+		3: "FK_DEPTH",
+	}
+	FeedKind_value = map[string]int32{
+		"FK_UNSPECIFIED": 0,
+		"FK_TRADES":      1,
+		"FK_QUOTES":      2,
+		"FK_DEPTH":       3,
+	}
+)
+
+func (x FeedKind) Enum() *FeedKind {
+	p := new(FeedKind)
+	*p = x
+	return p
+}
+
+func (x FeedKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FeedKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_price_v1_price_proto_enumTypes[4].Descriptor()
+}
+
+func (FeedKind) Type() protoreflect.EnumType {
+	return &file_price_v1_price_proto_enumTypes[4]
+}
+
+func (x FeedKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FeedKind.Descriptor instead.
+func (FeedKind) EnumDescriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{4}
+}
+
+// Alerting
+type AlertKind int32
+
+const (
+	AlertKind_AK_UNSPECIFIED  AlertKind = 0
+	AlertKind_AK_PRICE_CROSS  AlertKind = 1 // close crosses `level` in either direction
+	AlertKind_AK_PCT_MOVE     AlertKind = 2 // |close / close `windows` windows ago - 1| >= `threshold` percent
+	AlertKind_AK_SPREAD_ABOVE AlertKind = 3 // window average spread > `threshold`
+	AlertKind_AK_VOLUME_SPIKE AlertKind = 4 // volume >= `threshold` x the mean of the previous `windows` windows
+	AlertKind_AK_FEED_STALE   AlertKind = 5 // candle flagged stale, or no candle for `stale_after_ms`
+)
+
+// Enum value maps for AlertKind.
+var (
+	AlertKind_name = map[int32]string{
+		0: "AK_UNSPECIFIED",
+		1: "AK_PRICE_CROSS",
+		2: "AK_PCT_MOVE",
+		3: "AK_SPREAD_ABOVE",
+		4: "AK_VOLUME_SPIKE",
+		5: "AK_FEED_STALE",
+	}
+	AlertKind_value = map[string]int32{
+		"AK_UNSPECIFIED":  0,
+		"AK_PRICE_CROSS":  1,
+		"AK_PCT_MOVE":     2,
+		"AK_SPREAD_ABOVE": 3,
+		"AK_VOLUME_SPIKE": 4,
+		"AK_FEED_STALE":   5,
+	}
+)
+
+func (x AlertKind) Enum() *AlertKind {
+	p := new(AlertKind)
+	*p = x
+	return p
+}
+
+func (x AlertKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AlertKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_price_v1_price_proto_enumTypes[5].Descriptor()
+}
+
+func (AlertKind) Type() protoreflect.EnumType {
+	return &file_price_v1_price_proto_enumTypes[5]
+}
+
+func (x AlertKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AlertKind.Descriptor instead.
+func (AlertKind) EnumDescriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{5}
+}
+
+type StreamStatusKind int32
+
+const (
+	StreamStatusKind_SK_UNSPECIFIED          StreamStatusKind = 0
+	StreamStatusKind_SK_MARKET_OPEN          StreamStatusKind = 1 // trading session open, also after a halt ends
+	StreamStatusKind_SK_MARKET_CLOSED        StreamStatusKind = 2 // outside the calendar's sessions or a holiday
+	StreamStatusKind_SK_SYMBOL_HALTED        StreamStatusKind = 3 // halted by the operator
+	StreamStatusKind_SK_SOURCE_STALE         StreamStatusKind = 4 // stale_sources deliver no data although the market is open
+	StreamStatusKind_SK_SOURCE_LIVE          StreamStatusKind = 5 // every source delivers data again
+	StreamStatusKind_SK_RESUBSCRIBE_REQUIRED StreamStatusKind = 6 // the stream ends next; resubscribe and backfill with GetCandles
+)
+
+// Enum value maps for StreamStatusKind.
+var (
+	StreamStatusKind_name = map[int32]string{
+		0: "SK_UNSPECIFIED",
+		1: "SK_MARKET_OPEN",
+		2: "SK_MARKET_CLOSED",
+		3: "SK_SYMBOL_HALTED",
+		4: "SK_SOURCE_STALE",
+		5: "SK_SOURCE_LIVE",
+		6: "SK_RESUBSCRIBE_REQUIRED",
+	}
+	StreamStatusKind_value = map[string]int32{
+		"SK_UNSPECIFIED":          0,
+		"SK_MARKET_OPEN":          1,
+		"SK_MARKET_CLOSED":        2,
+		"SK_SYMBOL_HALTED":        3,
+		"SK_SOURCE_STALE":         4,
+		"SK_SOURCE_LIVE":          5,
+		"SK_RESUBSCRIBE_REQUIRED": 6,
+	}
+)
+
+func (x StreamStatusKind) Enum() *StreamStatusKind {
+	p := new(StreamStatusKind)
+	*p = x
+	return p
+}
+
+func (x StreamStatusKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StreamStatusKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_price_v1_price_proto_enumTypes[6].Descriptor()
+}
+
+func (StreamStatusKind) Type() protoreflect.EnumType {
+	return &file_price_v1_price_proto_enumTypes[6]
+}
+
+func (x StreamStatusKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StreamStatusKind.Descriptor instead.
+func (StreamStatusKind) EnumDescriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{6}
 }
 
 type SubscribeRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`                            // e.g., "BTCUSDT", "EURUSD"
-	IntervalMs    int64                  `protobuf:"varint,2,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"` // e.g., 1000
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Symbol     string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`                            // e.g., "BTCUSDT", "EURUSD"
+	IntervalMs int64                  `protobuf:"varint,2,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"` // e.g., 1000 (time bars only)
+	// Activity-based bars are built per subscription from the live trade feed.
+	BarType BarType `protobuf:"varint,3,opt,name=bar_type,json=barType,proto3,enum=price.v1.BarType" json:"bar_type,omitempty"`
+	BarSize float64 `protobuf:"fixed64,4,opt,name=bar_size,json=barSize,proto3" json:"bar_size,omitempty"` // trades (tick), base qty (volume) or quote notional (dollar)
+	// Delivery options
+	FinalOnly           bool     `protobuf:"varint,5,opt,name=final_only,json=finalOnly,proto3" json:"final_only,omitempty"`                                 // only closed windows / completed bars
+	MaxUpdatesPerSec    float64  `protobuf:"fixed64,6,opt,name=max_updates_per_sec,json=maxUpdatesPerSec,proto3" json:"max_updates_per_sec,omitempty"`       // cap on partial updates; the latest partial wins (0 = unlimited)
+	Fields              []string `protobuf:"bytes,7,rep,name=fields,proto3" json:"fields,omitempty"`                                                         // Candle field names to return (symbol, window bounds, is_final and revision are always set); empty = all
+	HeartbeatIntervalMs int64    `protobuf:"varint,8,opt,name=heartbeat_interval_ms,json=heartbeatIntervalMs,proto3" json:"heartbeat_interval_ms,omitempty"` // StreamAggregateEvents: heartbeat after this long without a message (0 = server default, min 1000)
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
 func (x *SubscribeRequest) Reset() {
@@ -179,6 +470,48 @@ func (x *SubscribeRequest) GetIntervalMs() int64 {
 	return 0
 }
 
+func (x *SubscribeRequest) GetBarType() BarType {
+	if x != nil {
+		return x.BarType
+	}
+	return BarType_BAR_TIME
+}
+
+func (x *SubscribeRequest) GetBarSize() float64 {
+	if x != nil {
+		return x.BarSize
+	}
+	return 0
+}
+
+func (x *SubscribeRequest) GetFinalOnly() bool {
+	if x != nil {
+		return x.FinalOnly
+	}
+	return false
+}
+
+func (x *SubscribeRequest) GetMaxUpdatesPerSec() float64 {
+	if x != nil {
+		return x.MaxUpdatesPerSec
+	}
+	return 0
+}
+
+func (x *SubscribeRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *SubscribeRequest) GetHeartbeatIntervalMs() int64 {
+	if x != nil {
+		return x.HeartbeatIntervalMs
+	}
+	return 0
+}
+
 type Candle struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
@@ -199,8 +532,39 @@ type Candle struct {
 	PriceType      PriceType      `protobuf:"varint,15,opt,name=price_type,json=priceType,proto3,enum=price.v1.PriceType" json:"price_type,omitempty"`
 	BaseCcy        string         `protobuf:"bytes,16,opt,name=base_ccy,json=baseCcy,proto3" json:"base_ccy,omitempty"`    // ISO 4217, e.g., "EUR"
 	QuoteCcy       string         `protobuf:"bytes,17,opt,name=quote_ccy,json=quoteCcy,proto3" json:"quote_ccy,omitempty"` // ISO 4217, e.g., "USD"
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Synthetic cross rates (triangulated from live legs, e.g. EURJPY = EURUSD * USDJPY)
+	Synthetic  bool         `protobuf:"varint,18,opt,name=synthetic,proto3" json:"synthetic,omitempty"`
+	SourceLegs []*SourceLeg `protobuf:"bytes,19,rep,name=source_legs,json=sourceLegs,proto3" json:"source_legs,omitempty"` // lineage: legs of the last synthetic tick
+	// Composite price across venues (populated when the engine runs a consensus mode)
+	ConsensusPrice     float64  `protobuf:"fixed64,20,opt,name=consensus_price,json=consensusPrice,proto3" json:"consensus_price,omitempty"`
+	ContributingVenues []string `protobuf:"bytes,21,rep,name=contributing_venues,json=contributingVenues,proto3" json:"contributing_venues,omitempty"`
+	ExcludedVenues     []string `protobuf:"bytes,22,rep,name=excluded_venues,json=excludedVenues,proto3" json:"excluded_venues,omitempty"` // stale or beyond the deviation threshold
+	Confidence         float64  `protobuf:"fixed64,23,opt,name=confidence,proto3" json:"confidence,omitempty"`                             // 0..1: venue coverage x agreement
+	// Set while a source feeding this symbol is stale or reconnecting
+	Stale        bool     `protobuf:"varint,24,opt,name=stale,proto3" json:"stale,omitempty"`
+	StaleSources []string `protobuf:"bytes,25,rep,name=stale_sources,json=staleSources,proto3" json:"stale_sources,omitempty"` // exchanges, e.g. "binance"
+	// Bid/ask spread over the window (populated when top-of-book quotes are ingested)
+	SpreadAvg  float64 `protobuf:"fixed64,26,opt,name=spread_avg,json=spreadAvg,proto3" json:"spread_avg,omitempty"` // mean over quote updates
+	SpreadMax  float64 `protobuf:"fixed64,27,opt,name=spread_max,json=spreadMax,proto3" json:"spread_max,omitempty"`
+	SpreadTwa  float64 `protobuf:"fixed64,28,opt,name=spread_twa,json=spreadTwa,proto3" json:"spread_twa,omitempty"` // time-weighted
+	QuoteCount uint64  `protobuf:"varint,29,opt,name=quote_count,json=quoteCount,proto3" json:"quote_count,omitempty"`
+	// Order flow by aggressor side (venues that report it, e.g. Binance)
+	BuyVolume       float64            `protobuf:"fixed64,30,opt,name=buy_volume,json=buyVolume,proto3" json:"buy_volume,omitempty"`    // taker buys
+	SellVolume      float64            `protobuf:"fixed64,31,opt,name=sell_volume,json=sellVolume,proto3" json:"sell_volume,omitempty"` // taker sells
+	BuyCount        uint64             `protobuf:"varint,32,opt,name=buy_count,json=buyCount,proto3" json:"buy_count,omitempty"`
+	SellCount       uint64             `protobuf:"varint,33,opt,name=sell_count,json=sellCount,proto3" json:"sell_count,omitempty"`
+	VolumeDelta     float64            `protobuf:"fixed64,34,opt,name=volume_delta,json=volumeDelta,proto3" json:"volume_delta,omitempty"`             // buy_volume - sell_volume
+	CumulativeDelta float64            `protobuf:"fixed64,35,opt,name=cumulative_delta,json=cumulativeDelta,proto3" json:"cumulative_delta,omitempty"` // running delta since engine start
+	SizeBuckets     []*TradeSizeBucket `protobuf:"bytes,36,rep,name=size_buckets,json=sizeBuckets,proto3" json:"size_buckets,omitempty"`               // trade size distribution by notional
+	// Bar type this candle was built as; for non-time bars the window spans
+	// the first to last trade of the bar.
+	BarType BarType `protobuf:"varint,37,opt,name=bar_type,json=barType,proto3,enum=price.v1.BarType" json:"bar_type,omitempty"`
+	BarSize float64 `protobuf:"fixed64,38,opt,name=bar_size,json=barSize,proto3" json:"bar_size,omitempty"`
+	// Increments with every update of the same window; the final candle
+	// carries the highest revision.
+	Revision      uint32 `protobuf:"varint,39,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Candle) Reset() {
@@ -352,97 +716,3655 @@ func (x *Candle) GetQuoteCcy() string {
 	return ""
 }
 
-var File_price_v1_price_proto protoreflect.FileDescriptor
+func (x *Candle) GetSynthetic() bool {
+	if x != nil {
+		return x.Synthetic
+	}
+	return false
+}
 
-const file_price_v1_price_proto_rawDesc = "" +
-	"\n" +
-	"\x14price/v1/price.proto\x12\bprice.v1\"K\n" +
-	"\x10SubscribeRequest\x12\x16\n" +
-	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1f\n" +
-	"\vinterval_ms\x18\x02 \x01(\x03R\n" +
-	"intervalMs\"\x93\x04\n" +
-	"\x06Candle\x12\x16\n" +
-	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12&\n" +
-	"\x0fwindow_start_ms\x18\x02 \x01(\x03R\rwindowStartMs\x12\"\n" +
-	"\rwindow_end_ms\x18\x03 \x01(\x03R\vwindowEndMs\x12\x12\n" +
-	"\x04open\x18\x04 \x01(\x01R\x04open\x12\x12\n" +
-	"\x04high\x18\x05 \x01(\x01R\x04high\x12\x10\n" +
-	"\x03low\x18\x06 \x01(\x01R\x03low\x12\x14\n" +
-	"\x05close\x18\a \x01(\x01R\x05close\x12\x16\n" +
-	"\x06volume\x18\b \x01(\x01R\x06volume\x12\x12\n" +
-	"\x04vwap\x18\t \x01(\x01R\x04vwap\x12\x19\n" +
-	"\bis_final\x18\n" +
-	" \x01(\bR\aisFinal\x12\x1a\n" +
-	"\bexchange\x18\v \x01(\tR\bexchange\x12\"\n" +
-	"\rlast_trade_ts\x18\f \x01(\x03R\vlastTradeTs\x12\x1f\n" +
-	"\vtrade_count\x18\r \x01(\x04R\n" +
-	"tradeCount\x12A\n" +
-	"\x0finstrument_type\x18\x0e \x01(\x0e2\x18.price.v1.InstrumentTypeR\x0einstrumentType\x122\n" +
-	"\n" +
-	"price_type\x18\x0f \x01(\x0e2\x13.price.v1.PriceTypeR\tpriceType\x12\x19\n" +
-	"\bbase_ccy\x18\x10 \x01(\tR\abaseCcy\x12\x1b\n" +
-	"\tquote_ccy\x18\x11 \x01(\tR\bquoteCcy*H\n" +
-	"\x0eInstrumentType\x12\x12\n" +
-	"\x0eIT_UNSPECIFIED\x10\x00\x12\x12\n" +
-	"\x0eIT_CRYPTO_SPOT\x10\x01\x12\x0e\n" +
-	"\n" +
-	"IT_FX_SPOT\x10\x02*Q\n" +
-	"\tPriceType\x12\x12\n" +
-	"\x0ePT_UNSPECIFIED\x10\x00\x12\f\n" +
-	"\bPT_TRADE\x10\x01\x12\n" +
-	"\n" +
-	"\x06PT_BID\x10\x02\x12\n" +
-	"\n" +
-	"\x06PT_ASK\x10\x03\x12\n" +
-	"\n" +
-	"\x06PT_MID\x10\x042Q\n" +
-	"\vPriceStream\x12B\n" +
-	"\x10StreamAggregates\x12\x1a.price.v1.SubscribeRequest\x1a\x10.price.v1.Candle0\x01B>Z<github.com/binaridigital/price-engine/proto/price/v1;pricev1b\x06proto3"
+func (x *Candle) GetSourceLegs() []*SourceLeg {
+	if x != nil {
+		return x.SourceLegs
+	}
+	return nil
+}
 
-var (
-	file_price_v1_price_proto_rawDescOnce sync.Once
-	file_price_v1_price_proto_rawDescData []byte
-)
+func (x *Candle) GetConsensusPrice() float64 {
+	if x != nil {
+		return x.ConsensusPrice
+	}
+	return 0
+}
 
-func file_price_v1_price_proto_rawDescGZIP() []byte {
-	file_price_v1_price_proto_rawDescOnce.Do(func() {
-		file_price_v1_price_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_price_v1_price_proto_rawDesc), len(file_price_v1_price_proto_rawDesc)))
-	})
-	return file_price_v1_price_proto_rawDescData
+func (x *Candle) GetContributingVenues() []string {
+	if x != nil {
+		return x.ContributingVenues
+	}
+	return nil
 }
 
-var file_price_v1_price_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_price_v1_price_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
-var file_price_v1_price_proto_goTypes = []any{
-	(InstrumentType)(0),      // 0: price.v1.InstrumentType
-	(PriceType)(0),           // 1: price.v1.PriceType
-	(*SubscribeRequest)(nil), // 2: price.v1.SubscribeRequest
-	(*Candle)(nil),           // 3: price.v1.Candle
+func (x *Candle) GetExcludedVenues() []string {
+	if x != nil {
+		return x.ExcludedVenues
+	}
+	return nil
 }
-var file_price_v1_price_proto_depIdxs = []int32{
-	0, // 0: price.v1.Candle.instrument_type:type_name -> price.v1.InstrumentType
-	1, // 1: price.v1.Candle.price_type:type_name -> price.v1.PriceType
-	2, // 2: price.v1.PriceStream.StreamAggregates:input_type -> price.v1.SubscribeRequest
-	3, // 3: price.v1.PriceStream.StreamAggregates:output_type -> price.v1.Candle
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+
+func (x *Candle) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
 }
 
-func init() { file_price_v1_price_proto_init() }
-func file_price_v1_price_proto_init() {
-	if File_price_v1_price_proto != nil {
-		return
+func (x *Candle) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *Candle) GetStaleSources() []string {
+	if x != nil {
+		return x.StaleSources
+	}
+	return nil
+}
+
+func (x *Candle) GetSpreadAvg() float64 {
+	if x != nil {
+		return x.SpreadAvg
+	}
+	return 0
+}
+
+func (x *Candle) GetSpreadMax() float64 {
+	if x != nil {
+		return x.SpreadMax
+	}
+	return 0
+}
+
+func (x *Candle) GetSpreadTwa() float64 {
+	if x != nil {
+		return x.SpreadTwa
+	}
+	return 0
+}
+
+func (x *Candle) GetQuoteCount() uint64 {
+	if x != nil {
+		return x.QuoteCount
+	}
+	return 0
+}
+
+func (x *Candle) GetBuyVolume() float64 {
+	if x != nil {
+		return x.BuyVolume
+	}
+	return 0
+}
+
+func (x *Candle) GetSellVolume() float64 {
+	if x != nil {
+		return x.SellVolume
+	}
+	return 0
+}
+
+func (x *Candle) GetBuyCount() uint64 {
+	if x != nil {
+		return x.BuyCount
+	}
+	return 0
+}
+
+func (x *Candle) GetSellCount() uint64 {
+	if x != nil {
+		return x.SellCount
+	}
+	return 0
+}
+
+func (x *Candle) GetVolumeDelta() float64 {
+	if x != nil {
+		return x.VolumeDelta
+	}
+	return 0
+}
+
+func (x *Candle) GetCumulativeDelta() float64 {
+	if x != nil {
+		return x.CumulativeDelta
+	}
+	return 0
+}
+
+func (x *Candle) GetSizeBuckets() []*TradeSizeBucket {
+	if x != nil {
+		return x.SizeBuckets
+	}
+	return nil
+}
+
+func (x *Candle) GetBarType() BarType {
+	if x != nil {
+		return x.BarType
+	}
+	return BarType_BAR_TIME
+}
+
+func (x *Candle) GetBarSize() float64 {
+	if x != nil {
+		return x.BarSize
+	}
+	return 0
+}
+
+func (x *Candle) GetRevision() uint32 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type TradeSizeBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UpperNotional float64                `protobuf:"fixed64,1,opt,name=upper_notional,json=upperNotional,proto3" json:"upper_notional,omitempty"` // price x qty upper bound (inclusive); +Inf for the last bucket
+	Count         uint64                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Volume        float64                `protobuf:"fixed64,3,opt,name=volume,proto3" json:"volume,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TradeSizeBucket) Reset() {
+	*x = TradeSizeBucket{}
+	mi := &file_price_v1_price_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TradeSizeBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradeSizeBucket) ProtoMessage() {}
+
+func (x *TradeSizeBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradeSizeBucket.ProtoReflect.Descriptor instead.
+func (*TradeSizeBucket) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TradeSizeBucket) GetUpperNotional() float64 {
+	if x != nil {
+		return x.UpperNotional
+	}
+	return 0
+}
+
+func (x *TradeSizeBucket) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *TradeSizeBucket) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+type SourceLeg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Exchange      string                 `protobuf:"bytes,2,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Price         float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	Inverted      bool                   `protobuf:"varint,4,opt,name=inverted,proto3" json:"inverted,omitempty"` // leg contributed 1/price
+	Ts            int64                  `protobuf:"varint,5,opt,name=ts,proto3" json:"ts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SourceLeg) Reset() {
+	*x = SourceLeg{}
+	mi := &file_price_v1_price_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SourceLeg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SourceLeg) ProtoMessage() {}
+
+func (x *SourceLeg) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SourceLeg.ProtoReflect.Descriptor instead.
+func (*SourceLeg) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SourceLeg) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *SourceLeg) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *SourceLeg) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *SourceLeg) GetInverted() bool {
+	if x != nil {
+		return x.Inverted
+	}
+	return false
+}
+
+func (x *SourceLeg) GetTs() int64 {
+	if x != nil {
+		return x.Ts
+	}
+	return 0
+}
+
+type ExchangeStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"` // optional filter, e.g. "binance"
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`     // optional filter
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExchangeStatusRequest) Reset() {
+	*x = ExchangeStatusRequest{}
+	mi := &file_price_v1_price_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExchangeStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExchangeStatusRequest) ProtoMessage() {}
+
+func (x *ExchangeStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExchangeStatusRequest.ProtoReflect.Descriptor instead.
+func (*ExchangeStatusRequest) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ExchangeStatusRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *ExchangeStatusRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type ExchangeStatus struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Exchange       string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Symbol         string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	State          ConnectorState         `protobuf:"varint,3,opt,name=state,proto3,enum=price.v1.ConnectorState" json:"state,omitempty"`
+	StateSinceTs   int64                  `protobuf:"varint,4,opt,name=state_since_ts,json=stateSinceTs,proto3" json:"state_since_ts,omitempty"`
+	LastMessageTs  int64                  `protobuf:"varint,5,opt,name=last_message_ts,json=lastMessageTs,proto3" json:"last_message_ts,omitempty"`
+	ReconnectCount uint64                 `protobuf:"varint,6,opt,name=reconnect_count,json=reconnectCount,proto3" json:"reconnect_count,omitempty"`
+	LastError      string                 `protobuf:"bytes,7,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	Replica        uint32                 `protobuf:"varint,8,opt,name=replica,proto3" json:"replica,omitempty"` // 0 = primary, 1.. = hot standby
+	Feed           FeedKind               `protobuf:"varint,9,opt,name=feed,proto3,enum=price.v1.FeedKind" json:"feed,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExchangeStatus) Reset() {
+	*x = ExchangeStatus{}
+	mi := &file_price_v1_price_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExchangeStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExchangeStatus) ProtoMessage() {}
+
+func (x *ExchangeStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExchangeStatus.ProtoReflect.Descriptor instead.
+func (*ExchangeStatus) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ExchangeStatus) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *ExchangeStatus) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *ExchangeStatus) GetState() ConnectorState {
+	if x != nil {
+		return x.State
+	}
+	return ConnectorState_CS_UNSPECIFIED
+}
+
+func (x *ExchangeStatus) GetStateSinceTs() int64 {
+	if x != nil {
+		return x.StateSinceTs
+	}
+	return 0
+}
+
+func (x *ExchangeStatus) GetLastMessageTs() int64 {
+	if x != nil {
+		return x.LastMessageTs
+	}
+	return 0
+}
+
+func (x *ExchangeStatus) GetReconnectCount() uint64 {
+	if x != nil {
+		return x.ReconnectCount
+	}
+	return 0
+}
+
+func (x *ExchangeStatus) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *ExchangeStatus) GetReplica() uint32 {
+	if x != nil {
+		return x.Replica
+	}
+	return 0
+}
+
+func (x *ExchangeStatus) GetFeed() FeedKind {
+	if x != nil {
+		return x.Feed
+	}
+	return FeedKind_FK_UNSPECIFIED
+}
+
+type ExchangeStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []*ExchangeStatus      `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExchangeStatusResponse) Reset() {
+	*x = ExchangeStatusResponse{}
+	mi := &file_price_v1_price_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExchangeStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExchangeStatusResponse) ProtoMessage() {}
+
+func (x *ExchangeStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExchangeStatusResponse.ProtoReflect.Descriptor instead.
+func (*ExchangeStatusResponse) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ExchangeStatusResponse) GetStatuses() []*ExchangeStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+// L2 order book
+type OrderBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Depth         uint32                 `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"` // levels per side; 0 = server default (20)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderBookRequest) Reset() {
+	*x = OrderBookRequest{}
+	mi := &file_price_v1_price_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderBookRequest) ProtoMessage() {}
+
+func (x *OrderBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderBookRequest.ProtoReflect.Descriptor instead.
+func (*OrderBookRequest) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *OrderBookRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *OrderBookRequest) GetDepth() uint32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+type PriceLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Price         float64                `protobuf:"fixed64,1,opt,name=price,proto3" json:"price,omitempty"`
+	Qty           float64                `protobuf:"fixed64,2,opt,name=qty,proto3" json:"qty,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceLevel) Reset() {
+	*x = PriceLevel{}
+	mi := &file_price_v1_price_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceLevel) ProtoMessage() {}
+
+func (x *PriceLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceLevel.ProtoReflect.Descriptor instead.
+func (*PriceLevel) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PriceLevel) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PriceLevel) GetQty() float64 {
+	if x != nil {
+		return x.Qty
+	}
+	return 0
+}
+
+type OrderBook struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Exchange      string                 `protobuf:"bytes,2,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	LastUpdateId  uint64                 `protobuf:"varint,3,opt,name=last_update_id,json=lastUpdateId,proto3" json:"last_update_id,omitempty"` // venue sequence of the last applied diff
+	Ts            int64                  `protobuf:"varint,4,opt,name=ts,proto3" json:"ts,omitempty"`
+	BestBid       *PriceLevel            `protobuf:"bytes,5,opt,name=best_bid,json=bestBid,proto3" json:"best_bid,omitempty"`
+	BestAsk       *PriceLevel            `protobuf:"bytes,6,opt,name=best_ask,json=bestAsk,proto3" json:"best_ask,omitempty"`
+	Spread        float64                `protobuf:"fixed64,7,opt,name=spread,proto3" json:"spread,omitempty"`
+	Bids          []*PriceLevel          `protobuf:"bytes,8,rep,name=bids,proto3" json:"bids,omitempty"` // best first (descending)
+	Asks          []*PriceLevel          `protobuf:"bytes,9,rep,name=asks,proto3" json:"asks,omitempty"` // best first (ascending)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderBook) Reset() {
+	*x = OrderBook{}
+	mi := &file_price_v1_price_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderBook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderBook) ProtoMessage() {}
+
+func (x *OrderBook) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderBook.ProtoReflect.Descriptor instead.
+func (*OrderBook) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *OrderBook) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *OrderBook) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *OrderBook) GetLastUpdateId() uint64 {
+	if x != nil {
+		return x.LastUpdateId
+	}
+	return 0
+}
+
+func (x *OrderBook) GetTs() int64 {
+	if x != nil {
+		return x.Ts
+	}
+	return 0
+}
+
+func (x *OrderBook) GetBestBid() *PriceLevel {
+	if x != nil {
+		return x.BestBid
+	}
+	return nil
+}
+
+func (x *OrderBook) GetBestAsk() *PriceLevel {
+	if x != nil {
+		return x.BestAsk
+	}
+	return nil
+}
+
+func (x *OrderBook) GetSpread() float64 {
+	if x != nil {
+		return x.Spread
+	}
+	return 0
+}
+
+func (x *OrderBook) GetBids() []*PriceLevel {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *OrderBook) GetAsks() []*PriceLevel {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+// Volume profile / footprint: volume by price level per window
+type VolumeAtPrice struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Price         float64                `protobuf:"fixed64,1,opt,name=price,proto3" json:"price,omitempty"` // lower edge of the tick bucket
+	Volume        float64                `protobuf:"fixed64,2,opt,name=volume,proto3" json:"volume,omitempty"`
+	BuyVolume     float64                `protobuf:"fixed64,3,opt,name=buy_volume,json=buyVolume,proto3" json:"buy_volume,omitempty"`    // taker buys (footprint ask side)
+	SellVolume    float64                `protobuf:"fixed64,4,opt,name=sell_volume,json=sellVolume,proto3" json:"sell_volume,omitempty"` // taker sells (footprint bid side)
+	Delta         float64                `protobuf:"fixed64,5,opt,name=delta,proto3" json:"delta,omitempty"`
+	TradeCount    uint64                 `protobuf:"varint,6,opt,name=trade_count,json=tradeCount,proto3" json:"trade_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VolumeAtPrice) Reset() {
+	*x = VolumeAtPrice{}
+	mi := &file_price_v1_price_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VolumeAtPrice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VolumeAtPrice) ProtoMessage() {}
+
+func (x *VolumeAtPrice) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VolumeAtPrice.ProtoReflect.Descriptor instead.
+func (*VolumeAtPrice) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *VolumeAtPrice) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *VolumeAtPrice) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *VolumeAtPrice) GetBuyVolume() float64 {
+	if x != nil {
+		return x.BuyVolume
+	}
+	return 0
+}
+
+func (x *VolumeAtPrice) GetSellVolume() float64 {
+	if x != nil {
+		return x.SellVolume
+	}
+	return 0
+}
+
+func (x *VolumeAtPrice) GetDelta() float64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *VolumeAtPrice) GetTradeCount() uint64 {
+	if x != nil {
+		return x.TradeCount
+	}
+	return 0
+}
+
+type VolumeProfile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	WindowStartMs int64                  `protobuf:"varint,2,opt,name=window_start_ms,json=windowStartMs,proto3" json:"window_start_ms,omitempty"`
+	WindowEndMs   int64                  `protobuf:"varint,3,opt,name=window_end_ms,json=windowEndMs,proto3" json:"window_end_ms,omitempty"`
+	TickSize      float64                `protobuf:"fixed64,4,opt,name=tick_size,json=tickSize,proto3" json:"tick_size,omitempty"`
+	TotalVolume   float64                `protobuf:"fixed64,5,opt,name=total_volume,json=totalVolume,proto3" json:"total_volume,omitempty"`
+	PocPrice      float64                `protobuf:"fixed64,6,opt,name=poc_price,json=pocPrice,proto3" json:"poc_price,omitempty"` // point of control: level with the most volume
+	ValueAreaLow  float64                `protobuf:"fixed64,7,opt,name=value_area_low,json=valueAreaLow,proto3" json:"value_area_low,omitempty"`
+	ValueAreaHigh float64                `protobuf:"fixed64,8,opt,name=value_area_high,json=valueAreaHigh,proto3" json:"value_area_high,omitempty"` // levels holding ~70% of volume around the POC
+	IsFinal       bool                   `protobuf:"varint,9,opt,name=is_final,json=isFinal,proto3" json:"is_final,omitempty"`
+	Levels        []*VolumeAtPrice       `protobuf:"bytes,10,rep,name=levels,proto3" json:"levels,omitempty"` // ascending price
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VolumeProfile) Reset() {
+	*x = VolumeProfile{}
+	mi := &file_price_v1_price_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VolumeProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VolumeProfile) ProtoMessage() {}
+
+func (x *VolumeProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VolumeProfile.ProtoReflect.Descriptor instead.
+func (*VolumeProfile) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *VolumeProfile) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *VolumeProfile) GetWindowStartMs() int64 {
+	if x != nil {
+		return x.WindowStartMs
+	}
+	return 0
+}
+
+func (x *VolumeProfile) GetWindowEndMs() int64 {
+	if x != nil {
+		return x.WindowEndMs
+	}
+	return 0
+}
+
+func (x *VolumeProfile) GetTickSize() float64 {
+	if x != nil {
+		return x.TickSize
+	}
+	return 0
+}
+
+func (x *VolumeProfile) GetTotalVolume() float64 {
+	if x != nil {
+		return x.TotalVolume
+	}
+	return 0
+}
+
+func (x *VolumeProfile) GetPocPrice() float64 {
+	if x != nil {
+		return x.PocPrice
+	}
+	return 0
+}
+
+func (x *VolumeProfile) GetValueAreaLow() float64 {
+	if x != nil {
+		return x.ValueAreaLow
+	}
+	return 0
+}
+
+func (x *VolumeProfile) GetValueAreaHigh() float64 {
+	if x != nil {
+		return x.ValueAreaHigh
+	}
+	return 0
+}
+
+func (x *VolumeProfile) GetIsFinal() bool {
+	if x != nil {
+		return x.IsFinal
+	}
+	return false
+}
+
+func (x *VolumeProfile) GetLevels() []*VolumeAtPrice {
+	if x != nil {
+		return x.Levels
+	}
+	return nil
+}
+
+// L1 top of book
+type QuoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuoteRequest) Reset() {
+	*x = QuoteRequest{}
+	mi := &file_price_v1_price_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuoteRequest) ProtoMessage() {}
+
+func (x *QuoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuoteRequest.ProtoReflect.Descriptor instead.
+func (*QuoteRequest) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *QuoteRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type Quote struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Exchange      string                 `protobuf:"bytes,2,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Bid           float64                `protobuf:"fixed64,3,opt,name=bid,proto3" json:"bid,omitempty"`
+	BidQty        float64                `protobuf:"fixed64,4,opt,name=bid_qty,json=bidQty,proto3" json:"bid_qty,omitempty"`
+	Ask           float64                `protobuf:"fixed64,5,opt,name=ask,proto3" json:"ask,omitempty"`
+	AskQty        float64                `protobuf:"fixed64,6,opt,name=ask_qty,json=askQty,proto3" json:"ask_qty,omitempty"`
+	Spread        float64                `protobuf:"fixed64,7,opt,name=spread,proto3" json:"spread,omitempty"`
+	Mid           float64                `protobuf:"fixed64,8,opt,name=mid,proto3" json:"mid,omitempty"`
+	Ts            int64                  `protobuf:"varint,9,opt,name=ts,proto3" json:"ts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Quote) Reset() {
+	*x = Quote{}
+	mi := &file_price_v1_price_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quote) ProtoMessage() {}
+
+func (x *Quote) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quote.ProtoReflect.Descriptor instead.
+func (*Quote) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Quote) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Quote) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *Quote) GetBid() float64 {
+	if x != nil {
+		return x.Bid
+	}
+	return 0
+}
+
+func (x *Quote) GetBidQty() float64 {
+	if x != nil {
+		return x.BidQty
+	}
+	return 0
+}
+
+func (x *Quote) GetAsk() float64 {
+	if x != nil {
+		return x.Ask
+	}
+	return 0
+}
+
+func (x *Quote) GetAskQty() float64 {
+	if x != nil {
+		return x.AskQty
+	}
+	return 0
+}
+
+func (x *Quote) GetSpread() float64 {
+	if x != nil {
+		return x.Spread
+	}
+	return 0
+}
+
+func (x *Quote) GetMid() float64 {
+	if x != nil {
+		return x.Mid
+	}
+	return 0
+}
+
+func (x *Quote) GetTs() int64 {
+	if x != nil {
+		return x.Ts
+	}
+	return 0
+}
+
+// Technical indicators on final candles
+type IndicatorValue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`     // spec, e.g. "ema:50" or "bb:20:2"
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"` // middle band for Bollinger
+	Upper         float64                `protobuf:"fixed64,3,opt,name=upper,proto3" json:"upper,omitempty"` // Bollinger only
+	Lower         float64                `protobuf:"fixed64,4,opt,name=lower,proto3" json:"lower,omitempty"` // Bollinger only
+	Ready         bool                   `protobuf:"varint,5,opt,name=ready,proto3" json:"ready,omitempty"`  // false while the indicator is warming up
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IndicatorValue) Reset() {
+	*x = IndicatorValue{}
+	mi := &file_price_v1_price_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IndicatorValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndicatorValue) ProtoMessage() {}
+
+func (x *IndicatorValue) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndicatorValue.ProtoReflect.Descriptor instead.
+func (*IndicatorValue) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *IndicatorValue) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *IndicatorValue) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *IndicatorValue) GetUpper() float64 {
+	if x != nil {
+		return x.Upper
+	}
+	return 0
+}
+
+func (x *IndicatorValue) GetLower() float64 {
+	if x != nil {
+		return x.Lower
+	}
+	return 0
+}
+
+func (x *IndicatorValue) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+type IndicatorUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	IntervalMs    int64                  `protobuf:"varint,2,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"`
+	WindowStartMs int64                  `protobuf:"varint,3,opt,name=window_start_ms,json=windowStartMs,proto3" json:"window_start_ms,omitempty"`
+	WindowEndMs   int64                  `protobuf:"varint,4,opt,name=window_end_ms,json=windowEndMs,proto3" json:"window_end_ms,omitempty"`
+	Close         float64                `protobuf:"fixed64,5,opt,name=close,proto3" json:"close,omitempty"`
+	Values        []*IndicatorValue      `protobuf:"bytes,6,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IndicatorUpdate) Reset() {
+	*x = IndicatorUpdate{}
+	mi := &file_price_v1_price_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IndicatorUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndicatorUpdate) ProtoMessage() {}
+
+func (x *IndicatorUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndicatorUpdate.ProtoReflect.Descriptor instead.
+func (*IndicatorUpdate) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *IndicatorUpdate) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *IndicatorUpdate) GetIntervalMs() int64 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
+func (x *IndicatorUpdate) GetWindowStartMs() int64 {
+	if x != nil {
+		return x.WindowStartMs
+	}
+	return 0
+}
+
+func (x *IndicatorUpdate) GetWindowEndMs() int64 {
+	if x != nil {
+		return x.WindowEndMs
+	}
+	return 0
+}
+
+func (x *IndicatorUpdate) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *IndicatorUpdate) GetValues() []*IndicatorValue {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// Scheduled reference price fixings
+type FixingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // optional: only this fixing schedule
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FixingRequest) Reset() {
+	*x = FixingRequest{}
+	mi := &file_price_v1_price_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FixingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FixingRequest) ProtoMessage() {}
+
+func (x *FixingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FixingRequest.ProtoReflect.Descriptor instead.
+func (*FixingRequest) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *FixingRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *FixingRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type FixingSource struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	TradeCount    uint64                 `protobuf:"varint,2,opt,name=trade_count,json=tradeCount,proto3" json:"trade_count,omitempty"`
+	Volume        float64                `protobuf:"fixed64,3,opt,name=volume,proto3" json:"volume,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FixingSource) Reset() {
+	*x = FixingSource{}
+	mi := &file_price_v1_price_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FixingSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FixingSource) ProtoMessage() {}
+
+func (x *FixingSource) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FixingSource.ProtoReflect.Descriptor instead.
+func (*FixingSource) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *FixingSource) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *FixingSource) GetTradeCount() uint64 {
+	if x != nil {
+		return x.TradeCount
+	}
+	return 0
+}
+
+func (x *FixingSource) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+type Fixing struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // schedule, e.g. "LDN4PM"
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Method        string                 `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"` // twap | vwap | median: which of the values below is `value`
+	Value         float64                `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"`
+	Twap          float64                `protobuf:"fixed64,5,opt,name=twap,proto3" json:"twap,omitempty"`
+	Vwap          float64                `protobuf:"fixed64,6,opt,name=vwap,proto3" json:"vwap,omitempty"`
+	Median        float64                `protobuf:"fixed64,7,opt,name=median,proto3" json:"median,omitempty"`
+	WindowStartMs int64                  `protobuf:"varint,8,opt,name=window_start_ms,json=windowStartMs,proto3" json:"window_start_ms,omitempty"`
+	WindowEndMs   int64                  `protobuf:"varint,9,opt,name=window_end_ms,json=windowEndMs,proto3" json:"window_end_ms,omitempty"`
+	TradeCount    uint64                 `protobuf:"varint,10,opt,name=trade_count,json=tradeCount,proto3" json:"trade_count,omitempty"`
+	Volume        float64                `protobuf:"fixed64,11,opt,name=volume,proto3" json:"volume,omitempty"`
+	Sources       []*FixingSource        `protobuf:"bytes,12,rep,name=sources,proto3" json:"sources,omitempty"`
+	Methodology   string                 `protobuf:"bytes,13,opt,name=methodology,proto3" json:"methodology,omitempty"` // human-readable description of the calculation
+	PublishedTs   int64                  `protobuf:"varint,14,opt,name=published_ts,json=publishedTs,proto3" json:"published_ts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Fixing) Reset() {
+	*x = Fixing{}
+	mi := &file_price_v1_price_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Fixing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fixing) ProtoMessage() {}
+
+func (x *Fixing) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fixing.ProtoReflect.Descriptor instead.
+func (*Fixing) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Fixing) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Fixing) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Fixing) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Fixing) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Fixing) GetTwap() float64 {
+	if x != nil {
+		return x.Twap
+	}
+	return 0
+}
+
+func (x *Fixing) GetVwap() float64 {
+	if x != nil {
+		return x.Vwap
+	}
+	return 0
+}
+
+func (x *Fixing) GetMedian() float64 {
+	if x != nil {
+		return x.Median
+	}
+	return 0
+}
+
+func (x *Fixing) GetWindowStartMs() int64 {
+	if x != nil {
+		return x.WindowStartMs
+	}
+	return 0
+}
+
+func (x *Fixing) GetWindowEndMs() int64 {
+	if x != nil {
+		return x.WindowEndMs
+	}
+	return 0
+}
+
+func (x *Fixing) GetTradeCount() uint64 {
+	if x != nil {
+		return x.TradeCount
+	}
+	return 0
+}
+
+func (x *Fixing) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *Fixing) GetSources() []*FixingSource {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *Fixing) GetMethodology() string {
+	if x != nil {
+		return x.Methodology
+	}
+	return ""
+}
+
+func (x *Fixing) GetPublishedTs() int64 {
+	if x != nil {
+		return x.PublishedTs
+	}
+	return 0
+}
+
+type AlertRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // assigned on create
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Kind          AlertKind              `protobuf:"varint,3,opt,name=kind,proto3,enum=price.v1.AlertKind" json:"kind,omitempty"`
+	Level         float64                `protobuf:"fixed64,4,opt,name=level,proto3" json:"level,omitempty"`
+	Threshold     float64                `protobuf:"fixed64,5,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Windows       int32                  `protobuf:"varint,6,opt,name=windows,proto3" json:"windows,omitempty"`
+	StaleAfterMs  int64                  `protobuf:"varint,7,opt,name=stale_after_ms,json=staleAfterMs,proto3" json:"stale_after_ms,omitempty"`
+	Webhook       bool                   `protobuf:"varint,8,opt,name=webhook,proto3" json:"webhook,omitempty"` // also POST alerts to the engine's webhook URL
+	Note          string                 `protobuf:"bytes,9,opt,name=note,proto3" json:"note,omitempty"`
+	CreatedTs     int64                  `protobuf:"varint,10,opt,name=created_ts,json=createdTs,proto3" json:"created_ts,omitempty"`
+	Owner         string                 `protobuf:"bytes,11,opt,name=owner,proto3" json:"owner,omitempty"` // client that created it; set by the server
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertRule) Reset() {
+	*x = AlertRule{}
+	mi := &file_price_v1_price_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRule) ProtoMessage() {}
+
+func (x *AlertRule) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRule.ProtoReflect.Descriptor instead.
+func (*AlertRule) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AlertRule) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AlertRule) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *AlertRule) GetKind() AlertKind {
+	if x != nil {
+		return x.Kind
+	}
+	return AlertKind_AK_UNSPECIFIED
+}
+
+func (x *AlertRule) GetLevel() float64 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *AlertRule) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *AlertRule) GetWindows() int32 {
+	if x != nil {
+		return x.Windows
+	}
+	return 0
+}
+
+func (x *AlertRule) GetStaleAfterMs() int64 {
+	if x != nil {
+		return x.StaleAfterMs
+	}
+	return 0
+}
+
+func (x *AlertRule) GetWebhook() bool {
+	if x != nil {
+		return x.Webhook
+	}
+	return false
+}
+
+func (x *AlertRule) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *AlertRule) GetCreatedTs() int64 {
+	if x != nil {
+		return x.CreatedTs
+	}
+	return 0
+}
+
+func (x *AlertRule) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+type Alert struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleId        string                 `protobuf:"bytes,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Kind          AlertKind              `protobuf:"varint,3,opt,name=kind,proto3,enum=price.v1.AlertKind" json:"kind,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	Value         float64                `protobuf:"fixed64,5,opt,name=value,proto3" json:"value,omitempty"` // observed value that triggered the rule
+	Threshold     float64                `protobuf:"fixed64,6,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	WindowEndMs   int64                  `protobuf:"varint,7,opt,name=window_end_ms,json=windowEndMs,proto3" json:"window_end_ms,omitempty"`
+	Ts            int64                  `protobuf:"varint,8,opt,name=ts,proto3" json:"ts,omitempty"`
+	Note          string                 `protobuf:"bytes,9,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	mi := &file_price_v1_price_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Alert) GetRuleId() string {
+	if x != nil {
+		return x.RuleId
+	}
+	return ""
+}
+
+func (x *Alert) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Alert) GetKind() AlertKind {
+	if x != nil {
+		return x.Kind
+	}
+	return AlertKind_AK_UNSPECIFIED
+}
+
+func (x *Alert) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Alert) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Alert) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *Alert) GetWindowEndMs() int64 {
+	if x != nil {
+		return x.WindowEndMs
+	}
+	return 0
+}
+
+func (x *Alert) GetTs() int64 {
+	if x != nil {
+		return x.Ts
+	}
+	return 0
+}
+
+func (x *Alert) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type AlertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"` // optional: all symbols when empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertRequest) Reset() {
+	*x = AlertRequest{}
+	mi := &file_price_v1_price_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRequest) ProtoMessage() {}
+
+func (x *AlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRequest.ProtoReflect.Descriptor instead.
+func (*AlertRequest) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AlertRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type DeleteAlertRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAlertRuleRequest) Reset() {
+	*x = DeleteAlertRuleRequest{}
+	mi := &file_price_v1_price_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAlertRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAlertRuleRequest) ProtoMessage() {}
+
+func (x *DeleteAlertRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAlertRuleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAlertRuleRequest) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DeleteAlertRuleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteAlertRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAlertRuleResponse) Reset() {
+	*x = DeleteAlertRuleResponse{}
+	mi := &file_price_v1_price_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAlertRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAlertRuleResponse) ProtoMessage() {}
+
+func (x *DeleteAlertRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAlertRuleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAlertRuleResponse) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeleteAlertRuleResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type ListAlertRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"` // optional
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertRulesRequest) Reset() {
+	*x = ListAlertRulesRequest{}
+	mi := &file_price_v1_price_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertRulesRequest) ProtoMessage() {}
+
+func (x *ListAlertRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListAlertRulesRequest) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListAlertRulesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type ListAlertRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*AlertRule           `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertRulesResponse) Reset() {
+	*x = ListAlertRulesResponse{}
+	mi := &file_price_v1_price_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertRulesResponse) ProtoMessage() {}
+
+func (x *ListAlertRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListAlertRulesResponse) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListAlertRulesResponse) GetRules() []*AlertRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// Trade removed by the pre-aggregation bad-tick filter
+type QuarantinedTrade struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Symbol         string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Exchange       string                 `protobuf:"bytes,2,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Price          float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	Qty            float64                `protobuf:"fixed64,4,opt,name=qty,proto3" json:"qty,omitempty"`
+	Ts             int64                  `protobuf:"varint,5,opt,name=ts,proto3" json:"ts,omitempty"` // venue timestamp (ms)
+	TradeId        string                 `protobuf:"bytes,6,opt,name=trade_id,json=tradeId,proto3" json:"trade_id,omitempty"`
+	Reason         string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`                                         // non_positive_price | future_timestamp | outlier
+	ReferencePrice float64                `protobuf:"fixed64,8,opt,name=reference_price,json=referencePrice,proto3" json:"reference_price,omitempty"` // rolling median (outliers)
+	Score          float64                `protobuf:"fixed64,9,opt,name=score,proto3" json:"score,omitempty"`                                         // robust z-score (outliers)
+	RejectedTs     int64                  `protobuf:"varint,10,opt,name=rejected_ts,json=rejectedTs,proto3" json:"rejected_ts,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *QuarantinedTrade) Reset() {
+	*x = QuarantinedTrade{}
+	mi := &file_price_v1_price_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuarantinedTrade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuarantinedTrade) ProtoMessage() {}
+
+func (x *QuarantinedTrade) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuarantinedTrade.ProtoReflect.Descriptor instead.
+func (*QuarantinedTrade) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *QuarantinedTrade) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *QuarantinedTrade) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *QuarantinedTrade) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *QuarantinedTrade) GetQty() float64 {
+	if x != nil {
+		return x.Qty
+	}
+	return 0
+}
+
+func (x *QuarantinedTrade) GetTs() int64 {
+	if x != nil {
+		return x.Ts
+	}
+	return 0
+}
+
+func (x *QuarantinedTrade) GetTradeId() string {
+	if x != nil {
+		return x.TradeId
+	}
+	return ""
+}
+
+func (x *QuarantinedTrade) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *QuarantinedTrade) GetReferencePrice() float64 {
+	if x != nil {
+		return x.ReferencePrice
+	}
+	return 0
+}
+
+func (x *QuarantinedTrade) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *QuarantinedTrade) GetRejectedTs() int64 {
+	if x != nil {
+		return x.RejectedTs
+	}
+	return 0
+}
+
+// Last-value cache
+type LatestPriceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbols       []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"` // empty = all symbols
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LatestPriceRequest) Reset() {
+	*x = LatestPriceRequest{}
+	mi := &file_price_v1_price_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatestPriceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatestPriceRequest) ProtoMessage() {}
+
+func (x *LatestPriceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatestPriceRequest.ProtoReflect.Descriptor instead.
+func (*LatestPriceRequest) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *LatestPriceRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+type LatestPrice struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Symbol         string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Price          float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"` // close of the latest update
+	Vwap           float64                `protobuf:"fixed64,3,opt,name=vwap,proto3" json:"vwap,omitempty"`
+	ConsensusPrice float64                `protobuf:"fixed64,4,opt,name=consensus_price,json=consensusPrice,proto3" json:"consensus_price,omitempty"` // consensus modes only
+	WindowStartMs  int64                  `protobuf:"varint,5,opt,name=window_start_ms,json=windowStartMs,proto3" json:"window_start_ms,omitempty"`
+	IsFinal        bool                   `protobuf:"varint,6,opt,name=is_final,json=isFinal,proto3" json:"is_final,omitempty"`
+	LastTradeTs    int64                  `protobuf:"varint,7,opt,name=last_trade_ts,json=lastTradeTs,proto3" json:"last_trade_ts,omitempty"`
+	UpdatedTs      int64                  `protobuf:"varint,8,opt,name=updated_ts,json=updatedTs,proto3" json:"updated_ts,omitempty"` // when the engine published the update
+	Stale          bool                   `protobuf:"varint,9,opt,name=stale,proto3" json:"stale,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *LatestPrice) Reset() {
+	*x = LatestPrice{}
+	mi := &file_price_v1_price_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatestPrice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatestPrice) ProtoMessage() {}
+
+func (x *LatestPrice) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatestPrice.ProtoReflect.Descriptor instead.
+func (*LatestPrice) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *LatestPrice) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *LatestPrice) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *LatestPrice) GetVwap() float64 {
+	if x != nil {
+		return x.Vwap
+	}
+	return 0
+}
+
+func (x *LatestPrice) GetConsensusPrice() float64 {
+	if x != nil {
+		return x.ConsensusPrice
+	}
+	return 0
+}
+
+func (x *LatestPrice) GetWindowStartMs() int64 {
+	if x != nil {
+		return x.WindowStartMs
+	}
+	return 0
+}
+
+func (x *LatestPrice) GetIsFinal() bool {
+	if x != nil {
+		return x.IsFinal
+	}
+	return false
+}
+
+func (x *LatestPrice) GetLastTradeTs() int64 {
+	if x != nil {
+		return x.LastTradeTs
+	}
+	return 0
+}
+
+func (x *LatestPrice) GetUpdatedTs() int64 {
+	if x != nil {
+		return x.UpdatedTs
+	}
+	return 0
+}
+
+func (x *LatestPrice) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+type LatestPriceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prices        []*LatestPrice         `protobuf:"bytes,1,rep,name=prices,proto3" json:"prices,omitempty"`
+	Missing       []string               `protobuf:"bytes,2,rep,name=missing,proto3" json:"missing,omitempty"` // requested symbols without data
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LatestPriceResponse) Reset() {
+	*x = LatestPriceResponse{}
+	mi := &file_price_v1_price_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatestPriceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatestPriceResponse) ProtoMessage() {}
+
+func (x *LatestPriceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatestPriceResponse.ProtoReflect.Descriptor instead.
+func (*LatestPriceResponse) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *LatestPriceResponse) GetPrices() []*LatestPrice {
+	if x != nil {
+		return x.Prices
+	}
+	return nil
+}
+
+func (x *LatestPriceResponse) GetMissing() []string {
+	if x != nil {
+		return x.Missing
+	}
+	return nil
+}
+
+type SnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotRequest) Reset() {
+	*x = SnapshotRequest{}
+	mi := &file_price_v1_price_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotRequest) ProtoMessage() {}
+
+func (x *SnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotRequest.ProtoReflect.Descriptor instead.
+func (*SnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{30}
+}
+
+type SnapshotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Candles       []*Candle              `protobuf:"bytes,1,rep,name=candles,proto3" json:"candles,omitempty"` // latest update (partial or final) per symbol
+	Final         []*Candle              `protobuf:"bytes,2,rep,name=final,proto3" json:"final,omitempty"`     // last closed window per symbol
+	Ts            int64                  `protobuf:"varint,3,opt,name=ts,proto3" json:"ts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotResponse) Reset() {
+	*x = SnapshotResponse{}
+	mi := &file_price_v1_price_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotResponse) ProtoMessage() {}
+
+func (x *SnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotResponse.ProtoReflect.Descriptor instead.
+func (*SnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SnapshotResponse) GetCandles() []*Candle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+func (x *SnapshotResponse) GetFinal() []*Candle {
+	if x != nil {
+		return x.Final
+	}
+	return nil
+}
+
+func (x *SnapshotResponse) GetTs() int64 {
+	if x != nil {
+		return x.Ts
+	}
+	return 0
+}
+
+type CandlesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	FromMs        int64                  `protobuf:"varint,2,opt,name=from_ms,json=fromMs,proto3" json:"from_ms,omitempty"` // window start, inclusive (0 = oldest kept)
+	ToMs          int64                  `protobuf:"varint,3,opt,name=to_ms,json=toMs,proto3" json:"to_ms,omitempty"`       // window start, exclusive (0 = now)
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`                 // most recent N of the range (0 = all)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CandlesRequest) Reset() {
+	*x = CandlesRequest{}
+	mi := &file_price_v1_price_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CandlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CandlesRequest) ProtoMessage() {}
+
+func (x *CandlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CandlesRequest.ProtoReflect.Descriptor instead.
+func (*CandlesRequest) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *CandlesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *CandlesRequest) GetFromMs() int64 {
+	if x != nil {
+		return x.FromMs
+	}
+	return 0
+}
+
+func (x *CandlesRequest) GetToMs() int64 {
+	if x != nil {
+		return x.ToMs
+	}
+	return 0
+}
+
+func (x *CandlesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type CandlesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Candles       []*Candle              `protobuf:"bytes,1,rep,name=candles,proto3" json:"candles,omitempty"` // closed windows, oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CandlesResponse) Reset() {
+	*x = CandlesResponse{}
+	mi := &file_price_v1_price_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CandlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CandlesResponse) ProtoMessage() {}
+
+func (x *CandlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CandlesResponse.ProtoReflect.Descriptor instead.
+func (*CandlesResponse) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CandlesResponse) GetCandles() []*Candle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+type UsageReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Client        string                 `protobuf:"bytes,1,opt,name=client,proto3" json:"client,omitempty"` // "" = all clients (admins only; others always get their own)
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	FromDay       string                 `protobuf:"bytes,3,opt,name=from_day,json=fromDay,proto3" json:"from_day,omitempty"` // YYYY-MM-DD (UTC), inclusive
+	ToDay         string                 `protobuf:"bytes,4,opt,name=to_day,json=toDay,proto3" json:"to_day,omitempty"`       // YYYY-MM-DD (UTC), inclusive
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsageReportRequest) Reset() {
+	*x = UsageReportRequest{}
+	mi := &file_price_v1_price_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageReportRequest) ProtoMessage() {}
+
+func (x *UsageReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageReportRequest.ProtoReflect.Descriptor instead.
+func (*UsageReportRequest) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *UsageReportRequest) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
+}
+
+func (x *UsageReportRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *UsageReportRequest) GetFromDay() string {
+	if x != nil {
+		return x.FromDay
+	}
+	return ""
+}
+
+func (x *UsageReportRequest) GetToDay() string {
+	if x != nil {
+		return x.ToDay
+	}
+	return ""
+}
+
+type UsageRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Day           string                 `protobuf:"bytes,1,opt,name=day,proto3" json:"day,omitempty"`
+	Client        string                 `protobuf:"bytes,2,opt,name=client,proto3" json:"client,omitempty"`
+	Symbol        string                 `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"` // "" for calls without a symbol
+	Subscriptions uint64                 `protobuf:"varint,4,opt,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	Messages      uint64                 `protobuf:"varint,5,opt,name=messages,proto3" json:"messages,omitempty"`
+	Bytes         uint64                 `protobuf:"varint,6,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	StreamSeconds float64                `protobuf:"fixed64,7,opt,name=stream_seconds,json=streamSeconds,proto3" json:"stream_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsageRow) Reset() {
+	*x = UsageRow{}
+	mi := &file_price_v1_price_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageRow) ProtoMessage() {}
+
+func (x *UsageRow) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageRow.ProtoReflect.Descriptor instead.
+func (*UsageRow) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *UsageRow) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *UsageRow) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
+}
+
+func (x *UsageRow) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *UsageRow) GetSubscriptions() uint64 {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return 0
+}
+
+func (x *UsageRow) GetMessages() uint64 {
+	if x != nil {
+		return x.Messages
+	}
+	return 0
+}
+
+func (x *UsageRow) GetBytes() uint64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *UsageRow) GetStreamSeconds() float64 {
+	if x != nil {
+		return x.StreamSeconds
+	}
+	return 0
+}
+
+type UsageReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          []*UsageRow            `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsageReportResponse) Reset() {
+	*x = UsageReportResponse{}
+	mi := &file_price_v1_price_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageReportResponse) ProtoMessage() {}
+
+func (x *UsageReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageReportResponse.ProtoReflect.Descriptor instead.
+func (*UsageReportResponse) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *UsageReportResponse) GetRows() []*UsageRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+type ListSymbolsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSymbolsRequest) Reset() {
+	*x = ListSymbolsRequest{}
+	mi := &file_price_v1_price_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSymbolsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSymbolsRequest) ProtoMessage() {}
+
+func (x *ListSymbolsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSymbolsRequest.ProtoReflect.Descriptor instead.
+func (*ListSymbolsRequest) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{37}
+}
+
+type SymbolInfo struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Symbol         string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	InstrumentType InstrumentType         `protobuf:"varint,2,opt,name=instrument_type,json=instrumentType,proto3,enum=price.v1.InstrumentType" json:"instrument_type,omitempty"`
+	PriceType      PriceType              `protobuf:"varint,3,opt,name=price_type,json=priceType,proto3,enum=price.v1.PriceType" json:"price_type,omitempty"`
+	BaseCcy        string                 `protobuf:"bytes,4,opt,name=base_ccy,json=baseCcy,proto3" json:"base_ccy,omitempty"`
+	QuoteCcy       string                 `protobuf:"bytes,5,opt,name=quote_ccy,json=quoteCcy,proto3" json:"quote_ccy,omitempty"`
+	Exchanges      []string               `protobuf:"bytes,6,rep,name=exchanges,proto3" json:"exchanges,omitempty"` // connectors and venues feeding the symbol
+	Synthetic      bool                   `protobuf:"varint,7,opt,name=synthetic,proto3" json:"synthetic,omitempty"`
+	FirstSeenTs    int64                  `protobuf:"varint,8,opt,name=first_seen_ts,json=firstSeenTs,proto3" json:"first_seen_ts,omitempty"`
+	LastUpdateTs   int64                  `protobuf:"varint,9,opt,name=last_update_ts,json=lastUpdateTs,proto3" json:"last_update_ts,omitempty"`
+	Updates        uint64                 `protobuf:"varint,10,opt,name=updates,proto3" json:"updates,omitempty"`
+	Stale          bool                   `protobuf:"varint,11,opt,name=stale,proto3" json:"stale,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SymbolInfo) Reset() {
+	*x = SymbolInfo{}
+	mi := &file_price_v1_price_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SymbolInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SymbolInfo) ProtoMessage() {}
+
+func (x *SymbolInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SymbolInfo.ProtoReflect.Descriptor instead.
+func (*SymbolInfo) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *SymbolInfo) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *SymbolInfo) GetInstrumentType() InstrumentType {
+	if x != nil {
+		return x.InstrumentType
+	}
+	return InstrumentType_IT_UNSPECIFIED
+}
+
+func (x *SymbolInfo) GetPriceType() PriceType {
+	if x != nil {
+		return x.PriceType
+	}
+	return PriceType_PT_UNSPECIFIED
+}
+
+func (x *SymbolInfo) GetBaseCcy() string {
+	if x != nil {
+		return x.BaseCcy
+	}
+	return ""
+}
+
+func (x *SymbolInfo) GetQuoteCcy() string {
+	if x != nil {
+		return x.QuoteCcy
+	}
+	return ""
+}
+
+func (x *SymbolInfo) GetExchanges() []string {
+	if x != nil {
+		return x.Exchanges
+	}
+	return nil
+}
+
+func (x *SymbolInfo) GetSynthetic() bool {
+	if x != nil {
+		return x.Synthetic
+	}
+	return false
+}
+
+func (x *SymbolInfo) GetFirstSeenTs() int64 {
+	if x != nil {
+		return x.FirstSeenTs
+	}
+	return 0
+}
+
+func (x *SymbolInfo) GetLastUpdateTs() int64 {
+	if x != nil {
+		return x.LastUpdateTs
+	}
+	return 0
+}
+
+func (x *SymbolInfo) GetUpdates() uint64 {
+	if x != nil {
+		return x.Updates
+	}
+	return 0
+}
+
+func (x *SymbolInfo) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+type ListSymbolsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbols       []*SymbolInfo          `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSymbolsResponse) Reset() {
+	*x = ListSymbolsResponse{}
+	mi := &file_price_v1_price_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSymbolsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSymbolsResponse) ProtoMessage() {}
+
+func (x *ListSymbolsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSymbolsResponse.ProtoReflect.Descriptor instead.
+func (*ListSymbolsResponse) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListSymbolsResponse) GetSymbols() []*SymbolInfo {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+// One StreamAggregateEvents message: a candle, a heartbeat or a status event.
+type AggregateEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*AggregateEvent_Candle
+	//	*AggregateEvent_Heartbeat
+	//	*AggregateEvent_Status
+	Event         isAggregateEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AggregateEvent) Reset() {
+	*x = AggregateEvent{}
+	mi := &file_price_v1_price_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AggregateEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregateEvent) ProtoMessage() {}
+
+func (x *AggregateEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregateEvent.ProtoReflect.Descriptor instead.
+func (*AggregateEvent) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *AggregateEvent) GetEvent() isAggregateEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *AggregateEvent) GetCandle() *Candle {
+	if x != nil {
+		if x, ok := x.Event.(*AggregateEvent_Candle); ok {
+			return x.Candle
+		}
+	}
+	return nil
+}
+
+func (x *AggregateEvent) GetHeartbeat() *Heartbeat {
+	if x != nil {
+		if x, ok := x.Event.(*AggregateEvent_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
+}
+
+func (x *AggregateEvent) GetStatus() *StreamStatus {
+	if x != nil {
+		if x, ok := x.Event.(*AggregateEvent_Status); ok {
+			return x.Status
+		}
+	}
+	return nil
+}
+
+type isAggregateEvent_Event interface {
+	isAggregateEvent_Event()
+}
+
+type AggregateEvent_Candle struct {
+	Candle *Candle `protobuf:"bytes,1,opt,name=candle,proto3,oneof"`
+}
+
+type AggregateEvent_Heartbeat struct {
+	Heartbeat *Heartbeat `protobuf:"bytes,2,opt,name=heartbeat,proto3,oneof"`
+}
+
+type AggregateEvent_Status struct {
+	Status *StreamStatus `protobuf:"bytes,3,opt,name=status,proto3,oneof"`
+}
+
+func (*AggregateEvent_Candle) isAggregateEvent_Event() {}
+
+func (*AggregateEvent_Heartbeat) isAggregateEvent_Event() {}
+
+func (*AggregateEvent_Status) isAggregateEvent_Event() {}
+
+// Sent when a stream was otherwise silent for heartbeat_interval_ms.
+type Heartbeat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerTimeMs  int64                  `protobuf:"varint,1,opt,name=server_time_ms,json=serverTimeMs,proto3" json:"server_time_ms,omitempty"`
+	LastUpdateMs  int64                  `protobuf:"varint,2,opt,name=last_update_ms,json=lastUpdateMs,proto3" json:"last_update_ms,omitempty"` // when the symbol last updated on the engine, 0 = never
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	mi := &file_price_v1_price_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heartbeat) ProtoMessage() {}
+
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *Heartbeat) GetServerTimeMs() int64 {
+	if x != nil {
+		return x.ServerTimeMs
+	}
+	return 0
+}
+
+func (x *Heartbeat) GetLastUpdateMs() int64 {
+	if x != nil {
+		return x.LastUpdateMs
+	}
+	return 0
+}
+
+// Sent when a stream starts (the market state, and stale sources if any)
+// and whenever the state changes.
+type StreamStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          StreamStatusKind       `protobuf:"varint,1,opt,name=kind,proto3,enum=price.v1.StreamStatusKind" json:"kind,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	TsMs          int64                  `protobuf:"varint,3,opt,name=ts_ms,json=tsMs,proto3" json:"ts_ms,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	NextOpenMs    int64                  `protobuf:"varint,5,opt,name=next_open_ms,json=nextOpenMs,proto3" json:"next_open_ms,omitempty"`    // MARKET_CLOSED, SYMBOL_HALTED: expected reopening, 0 = unknown
+	StaleSources  []string               `protobuf:"bytes,6,rep,name=stale_sources,json=staleSources,proto3" json:"stale_sources,omitempty"` // SOURCE_STALE
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamStatus) Reset() {
+	*x = StreamStatus{}
+	mi := &file_price_v1_price_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamStatus) ProtoMessage() {}
+
+func (x *StreamStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_price_v1_price_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamStatus.ProtoReflect.Descriptor instead.
+func (*StreamStatus) Descriptor() ([]byte, []int) {
+	return file_price_v1_price_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *StreamStatus) GetKind() StreamStatusKind {
+	if x != nil {
+		return x.Kind
+	}
+	return StreamStatusKind_SK_UNSPECIFIED
+}
+
+func (x *StreamStatus) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *StreamStatus) GetTsMs() int64 {
+	if x != nil {
+		return x.TsMs
+	}
+	return 0
+}
+
+func (x *StreamStatus) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *StreamStatus) GetNextOpenMs() int64 {
+	if x != nil {
+		return x.NextOpenMs
+	}
+	return 0
+}
+
+func (x *StreamStatus) GetStaleSources() []string {
+	if x != nil {
+		return x.StaleSources
+	}
+	return nil
+}
+
+var File_price_v1_price_proto protoreflect.FileDescriptor
+
+const file_price_v1_price_proto_rawDesc = "" +
+	"\n" +
+	"\x14price/v1/price.proto\x12\bprice.v1\"\xae\x02\n" +
+	"\x10SubscribeRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1f\n" +
+	"\vinterval_ms\x18\x02 \x01(\x03R\n" +
+	"intervalMs\x12,\n" +
+	"\bbar_type\x18\x03 \x01(\x0e2\x11.price.v1.BarTypeR\abarType\x12\x19\n" +
+	"\bbar_size\x18\x04 \x01(\x01R\abarSize\x12\x1d\n" +
+	"\n" +
+	"final_only\x18\x05 \x01(\bR\tfinalOnly\x12-\n" +
+	"\x13max_updates_per_sec\x18\x06 \x01(\x01R\x10maxUpdatesPerSec\x12\x16\n" +
+	"\x06fields\x18\a \x03(\tR\x06fields\x122\n" +
+	"\x15heartbeat_interval_ms\x18\b \x01(\x03R\x13heartbeatIntervalMs\"\xb0\n" +
+	"\n" +
+	"\x06Candle\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12&\n" +
+	"\x0fwindow_start_ms\x18\x02 \x01(\x03R\rwindowStartMs\x12\"\n" +
+	"\rwindow_end_ms\x18\x03 \x01(\x03R\vwindowEndMs\x12\x12\n" +
+	"\x04open\x18\x04 \x01(\x01R\x04open\x12\x12\n" +
+	"\x04high\x18\x05 \x01(\x01R\x04high\x12\x10\n" +
+	"\x03low\x18\x06 \x01(\x01R\x03low\x12\x14\n" +
+	"\x05close\x18\a \x01(\x01R\x05close\x12\x16\n" +
+	"\x06volume\x18\b \x01(\x01R\x06volume\x12\x12\n" +
+	"\x04vwap\x18\t \x01(\x01R\x04vwap\x12\x19\n" +
+	"\bis_final\x18\n" +
+	" \x01(\bR\aisFinal\x12\x1a\n" +
+	"\bexchange\x18\v \x01(\tR\bexchange\x12\"\n" +
+	"\rlast_trade_ts\x18\f \x01(\x03R\vlastTradeTs\x12\x1f\n" +
+	"\vtrade_count\x18\r \x01(\x04R\n" +
+	"tradeCount\x12A\n" +
+	"\x0finstrument_type\x18\x0e \x01(\x0e2\x18.price.v1.InstrumentTypeR\x0einstrumentType\x122\n" +
+	"\n" +
+	"price_type\x18\x0f \x01(\x0e2\x13.price.v1.PriceTypeR\tpriceType\x12\x19\n" +
+	"\bbase_ccy\x18\x10 \x01(\tR\abaseCcy\x12\x1b\n" +
+	"\tquote_ccy\x18\x11 \x01(\tR\bquoteCcy\x12\x1c\n" +
+	"\tsynthetic\x18\x12 \x01(\bR\tsynthetic\x124\n" +
+	"\vsource_legs\x18\x13 \x03(\v2\x13.price.v1.SourceLegR\n" +
+	"sourceLegs\x12'\n" +
+	"\x0fconsensus_price\x18\x14 \x01(\x01R\x0econsensusPrice\x12/\n" +
+	"\x13contributing_venues\x18\x15 \x03(\tR\x12contributingVenues\x12'\n" +
+	"\x0fexcluded_venues\x18\x16 \x03(\tR\x0eexcludedVenues\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x17 \x01(\x01R\n" +
+	"confidence\x12\x14\n" +
+	"\x05stale\x18\x18 \x01(\bR\x05stale\x12#\n" +
+	"\rstale_sources\x18\x19 \x03(\tR\fstaleSources\x12\x1d\n" +
+	"\n" +
+	"spread_avg\x18\x1a \x01(\x01R\tspreadAvg\x12\x1d\n" +
+	"\n" +
+	"spread_max\x18\x1b \x01(\x01R\tspreadMax\x12\x1d\n" +
+	"\n" +
+	"spread_twa\x18\x1c \x01(\x01R\tspreadTwa\x12\x1f\n" +
+	"\vquote_count\x18\x1d \x01(\x04R\n" +
+	"quoteCount\x12\x1d\n" +
+	"\n" +
+	"buy_volume\x18\x1e \x01(\x01R\tbuyVolume\x12\x1f\n" +
+	"\vsell_volume\x18\x1f \x01(\x01R\n" +
+	"sellVolume\x12\x1b\n" +
+	"\tbuy_count\x18  \x01(\x04R\bbuyCount\x12\x1d\n" +
+	"\n" +
+	"sell_count\x18! \x01(\x04R\tsellCount\x12!\n" +
+	"\fvolume_delta\x18\" \x01(\x01R\vvolumeDelta\x12)\n" +
+	"\x10cumulative_delta\x18# \x01(\x01R\x0fcumulativeDelta\x12<\n" +
+	"\fsize_buckets\x18$ \x03(\v2\x19.price.v1.TradeSizeBucketR\vsizeBuckets\x12,\n" +
+	"\bbar_type\x18% \x01(\x0e2\x11.price.v1.BarTypeR\abarType\x12\x19\n" +
+	"\bbar_size\x18& \x01(\x01R\abarSize\x12\x1a\n" +
+	"\brevision\x18' \x01(\rR\brevision\"f\n" +
+	"\x0fTradeSizeBucket\x12%\n" +
+	"\x0eupper_notional\x18\x01 \x01(\x01R\rupperNotional\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x04R\x05count\x12\x16\n" +
+	"\x06volume\x18\x03 \x01(\x01R\x06volume\"\x81\x01\n" +
+	"\tSourceLeg\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bexchange\x18\x02 \x01(\tR\bexchange\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x1a\n" +
+	"\binverted\x18\x04 \x01(\bR\binverted\x12\x0e\n" +
+	"\x02ts\x18\x05 \x01(\x03R\x02ts\"K\n" +
+	"\x15ExchangeStatusRequest\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\"\xcc\x02\n" +
+	"\x0eExchangeStatus\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12.\n" +
+	"\x05state\x18\x03 \x01(\x0e2\x18.price.v1.ConnectorStateR\x05state\x12$\n" +
+	"\x0estate_since_ts\x18\x04 \x01(\x03R\fstateSinceTs\x12&\n" +
+	"\x0flast_message_ts\x18\x05 \x01(\x03R\rlastMessageTs\x12'\n" +
+	"\x0freconnect_count\x18\x06 \x01(\x04R\x0ereconnectCount\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\a \x01(\tR\tlastError\x12\x18\n" +
+	"\areplica\x18\b \x01(\rR\areplica\x12&\n" +
+	"\x04feed\x18\t \x01(\x0e2\x12.price.v1.FeedKindR\x04feed\"N\n" +
+	"\x16ExchangeStatusResponse\x124\n" +
+	"\bstatuses\x18\x01 \x03(\v2\x18.price.v1.ExchangeStatusR\bstatuses\"@\n" +
+	"\x10OrderBookRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\rR\x05depth\"4\n" +
+	"\n" +
+	"PriceLevel\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\x01R\x05price\x12\x10\n" +
+	"\x03qty\x18\x02 \x01(\x01R\x03qty\"\xc3\x02\n" +
+	"\tOrderBook\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bexchange\x18\x02 \x01(\tR\bexchange\x12$\n" +
+	"\x0elast_update_id\x18\x03 \x01(\x04R\flastUpdateId\x12\x0e\n" +
+	"\x02ts\x18\x04 \x01(\x03R\x02ts\x12/\n" +
+	"\bbest_bid\x18\x05 \x01(\v2\x14.price.v1.PriceLevelR\abestBid\x12/\n" +
+	"\bbest_ask\x18\x06 \x01(\v2\x14.price.v1.PriceLevelR\abestAsk\x12\x16\n" +
+	"\x06spread\x18\a \x01(\x01R\x06spread\x12(\n" +
+	"\x04bids\x18\b \x03(\v2\x14.price.v1.PriceLevelR\x04bids\x12(\n" +
+	"\x04asks\x18\t \x03(\v2\x14.price.v1.PriceLevelR\x04asks\"\xb4\x01\n" +
+	"\rVolumeAtPrice\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\x01R\x05price\x12\x16\n" +
+	"\x06volume\x18\x02 \x01(\x01R\x06volume\x12\x1d\n" +
+	"\n" +
+	"buy_volume\x18\x03 \x01(\x01R\tbuyVolume\x12\x1f\n" +
+	"\vsell_volume\x18\x04 \x01(\x01R\n" +
+	"sellVolume\x12\x14\n" +
+	"\x05delta\x18\x05 \x01(\x01R\x05delta\x12\x1f\n" +
+	"\vtrade_count\x18\x06 \x01(\x04R\n" +
+	"tradeCount\"\xea\x02\n" +
+	"\rVolumeProfile\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12&\n" +
+	"\x0fwindow_start_ms\x18\x02 \x01(\x03R\rwindowStartMs\x12\"\n" +
+	"\rwindow_end_ms\x18\x03 \x01(\x03R\vwindowEndMs\x12\x1b\n" +
+	"\ttick_size\x18\x04 \x01(\x01R\btickSize\x12!\n" +
+	"\ftotal_volume\x18\x05 \x01(\x01R\vtotalVolume\x12\x1b\n" +
+	"\tpoc_price\x18\x06 \x01(\x01R\bpocPrice\x12$\n" +
+	"\x0evalue_area_low\x18\a \x01(\x01R\fvalueAreaLow\x12&\n" +
+	"\x0fvalue_area_high\x18\b \x01(\x01R\rvalueAreaHigh\x12\x19\n" +
+	"\bis_final\x18\t \x01(\bR\aisFinal\x12/\n" +
+	"\x06levels\x18\n" +
+	" \x03(\v2\x17.price.v1.VolumeAtPriceR\x06levels\"&\n" +
+	"\fQuoteRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"\xcb\x01\n" +
+	"\x05Quote\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bexchange\x18\x02 \x01(\tR\bexchange\x12\x10\n" +
+	"\x03bid\x18\x03 \x01(\x01R\x03bid\x12\x17\n" +
+	"\abid_qty\x18\x04 \x01(\x01R\x06bidQty\x12\x10\n" +
+	"\x03ask\x18\x05 \x01(\x01R\x03ask\x12\x17\n" +
+	"\aask_qty\x18\x06 \x01(\x01R\x06askQty\x12\x16\n" +
+	"\x06spread\x18\a \x01(\x01R\x06spread\x12\x10\n" +
+	"\x03mid\x18\b \x01(\x01R\x03mid\x12\x0e\n" +
+	"\x02ts\x18\t \x01(\x03R\x02ts\"|\n" +
+	"\x0eIndicatorValue\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\x12\x14\n" +
+	"\x05upper\x18\x03 \x01(\x01R\x05upper\x12\x14\n" +
+	"\x05lower\x18\x04 \x01(\x01R\x05lower\x12\x14\n" +
+	"\x05ready\x18\x05 \x01(\bR\x05ready\"\xde\x01\n" +
+	"\x0fIndicatorUpdate\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1f\n" +
+	"\vinterval_ms\x18\x02 \x01(\x03R\n" +
+	"intervalMs\x12&\n" +
+	"\x0fwindow_start_ms\x18\x03 \x01(\x03R\rwindowStartMs\x12\"\n" +
+	"\rwindow_end_ms\x18\x04 \x01(\x03R\vwindowEndMs\x12\x14\n" +
+	"\x05close\x18\x05 \x01(\x01R\x05close\x120\n" +
+	"\x06values\x18\x06 \x03(\v2\x18.price.v1.IndicatorValueR\x06values\";\n" +
+	"\rFixingRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"c\n" +
+	"\fFixingSource\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x1f\n" +
+	"\vtrade_count\x18\x02 \x01(\x04R\n" +
+	"tradeCount\x12\x16\n" +
+	"\x06volume\x18\x03 \x01(\x01R\x06volume\"\x9e\x03\n" +
+	"\x06Fixing\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x16\n" +
+	"\x06method\x18\x03 \x01(\tR\x06method\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\x01R\x05value\x12\x12\n" +
+	"\x04twap\x18\x05 \x01(\x01R\x04twap\x12\x12\n" +
+	"\x04vwap\x18\x06 \x01(\x01R\x04vwap\x12\x16\n" +
+	"\x06median\x18\a \x01(\x01R\x06median\x12&\n" +
+	"\x0fwindow_start_ms\x18\b \x01(\x03R\rwindowStartMs\x12\"\n" +
+	"\rwindow_end_ms\x18\t \x01(\x03R\vwindowEndMs\x12\x1f\n" +
+	"\vtrade_count\x18\n" +
+	" \x01(\x04R\n" +
+	"tradeCount\x12\x16\n" +
+	"\x06volume\x18\v \x01(\x01R\x06volume\x120\n" +
+	"\asources\x18\f \x03(\v2\x16.price.v1.FixingSourceR\asources\x12 \n" +
+	"\vmethodology\x18\r \x01(\tR\vmethodology\x12!\n" +
+	"\fpublished_ts\x18\x0e \x01(\x03R\vpublishedTs\"\xb3\x02\n" +
+	"\tAlertRule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12'\n" +
+	"\x04kind\x18\x03 \x01(\x0e2\x13.price.v1.AlertKindR\x04kind\x12\x14\n" +
+	"\x05level\x18\x04 \x01(\x01R\x05level\x12\x1c\n" +
+	"\tthreshold\x18\x05 \x01(\x01R\tthreshold\x12\x18\n" +
+	"\awindows\x18\x06 \x01(\x05R\awindows\x12$\n" +
+	"\x0estale_after_ms\x18\a \x01(\x03R\fstaleAfterMs\x12\x18\n" +
+	"\awebhook\x18\b \x01(\bR\awebhook\x12\x12\n" +
+	"\x04note\x18\t \x01(\tR\x04note\x12\x1d\n" +
+	"\n" +
+	"created_ts\x18\n" +
+	" \x01(\x03R\tcreatedTs\x12\x14\n" +
+	"\x05owner\x18\v \x01(\tR\x05owner\"\xf7\x01\n" +
+	"\x05Alert\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\tR\x06ruleId\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12'\n" +
+	"\x04kind\x18\x03 \x01(\x0e2\x13.price.v1.AlertKindR\x04kind\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12\x14\n" +
+	"\x05value\x18\x05 \x01(\x01R\x05value\x12\x1c\n" +
+	"\tthreshold\x18\x06 \x01(\x01R\tthreshold\x12\"\n" +
+	"\rwindow_end_ms\x18\a \x01(\x03R\vwindowEndMs\x12\x0e\n" +
+	"\x02ts\x18\b \x01(\x03R\x02ts\x12\x12\n" +
+	"\x04note\x18\t \x01(\tR\x04note\"&\n" +
+	"\fAlertRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"(\n" +
+	"\x16DeleteAlertRuleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"3\n" +
+	"\x17DeleteAlertRuleResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\"/\n" +
+	"\x15ListAlertRulesRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"C\n" +
+	"\x16ListAlertRulesResponse\x12)\n" +
+	"\x05rules\x18\x01 \x03(\v2\x13.price.v1.AlertRuleR\x05rules\"\x91\x02\n" +
+	"\x10QuarantinedTrade\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bexchange\x18\x02 \x01(\tR\bexchange\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x10\n" +
+	"\x03qty\x18\x04 \x01(\x01R\x03qty\x12\x0e\n" +
+	"\x02ts\x18\x05 \x01(\x03R\x02ts\x12\x19\n" +
+	"\btrade_id\x18\x06 \x01(\tR\atradeId\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x12'\n" +
+	"\x0freference_price\x18\b \x01(\x01R\x0ereferencePrice\x12\x14\n" +
+	"\x05score\x18\t \x01(\x01R\x05score\x12\x1f\n" +
+	"\vrejected_ts\x18\n" +
+	" \x01(\x03R\n" +
+	"rejectedTs\".\n" +
+	"\x12LatestPriceRequest\x12\x18\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\"\x94\x02\n" +
+	"\vLatestPrice\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x01R\x05price\x12\x12\n" +
+	"\x04vwap\x18\x03 \x01(\x01R\x04vwap\x12'\n" +
+	"\x0fconsensus_price\x18\x04 \x01(\x01R\x0econsensusPrice\x12&\n" +
+	"\x0fwindow_start_ms\x18\x05 \x01(\x03R\rwindowStartMs\x12\x19\n" +
+	"\bis_final\x18\x06 \x01(\bR\aisFinal\x12\"\n" +
+	"\rlast_trade_ts\x18\a \x01(\x03R\vlastTradeTs\x12\x1d\n" +
+	"\n" +
+	"updated_ts\x18\b \x01(\x03R\tupdatedTs\x12\x14\n" +
+	"\x05stale\x18\t \x01(\bR\x05stale\"^\n" +
+	"\x13LatestPriceResponse\x12-\n" +
+	"\x06prices\x18\x01 \x03(\v2\x15.price.v1.LatestPriceR\x06prices\x12\x18\n" +
+	"\amissing\x18\x02 \x03(\tR\amissing\"\x11\n" +
+	"\x0fSnapshotRequest\"v\n" +
+	"\x10SnapshotResponse\x12*\n" +
+	"\acandles\x18\x01 \x03(\v2\x10.price.v1.CandleR\acandles\x12&\n" +
+	"\x05final\x18\x02 \x03(\v2\x10.price.v1.CandleR\x05final\x12\x0e\n" +
+	"\x02ts\x18\x03 \x01(\x03R\x02ts\"l\n" +
+	"\x0eCandlesRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x17\n" +
+	"\afrom_ms\x18\x02 \x01(\x03R\x06fromMs\x12\x13\n" +
+	"\x05to_ms\x18\x03 \x01(\x03R\x04toMs\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"=\n" +
+	"\x0fCandlesResponse\x12*\n" +
+	"\acandles\x18\x01 \x03(\v2\x10.price.v1.CandleR\acandles\"v\n" +
+	"\x12UsageReportRequest\x12\x16\n" +
+	"\x06client\x18\x01 \x01(\tR\x06client\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x19\n" +
+	"\bfrom_day\x18\x03 \x01(\tR\afromDay\x12\x15\n" +
+	"\x06to_day\x18\x04 \x01(\tR\x05toDay\"\xcb\x01\n" +
+	"\bUsageRow\x12\x10\n" +
+	"\x03day\x18\x01 \x01(\tR\x03day\x12\x16\n" +
+	"\x06client\x18\x02 \x01(\tR\x06client\x12\x16\n" +
+	"\x06symbol\x18\x03 \x01(\tR\x06symbol\x12$\n" +
+	"\rsubscriptions\x18\x04 \x01(\x04R\rsubscriptions\x12\x1a\n" +
+	"\bmessages\x18\x05 \x01(\x04R\bmessages\x12\x14\n" +
+	"\x05bytes\x18\x06 \x01(\x04R\x05bytes\x12%\n" +
+	"\x0estream_seconds\x18\a \x01(\x01R\rstreamSeconds\"=\n" +
+	"\x13UsageReportResponse\x12&\n" +
+	"\x04rows\x18\x01 \x03(\v2\x12.price.v1.UsageRowR\x04rows\"\x14\n" +
+	"\x12ListSymbolsRequest\"\x89\x03\n" +
+	"\n" +
+	"SymbolInfo\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12A\n" +
+	"\x0finstrument_type\x18\x02 \x01(\x0e2\x18.price.v1.InstrumentTypeR\x0einstrumentType\x122\n" +
+	"\n" +
+	"price_type\x18\x03 \x01(\x0e2\x13.price.v1.PriceTypeR\tpriceType\x12\x19\n" +
+	"\bbase_ccy\x18\x04 \x01(\tR\abaseCcy\x12\x1b\n" +
+	"\tquote_ccy\x18\x05 \x01(\tR\bquoteCcy\x12\x1c\n" +
+	"\texchanges\x18\x06 \x03(\tR\texchanges\x12\x1c\n" +
+	"\tsynthetic\x18\a \x01(\bR\tsynthetic\x12\"\n" +
+	"\rfirst_seen_ts\x18\b \x01(\x03R\vfirstSeenTs\x12$\n" +
+	"\x0elast_update_ts\x18\t \x01(\x03R\flastUpdateTs\x12\x18\n" +
+	"\aupdates\x18\n" +
+	" \x01(\x04R\aupdates\x12\x14\n" +
+	"\x05stale\x18\v \x01(\bR\x05stale\"E\n" +
+	"\x13ListSymbolsResponse\x12.\n" +
+	"\asymbols\x18\x01 \x03(\v2\x14.price.v1.SymbolInfoR\asymbols\"\xac\x01\n" +
+	"\x0eAggregateEvent\x12*\n" +
+	"\x06candle\x18\x01 \x01(\v2\x10.price.v1.CandleH\x00R\x06candle\x123\n" +
+	"\theartbeat\x18\x02 \x01(\v2\x13.price.v1.HeartbeatH\x00R\theartbeat\x120\n" +
+	"\x06status\x18\x03 \x01(\v2\x16.price.v1.StreamStatusH\x00R\x06statusB\a\n" +
+	"\x05event\"W\n" +
+	"\tHeartbeat\x12$\n" +
+	"\x0eserver_time_ms\x18\x01 \x01(\x03R\fserverTimeMs\x12$\n" +
+	"\x0elast_update_ms\x18\x02 \x01(\x03R\flastUpdateMs\"\xca\x01\n" +
+	"\fStreamStatus\x12.\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x1a.price.v1.StreamStatusKindR\x04kind\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x13\n" +
+	"\x05ts_ms\x18\x03 \x01(\x03R\x04tsMs\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12 \n" +
+	"\fnext_open_ms\x18\x05 \x01(\x03R\n" +
+	"nextOpenMs\x12#\n" +
+	"\rstale_sources\x18\x06 \x03(\tR\fstaleSources*E\n" +
+	"\aBarType\x12\f\n" +
+	"\bBAR_TIME\x10\x00\x12\f\n" +
+	"\bBAR_TICK\x10\x01\x12\x0e\n" +
+	"\n" +
+	"BAR_VOLUME\x10\x02\x12\x0e\n" +
+	"\n" +
+	"BAR_DOLLAR\x10\x03*H\n" +
+	"\x0eInstrumentType\x12\x12\n" +
+	"\x0eIT_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eIT_CRYPTO_SPOT\x10\x01\x12\x0e\n" +
+	"\n" +
+	"IT_FX_SPOT\x10\x02*Q\n" +
+	"\tPriceType\x12\x12\n" +
+	"\x0ePT_UNSPECIFIED\x10\x00\x12\f\n" +
+	"\bPT_TRADE\x10\x01\x12\n" +
+	"\n" +
+	"\x06PT_BID\x10\x02\x12\n" +
+	"\n" +
+	"\x06PT_ASK\x10\x03\x12\n" +
+	"\n" +
+	"\x06PT_MID\x10\x04*z\n" +
+	"\x0eConnectorState\x12\x12\n" +
+	"\x0eCS_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rCS_CONNECTING\x10\x01\x12\v\n" +
+	"\aCS_LIVE\x10\x02\x12\f\n" +
+	"\bCS_STALE\x10\x03\x12\x12\n" +
+	"\x0eCS_BACKING_OFF\x10\x04\x12\x12\n" +
+	"\x0eCS_AUTH_FAILED\x10\x05*J\n" +
+	"\bFeedKind\x12\x12\n" +
+	"\x0eFK_UNSPECIFIED\x10\x00\x12\r\n" +
+	"\tFK_TRADES\x10\x01\x12\r\n" +
+	"\tFK_QUOTES\x10\x02\x12\f\n" +
+	"\bFK_DEPTH\x10\x03*\x81\x01\n" +
+	"\tAlertKind\x12\x12\n" +
+	"\x0eAK_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eAK_PRICE_CROSS\x10\x01\x12\x0f\n" +
+	"\vAK_PCT_MOVE\x10\x02\x12\x13\n" +
+	"\x0fAK_SPREAD_ABOVE\x10\x03\x12\x13\n" +
+	"\x0fAK_VOLUME_SPIKE\x10\x04\x12\x11\n" +
+	"\rAK_FEED_STALE\x10\x05*\xac\x01\n" +
+	"\x10StreamStatusKind\x12\x12\n" +
+	"\x0eSK_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eSK_MARKET_OPEN\x10\x01\x12\x14\n" +
+	"\x10SK_MARKET_CLOSED\x10\x02\x12\x14\n" +
+	"\x10SK_SYMBOL_HALTED\x10\x03\x12\x13\n" +
+	"\x0fSK_SOURCE_STALE\x10\x04\x12\x12\n" +
+	"\x0eSK_SOURCE_LIVE\x10\x05\x12\x1b\n" +
+	"\x17SK_RESUBSCRIBE_REQUIRED\x10\x062\xc1\n" +
+	"\n" +
+	"\vPriceStream\x12B\n" +
+	"\x10StreamAggregates\x12\x1a.price.v1.SubscribeRequest\x1a\x10.price.v1.Candle0\x01\x12O\n" +
+	"\x15StreamAggregateEvents\x12\x1a.price.v1.SubscribeRequest\x1a\x18.price.v1.AggregateEvent0\x01\x12V\n" +
+	"\x11GetExchangeStatus\x12\x1f.price.v1.ExchangeStatusRequest\x1a .price.v1.ExchangeStatusResponse\x12S\n" +
+	"\x14StreamExchangeStatus\x12\x1f.price.v1.ExchangeStatusRequest\x1a\x18.price.v1.ExchangeStatus0\x01\x12D\n" +
+	"\x0fStreamOrderBook\x12\x1a.price.v1.OrderBookRequest\x1a\x13.price.v1.OrderBook0\x01\x129\n" +
+	"\fStreamQuotes\x12\x16.price.v1.QuoteRequest\x1a\x0f.price.v1.Quote0\x01\x12L\n" +
+	"\x13StreamVolumeProfile\x12\x1a.price.v1.SubscribeRequest\x1a\x17.price.v1.VolumeProfile0\x01\x12K\n" +
+	"\x10StreamIndicators\x12\x1a.price.v1.SubscribeRequest\x1a\x19.price.v1.IndicatorUpdate0\x01\x12<\n" +
+	"\rStreamFixings\x12\x17.price.v1.FixingRequest\x1a\x10.price.v1.Fixing0\x01\x12;\n" +
+	"\x0fCreateAlertRule\x12\x13.price.v1.AlertRule\x1a\x13.price.v1.AlertRule\x12V\n" +
+	"\x0fDeleteAlertRule\x12 .price.v1.DeleteAlertRuleRequest\x1a!.price.v1.DeleteAlertRuleResponse\x12S\n" +
+	"\x0eListAlertRules\x12\x1f.price.v1.ListAlertRulesRequest\x1a .price.v1.ListAlertRulesResponse\x129\n" +
+	"\fStreamAlerts\x12\x16.price.v1.AlertRequest\x1a\x0f.price.v1.Alert0\x01\x12M\n" +
+	"\x0eGetLatestPrice\x12\x1c.price.v1.LatestPriceRequest\x1a\x1d.price.v1.LatestPriceResponse\x12D\n" +
+	"\vGetSnapshot\x12\x19.price.v1.SnapshotRequest\x1a\x1a.price.v1.SnapshotResponse\x12J\n" +
+	"\vListSymbols\x12\x1c.price.v1.ListSymbolsRequest\x1a\x1d.price.v1.ListSymbolsResponse\x12A\n" +
+	"\n" +
+	"GetCandles\x12\x18.price.v1.CandlesRequest\x1a\x19.price.v1.CandlesResponse\x12M\n" +
+	"\x0eGetUsageReport\x12\x1c.price.v1.UsageReportRequest\x1a\x1d.price.v1.UsageReportResponseB>Z<github.com/binaridigital/price-engine/proto/price/v1;pricev1b\x06proto3"
+
+var (
+	file_price_v1_price_proto_rawDescOnce sync.Once
+	file_price_v1_price_proto_rawDescData []byte
+)
+
+func file_price_v1_price_proto_rawDescGZIP() []byte {
+	file_price_v1_price_proto_rawDescOnce.Do(func() {
+		file_price_v1_price_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_price_v1_price_proto_rawDesc), len(file_price_v1_price_proto_rawDesc)))
+	})
+	return file_price_v1_price_proto_rawDescData
+}
+
+var file_price_v1_price_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_price_v1_price_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
+var file_price_v1_price_proto_goTypes = []any{
+	(BarType)(0),                    // 0: price.v1.BarType
+	(InstrumentType)(0),             // 1: price.v1.InstrumentType
+	(PriceType)(0),                  // 2: price.v1.PriceType
+	(ConnectorState)(0),             // 3: price.v1.ConnectorState
+	(FeedKind)(0),                   // 4: price.v1.FeedKind
+	(AlertKind)(0),                  // 5: price.v1.AlertKind
+	(StreamStatusKind)(0),           // 6: price.v1.StreamStatusKind
+	(*SubscribeRequest)(nil),        // 7: price.v1.SubscribeRequest
+	(*Candle)(nil),                  // 8: price.v1.Candle
+	(*TradeSizeBucket)(nil),         // 9: price.v1.TradeSizeBucket
+	(*SourceLeg)(nil),               // 10: price.v1.SourceLeg
+	(*ExchangeStatusRequest)(nil),   // 11: price.v1.ExchangeStatusRequest
+	(*ExchangeStatus)(nil),          // 12: price.v1.ExchangeStatus
+	(*ExchangeStatusResponse)(nil),  // 13: price.v1.ExchangeStatusResponse
+	(*OrderBookRequest)(nil),        // 14: price.v1.OrderBookRequest
+	(*PriceLevel)(nil),              // 15: price.v1.PriceLevel
+	(*OrderBook)(nil),               // 16: price.v1.OrderBook
+	(*VolumeAtPrice)(nil),           // 17: price.v1.VolumeAtPrice
+	(*VolumeProfile)(nil),           // 18: price.v1.VolumeProfile
+	(*QuoteRequest)(nil),            // 19: price.v1.QuoteRequest
+	(*Quote)(nil),                   // 20: price.v1.Quote
+	(*IndicatorValue)(nil),          // 21: price.v1.IndicatorValue
+	(*IndicatorUpdate)(nil),         // 22: price.v1.IndicatorUpdate
+	(*FixingRequest)(nil),           // 23: price.v1.FixingRequest
+	(*FixingSource)(nil),            // 24: price.v1.FixingSource
+	(*Fixing)(nil),                  // 25: price.v1.Fixing
+	(*AlertRule)(nil),               // 26: price.v1.AlertRule
+	(*Alert)(nil),                   // 27: price.v1.Alert
+	(*AlertRequest)(nil),            // 28: price.v1.AlertRequest
+	(*DeleteAlertRuleRequest)(nil),  // 29: price.v1.DeleteAlertRuleRequest
+	(*DeleteAlertRuleResponse)(nil), // 30: price.v1.DeleteAlertRuleResponse
+	(*ListAlertRulesRequest)(nil),   // 31: price.v1.ListAlertRulesRequest
+	(*ListAlertRulesResponse)(nil),  // 32: price.v1.ListAlertRulesResponse
+	(*QuarantinedTrade)(nil),        // 33: price.v1.QuarantinedTrade
+	(*LatestPriceRequest)(nil),      // 34: price.v1.LatestPriceRequest
+	(*LatestPrice)(nil),             // 35: price.v1.LatestPrice
+	(*LatestPriceResponse)(nil),     // 36: price.v1.LatestPriceResponse
+	(*SnapshotRequest)(nil),         // 37: price.v1.SnapshotRequest
+	(*SnapshotResponse)(nil),        // 38: price.v1.SnapshotResponse
+	(*CandlesRequest)(nil),          // 39: price.v1.CandlesRequest
+	(*CandlesResponse)(nil),         // 40: price.v1.CandlesResponse
+	(*UsageReportRequest)(nil),      // 41: price.v1.UsageReportRequest
+	(*UsageRow)(nil),                // 42: price.v1.UsageRow
+	(*UsageReportResponse)(nil),     // 43: price.v1.UsageReportResponse
+	(*ListSymbolsRequest)(nil),      // 44: price.v1.ListSymbolsRequest
+	(*SymbolInfo)(nil),              // 45: price.v1.SymbolInfo
+	(*ListSymbolsResponse)(nil),     // 46: price.v1.ListSymbolsResponse
+	(*AggregateEvent)(nil),          // 47: price.v1.AggregateEvent
+	(*Heartbeat)(nil),               // 48: price.v1.Heartbeat
+	(*StreamStatus)(nil),            // 49: price.v1.StreamStatus
+}
+var file_price_v1_price_proto_depIdxs = []int32{
+	0,  // 0: price.v1.SubscribeRequest.bar_type:type_name -> price.v1.BarType
+	1,  // 1: price.v1.Candle.instrument_type:type_name -> price.v1.InstrumentType
+	2,  // 2: price.v1.Candle.price_type:type_name -> price.v1.PriceType
+	10, // 3: price.v1.Candle.source_legs:type_name -> price.v1.SourceLeg
+	9,  // 4: price.v1.Candle.size_buckets:type_name -> price.v1.TradeSizeBucket
+	0,  // 5: price.v1.Candle.bar_type:type_name -> price.v1.BarType
+	3,  // 6: price.v1.ExchangeStatus.state:type_name -> price.v1.ConnectorState
+	4,  // 7: price.v1.ExchangeStatus.feed:type_name -> price.v1.FeedKind
+	12, // 8: price.v1.ExchangeStatusResponse.statuses:type_name -> price.v1.ExchangeStatus
+	15, // 9: price.v1.OrderBook.best_bid:type_name -> price.v1.PriceLevel
+	15, // 10: price.v1.OrderBook.best_ask:type_name -> price.v1.PriceLevel
+	15, // 11: price.v1.OrderBook.bids:type_name -> price.v1.PriceLevel
+	15, // 12: price.v1.OrderBook.asks:type_name -> price.v1.PriceLevel
+	17, // 13: price.v1.VolumeProfile.levels:type_name -> price.v1.VolumeAtPrice
+	21, // 14: price.v1.IndicatorUpdate.values:type_name -> price.v1.IndicatorValue
+	24, // 15: price.v1.Fixing.sources:type_name -> price.v1.FixingSource
+	5,  // 16: price.v1.AlertRule.kind:type_name -> price.v1.AlertKind
+	5,  // 17: price.v1.Alert.kind:type_name -> price.v1.AlertKind
+	26, // 18: price.v1.ListAlertRulesResponse.rules:type_name -> price.v1.AlertRule
+	35, // 19: price.v1.LatestPriceResponse.prices:type_name -> price.v1.LatestPrice
+	8,  // 20: price.v1.SnapshotResponse.candles:type_name -> price.v1.Candle
+	8,  // 21: price.v1.SnapshotResponse.final:type_name -> price.v1.Candle
+	8,  // 22: price.v1.CandlesResponse.candles:type_name -> price.v1.Candle
+	42, // 23: price.v1.UsageReportResponse.rows:type_name -> price.v1.UsageRow
+	1,  // 24: price.v1.SymbolInfo.instrument_type:type_name -> price.v1.InstrumentType
+	2,  // 25: price.v1.SymbolInfo.price_type:type_name -> price.v1.PriceType
+	45, // 26: price.v1.ListSymbolsResponse.symbols:type_name -> price.v1.SymbolInfo
+	8,  // 27: price.v1.AggregateEvent.candle:type_name -> price.v1.Candle
+	48, // 28: price.v1.AggregateEvent.heartbeat:type_name -> price.v1.Heartbeat
+	49, // 29: price.v1.AggregateEvent.status:type_name -> price.v1.StreamStatus
+	6,  // 30: price.v1.StreamStatus.kind:type_name -> price.v1.StreamStatusKind
+	7,  // 31: price.v1.PriceStream.StreamAggregates:input_type -> price.v1.SubscribeRequest
+	7,  // 32: price.v1.PriceStream.StreamAggregateEvents:input_type -> price.v1.SubscribeRequest
+	11, // 33: price.v1.PriceStream.GetExchangeStatus:input_type -> price.v1.ExchangeStatusRequest
+	11, // 34: price.v1.PriceStream.StreamExchangeStatus:input_type -> price.v1.ExchangeStatusRequest
+	14, // 35: price.v1.PriceStream.StreamOrderBook:input_type -> price.v1.OrderBookRequest
+	19, // 36: price.v1.PriceStream.StreamQuotes:input_type -> price.v1.QuoteRequest
+	7,  // 37: price.v1.PriceStream.StreamVolumeProfile:input_type -> price.v1.SubscribeRequest
+	7,  // 38: price.v1.PriceStream.StreamIndicators:input_type -> price.v1.SubscribeRequest
+	23, // 39: price.v1.PriceStream.StreamFixings:input_type -> price.v1.FixingRequest
+	26, // 40: price.v1.PriceStream.CreateAlertRule:input_type -> price.v1.AlertRule
+	29, // 41: price.v1.PriceStream.DeleteAlertRule:input_type -> price.v1.DeleteAlertRuleRequest
+	31, // 42: price.v1.PriceStream.ListAlertRules:input_type -> price.v1.ListAlertRulesRequest
+	28, // 43: price.v1.PriceStream.StreamAlerts:input_type -> price.v1.AlertRequest
+	34, // 44: price.v1.PriceStream.GetLatestPrice:input_type -> price.v1.LatestPriceRequest
+	37, // 45: price.v1.PriceStream.GetSnapshot:input_type -> price.v1.SnapshotRequest
+	44, // 46: price.v1.PriceStream.ListSymbols:input_type -> price.v1.ListSymbolsRequest
+	39, // 47: price.v1.PriceStream.GetCandles:input_type -> price.v1.CandlesRequest
+	41, // 48: price.v1.PriceStream.GetUsageReport:input_type -> price.v1.UsageReportRequest
+	8,  // 49: price.v1.PriceStream.StreamAggregates:output_type -> price.v1.Candle
+	47, // 50: price.v1.PriceStream.StreamAggregateEvents:output_type -> price.v1.AggregateEvent
+	13, // 51: price.v1.PriceStream.GetExchangeStatus:output_type -> price.v1.ExchangeStatusResponse
+	12, // 52: price.v1.PriceStream.StreamExchangeStatus:output_type -> price.v1.ExchangeStatus
+	16, // 53: price.v1.PriceStream.StreamOrderBook:output_type -> price.v1.OrderBook
+	20, // 54: price.v1.PriceStream.StreamQuotes:output_type -> price.v1.Quote
+	18, // 55: price.v1.PriceStream.StreamVolumeProfile:output_type -> price.v1.VolumeProfile
+	22, // 56: price.v1.PriceStream.StreamIndicators:output_type -> price.v1.IndicatorUpdate
+	25, // 57: price.v1.PriceStream.StreamFixings:output_type -> price.v1.Fixing
+	26, // 58: price.v1.PriceStream.CreateAlertRule:output_type -> price.v1.AlertRule
+	30, // 59: price.v1.PriceStream.DeleteAlertRule:output_type -> price.v1.DeleteAlertRuleResponse
+	32, // 60: price.v1.PriceStream.ListAlertRules:output_type -> price.v1.ListAlertRulesResponse
+	27, // 61: price.v1.PriceStream.StreamAlerts:output_type -> price.v1.Alert
+	36, // 62: price.v1.PriceStream.GetLatestPrice:output_type -> price.v1.LatestPriceResponse
+	38, // 63: price.v1.PriceStream.GetSnapshot:output_type -> price.v1.SnapshotResponse
+	46, // 64: price.v1.PriceStream.ListSymbols:output_type -> price.v1.ListSymbolsResponse
+	40, // 65: price.v1.PriceStream.GetCandles:output_type -> price.v1.CandlesResponse
+	43, // 66: price.v1.PriceStream.GetUsageReport:output_type -> price.v1.UsageReportResponse
+	49, // [49:67] is the sub-list for method output_type
+	31, // [31:49] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
+}
+
+func init() { file_price_v1_price_proto_init() }
+func file_price_v1_price_proto_init() {
+	if File_price_v1_price_proto != nil {
+		return
+	}
+	file_price_v1_price_proto_msgTypes[40].OneofWrappers = []any{
+		(*AggregateEvent_Candle)(nil),
+		(*AggregateEvent_Heartbeat)(nil),
+		(*AggregateEvent_Status)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_price_v1_price_proto_rawDesc), len(file_price_v1_price_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   2,
+			NumEnums:      7,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
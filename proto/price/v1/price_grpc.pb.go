@@ -21,7 +21,24 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PriceStream_StreamAggregates_FullMethodName = "/price.v1.PriceStream/StreamAggregates"
+	PriceStream_StreamAggregates_FullMethodName      = "/price.v1.PriceStream/StreamAggregates"
+	PriceStream_StreamAggregateEvents_FullMethodName = "/price.v1.PriceStream/StreamAggregateEvents"
+	PriceStream_GetExchangeStatus_FullMethodName     = "/price.v1.PriceStream/GetExchangeStatus"
+	PriceStream_StreamExchangeStatus_FullMethodName  = "/price.v1.PriceStream/StreamExchangeStatus"
+	PriceStream_StreamOrderBook_FullMethodName       = "/price.v1.PriceStream/StreamOrderBook"
+	PriceStream_StreamQuotes_FullMethodName          = "/price.v1.PriceStream/StreamQuotes"
+	PriceStream_StreamVolumeProfile_FullMethodName   = "/price.v1.PriceStream/StreamVolumeProfile"
+	PriceStream_StreamIndicators_FullMethodName      = "/price.v1.PriceStream/StreamIndicators"
+	PriceStream_StreamFixings_FullMethodName         = "/price.v1.PriceStream/StreamFixings"
+	PriceStream_CreateAlertRule_FullMethodName       = "/price.v1.PriceStream/CreateAlertRule"
+	PriceStream_DeleteAlertRule_FullMethodName       = "/price.v1.PriceStream/DeleteAlertRule"
+	PriceStream_ListAlertRules_FullMethodName        = "/price.v1.PriceStream/ListAlertRules"
+	PriceStream_StreamAlerts_FullMethodName          = "/price.v1.PriceStream/StreamAlerts"
+	PriceStream_GetLatestPrice_FullMethodName        = "/price.v1.PriceStream/GetLatestPrice"
+	PriceStream_GetSnapshot_FullMethodName           = "/price.v1.PriceStream/GetSnapshot"
+	PriceStream_ListSymbols_FullMethodName           = "/price.v1.PriceStream/ListSymbols"
+	PriceStream_GetCandles_FullMethodName            = "/price.v1.PriceStream/GetCandles"
+	PriceStream_GetUsageReport_FullMethodName        = "/price.v1.PriceStream/GetUsageReport"
 )
 
 // PriceStreamClient is the client API for PriceStream service.
@@ -29,6 +46,35 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type PriceStreamClient interface {
 	StreamAggregates(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Candle], error)
+	// The candles of StreamAggregates, interleaved with heartbeats and
+	// market/source status events.
+	StreamAggregateEvents(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AggregateEvent], error)
+	GetExchangeStatus(ctx context.Context, in *ExchangeStatusRequest, opts ...grpc.CallOption) (*ExchangeStatusResponse, error)
+	// Current status of every matching stream, then each change as it happens.
+	StreamExchangeStatus(ctx context.Context, in *ExchangeStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExchangeStatus], error)
+	// Current book, then the book after every applied depth update.
+	StreamOrderBook(ctx context.Context, in *OrderBookRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderBook], error)
+	// Best bid/offer updates as they arrive.
+	StreamQuotes(ctx context.Context, in *QuoteRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Quote], error)
+	// Final volume profile per window (interval_ms must match the engine's profile interval).
+	StreamVolumeProfile(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[VolumeProfile], error)
+	// Indicator values after every final candle (interval_ms must match the engine's interval).
+	StreamIndicators(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IndicatorUpdate], error)
+	// Latest fixing for the symbol, then each new one as it is published.
+	StreamFixings(ctx context.Context, in *FixingRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Fixing], error)
+	// Alert rules are evaluated on final candles and persisted across restarts.
+	CreateAlertRule(ctx context.Context, in *AlertRule, opts ...grpc.CallOption) (*AlertRule, error)
+	DeleteAlertRule(ctx context.Context, in *DeleteAlertRuleRequest, opts ...grpc.CallOption) (*DeleteAlertRuleResponse, error)
+	ListAlertRules(ctx context.Context, in *ListAlertRulesRequest, opts ...grpc.CallOption) (*ListAlertRulesResponse, error)
+	StreamAlerts(ctx context.Context, in *AlertRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Alert], error)
+	// Served from the in-memory last-value cache.
+	GetLatestPrice(ctx context.Context, in *LatestPriceRequest, opts ...grpc.CallOption) (*LatestPriceResponse, error)
+	GetSnapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*SnapshotResponse, error)
+	ListSymbols(ctx context.Context, in *ListSymbolsRequest, opts ...grpc.CallOption) (*ListSymbolsResponse, error)
+	// Closed windows from the in-memory history.
+	GetCandles(ctx context.Context, in *CandlesRequest, opts ...grpc.CallOption) (*CandlesResponse, error)
+	// Per-client usage metering.
+	GetUsageReport(ctx context.Context, in *UsageReportRequest, opts ...grpc.CallOption) (*UsageReportResponse, error)
 }
 
 type priceStreamClient struct {
@@ -58,11 +104,282 @@ func (c *priceStreamClient) StreamAggregates(ctx context.Context, in *SubscribeR
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PriceStream_StreamAggregatesClient = grpc.ServerStreamingClient[Candle]
 
+func (c *priceStreamClient) StreamAggregateEvents(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AggregateEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PriceStream_ServiceDesc.Streams[1], PriceStream_StreamAggregateEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, AggregateEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamAggregateEventsClient = grpc.ServerStreamingClient[AggregateEvent]
+
+func (c *priceStreamClient) GetExchangeStatus(ctx context.Context, in *ExchangeStatusRequest, opts ...grpc.CallOption) (*ExchangeStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExchangeStatusResponse)
+	err := c.cc.Invoke(ctx, PriceStream_GetExchangeStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceStreamClient) StreamExchangeStatus(ctx context.Context, in *ExchangeStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExchangeStatus], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PriceStream_ServiceDesc.Streams[2], PriceStream_StreamExchangeStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExchangeStatusRequest, ExchangeStatus]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamExchangeStatusClient = grpc.ServerStreamingClient[ExchangeStatus]
+
+func (c *priceStreamClient) StreamOrderBook(ctx context.Context, in *OrderBookRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderBook], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PriceStream_ServiceDesc.Streams[3], PriceStream_StreamOrderBook_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[OrderBookRequest, OrderBook]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamOrderBookClient = grpc.ServerStreamingClient[OrderBook]
+
+func (c *priceStreamClient) StreamQuotes(ctx context.Context, in *QuoteRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Quote], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PriceStream_ServiceDesc.Streams[4], PriceStream_StreamQuotes_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[QuoteRequest, Quote]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamQuotesClient = grpc.ServerStreamingClient[Quote]
+
+func (c *priceStreamClient) StreamVolumeProfile(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[VolumeProfile], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PriceStream_ServiceDesc.Streams[5], PriceStream_StreamVolumeProfile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, VolumeProfile]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamVolumeProfileClient = grpc.ServerStreamingClient[VolumeProfile]
+
+func (c *priceStreamClient) StreamIndicators(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IndicatorUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PriceStream_ServiceDesc.Streams[6], PriceStream_StreamIndicators_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, IndicatorUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamIndicatorsClient = grpc.ServerStreamingClient[IndicatorUpdate]
+
+func (c *priceStreamClient) StreamFixings(ctx context.Context, in *FixingRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Fixing], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PriceStream_ServiceDesc.Streams[7], PriceStream_StreamFixings_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FixingRequest, Fixing]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamFixingsClient = grpc.ServerStreamingClient[Fixing]
+
+func (c *priceStreamClient) CreateAlertRule(ctx context.Context, in *AlertRule, opts ...grpc.CallOption) (*AlertRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AlertRule)
+	err := c.cc.Invoke(ctx, PriceStream_CreateAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceStreamClient) DeleteAlertRule(ctx context.Context, in *DeleteAlertRuleRequest, opts ...grpc.CallOption) (*DeleteAlertRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAlertRuleResponse)
+	err := c.cc.Invoke(ctx, PriceStream_DeleteAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceStreamClient) ListAlertRules(ctx context.Context, in *ListAlertRulesRequest, opts ...grpc.CallOption) (*ListAlertRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAlertRulesResponse)
+	err := c.cc.Invoke(ctx, PriceStream_ListAlertRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceStreamClient) StreamAlerts(ctx context.Context, in *AlertRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Alert], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PriceStream_ServiceDesc.Streams[8], PriceStream_StreamAlerts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AlertRequest, Alert]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamAlertsClient = grpc.ServerStreamingClient[Alert]
+
+func (c *priceStreamClient) GetLatestPrice(ctx context.Context, in *LatestPriceRequest, opts ...grpc.CallOption) (*LatestPriceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LatestPriceResponse)
+	err := c.cc.Invoke(ctx, PriceStream_GetLatestPrice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceStreamClient) GetSnapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*SnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SnapshotResponse)
+	err := c.cc.Invoke(ctx, PriceStream_GetSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceStreamClient) ListSymbols(ctx context.Context, in *ListSymbolsRequest, opts ...grpc.CallOption) (*ListSymbolsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSymbolsResponse)
+	err := c.cc.Invoke(ctx, PriceStream_ListSymbols_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceStreamClient) GetCandles(ctx context.Context, in *CandlesRequest, opts ...grpc.CallOption) (*CandlesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CandlesResponse)
+	err := c.cc.Invoke(ctx, PriceStream_GetCandles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceStreamClient) GetUsageReport(ctx context.Context, in *UsageReportRequest, opts ...grpc.CallOption) (*UsageReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UsageReportResponse)
+	err := c.cc.Invoke(ctx, PriceStream_GetUsageReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PriceStreamServer is the server API for PriceStream service.
 // All implementations must embed UnimplementedPriceStreamServer
 // for forward compatibility.
 type PriceStreamServer interface {
 	StreamAggregates(*SubscribeRequest, grpc.ServerStreamingServer[Candle]) error
+	// The candles of StreamAggregates, interleaved with heartbeats and
+	// market/source status events.
+	StreamAggregateEvents(*SubscribeRequest, grpc.ServerStreamingServer[AggregateEvent]) error
+	GetExchangeStatus(context.Context, *ExchangeStatusRequest) (*ExchangeStatusResponse, error)
+	// Current status of every matching stream, then each change as it happens.
+	StreamExchangeStatus(*ExchangeStatusRequest, grpc.ServerStreamingServer[ExchangeStatus]) error
+	// Current book, then the book after every applied depth update.
+	StreamOrderBook(*OrderBookRequest, grpc.ServerStreamingServer[OrderBook]) error
+	// Best bid/offer updates as they arrive.
+	StreamQuotes(*QuoteRequest, grpc.ServerStreamingServer[Quote]) error
+	// Final volume profile per window (interval_ms must match the engine's profile interval).
+	StreamVolumeProfile(*SubscribeRequest, grpc.ServerStreamingServer[VolumeProfile]) error
+	// Indicator values after every final candle (interval_ms must match the engine's interval).
+	StreamIndicators(*SubscribeRequest, grpc.ServerStreamingServer[IndicatorUpdate]) error
+	// Latest fixing for the symbol, then each new one as it is published.
+	StreamFixings(*FixingRequest, grpc.ServerStreamingServer[Fixing]) error
+	// Alert rules are evaluated on final candles and persisted across restarts.
+	CreateAlertRule(context.Context, *AlertRule) (*AlertRule, error)
+	DeleteAlertRule(context.Context, *DeleteAlertRuleRequest) (*DeleteAlertRuleResponse, error)
+	ListAlertRules(context.Context, *ListAlertRulesRequest) (*ListAlertRulesResponse, error)
+	StreamAlerts(*AlertRequest, grpc.ServerStreamingServer[Alert]) error
+	// Served from the in-memory last-value cache.
+	GetLatestPrice(context.Context, *LatestPriceRequest) (*LatestPriceResponse, error)
+	GetSnapshot(context.Context, *SnapshotRequest) (*SnapshotResponse, error)
+	ListSymbols(context.Context, *ListSymbolsRequest) (*ListSymbolsResponse, error)
+	// Closed windows from the in-memory history.
+	GetCandles(context.Context, *CandlesRequest) (*CandlesResponse, error)
+	// Per-client usage metering.
+	GetUsageReport(context.Context, *UsageReportRequest) (*UsageReportResponse, error)
 	mustEmbedUnimplementedPriceStreamServer()
 }
 
@@ -76,6 +393,57 @@ type UnimplementedPriceStreamServer struct{}
 func (UnimplementedPriceStreamServer) StreamAggregates(*SubscribeRequest, grpc.ServerStreamingServer[Candle]) error {
 	return status.Errorf(codes.Unimplemented, "method StreamAggregates not implemented")
 }
+func (UnimplementedPriceStreamServer) StreamAggregateEvents(*SubscribeRequest, grpc.ServerStreamingServer[AggregateEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamAggregateEvents not implemented")
+}
+func (UnimplementedPriceStreamServer) GetExchangeStatus(context.Context, *ExchangeStatusRequest) (*ExchangeStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExchangeStatus not implemented")
+}
+func (UnimplementedPriceStreamServer) StreamExchangeStatus(*ExchangeStatusRequest, grpc.ServerStreamingServer[ExchangeStatus]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamExchangeStatus not implemented")
+}
+func (UnimplementedPriceStreamServer) StreamOrderBook(*OrderBookRequest, grpc.ServerStreamingServer[OrderBook]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamOrderBook not implemented")
+}
+func (UnimplementedPriceStreamServer) StreamQuotes(*QuoteRequest, grpc.ServerStreamingServer[Quote]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamQuotes not implemented")
+}
+func (UnimplementedPriceStreamServer) StreamVolumeProfile(*SubscribeRequest, grpc.ServerStreamingServer[VolumeProfile]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamVolumeProfile not implemented")
+}
+func (UnimplementedPriceStreamServer) StreamIndicators(*SubscribeRequest, grpc.ServerStreamingServer[IndicatorUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamIndicators not implemented")
+}
+func (UnimplementedPriceStreamServer) StreamFixings(*FixingRequest, grpc.ServerStreamingServer[Fixing]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamFixings not implemented")
+}
+func (UnimplementedPriceStreamServer) CreateAlertRule(context.Context, *AlertRule) (*AlertRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAlertRule not implemented")
+}
+func (UnimplementedPriceStreamServer) DeleteAlertRule(context.Context, *DeleteAlertRuleRequest) (*DeleteAlertRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAlertRule not implemented")
+}
+func (UnimplementedPriceStreamServer) ListAlertRules(context.Context, *ListAlertRulesRequest) (*ListAlertRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlertRules not implemented")
+}
+func (UnimplementedPriceStreamServer) StreamAlerts(*AlertRequest, grpc.ServerStreamingServer[Alert]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamAlerts not implemented")
+}
+func (UnimplementedPriceStreamServer) GetLatestPrice(context.Context, *LatestPriceRequest) (*LatestPriceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLatestPrice not implemented")
+}
+func (UnimplementedPriceStreamServer) GetSnapshot(context.Context, *SnapshotRequest) (*SnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSnapshot not implemented")
+}
+func (UnimplementedPriceStreamServer) ListSymbols(context.Context, *ListSymbolsRequest) (*ListSymbolsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSymbols not implemented")
+}
+func (UnimplementedPriceStreamServer) GetCandles(context.Context, *CandlesRequest) (*CandlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCandles not implemented")
+}
+func (UnimplementedPriceStreamServer) GetUsageReport(context.Context, *UsageReportRequest) (*UsageReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsageReport not implemented")
+}
 func (UnimplementedPriceStreamServer) mustEmbedUnimplementedPriceStreamServer() {}
 func (UnimplementedPriceStreamServer) testEmbeddedByValue()                     {}
 
@@ -108,19 +476,346 @@ func _PriceStream_StreamAggregates_Handler(srv interface{}, stream grpc.ServerSt
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PriceStream_StreamAggregatesServer = grpc.ServerStreamingServer[Candle]
 
+func _PriceStream_StreamAggregateEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PriceStreamServer).StreamAggregateEvents(m, &grpc.GenericServerStream[SubscribeRequest, AggregateEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamAggregateEventsServer = grpc.ServerStreamingServer[AggregateEvent]
+
+func _PriceStream_GetExchangeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExchangeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceStreamServer).GetExchangeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceStream_GetExchangeStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceStreamServer).GetExchangeStatus(ctx, req.(*ExchangeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceStream_StreamExchangeStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExchangeStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PriceStreamServer).StreamExchangeStatus(m, &grpc.GenericServerStream[ExchangeStatusRequest, ExchangeStatus]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamExchangeStatusServer = grpc.ServerStreamingServer[ExchangeStatus]
+
+func _PriceStream_StreamOrderBook_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(OrderBookRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PriceStreamServer).StreamOrderBook(m, &grpc.GenericServerStream[OrderBookRequest, OrderBook]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamOrderBookServer = grpc.ServerStreamingServer[OrderBook]
+
+func _PriceStream_StreamQuotes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QuoteRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PriceStreamServer).StreamQuotes(m, &grpc.GenericServerStream[QuoteRequest, Quote]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamQuotesServer = grpc.ServerStreamingServer[Quote]
+
+func _PriceStream_StreamVolumeProfile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PriceStreamServer).StreamVolumeProfile(m, &grpc.GenericServerStream[SubscribeRequest, VolumeProfile]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamVolumeProfileServer = grpc.ServerStreamingServer[VolumeProfile]
+
+func _PriceStream_StreamIndicators_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PriceStreamServer).StreamIndicators(m, &grpc.GenericServerStream[SubscribeRequest, IndicatorUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamIndicatorsServer = grpc.ServerStreamingServer[IndicatorUpdate]
+
+func _PriceStream_StreamFixings_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FixingRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PriceStreamServer).StreamFixings(m, &grpc.GenericServerStream[FixingRequest, Fixing]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamFixingsServer = grpc.ServerStreamingServer[Fixing]
+
+func _PriceStream_CreateAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlertRule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceStreamServer).CreateAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceStream_CreateAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceStreamServer).CreateAlertRule(ctx, req.(*AlertRule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceStream_DeleteAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAlertRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceStreamServer).DeleteAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceStream_DeleteAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceStreamServer).DeleteAlertRule(ctx, req.(*DeleteAlertRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceStream_ListAlertRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlertRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceStreamServer).ListAlertRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceStream_ListAlertRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceStreamServer).ListAlertRules(ctx, req.(*ListAlertRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceStream_StreamAlerts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AlertRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PriceStreamServer).StreamAlerts(m, &grpc.GenericServerStream[AlertRequest, Alert]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceStream_StreamAlertsServer = grpc.ServerStreamingServer[Alert]
+
+func _PriceStream_GetLatestPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LatestPriceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceStreamServer).GetLatestPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceStream_GetLatestPrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceStreamServer).GetLatestPrice(ctx, req.(*LatestPriceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceStream_GetSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceStreamServer).GetSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceStream_GetSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceStreamServer).GetSnapshot(ctx, req.(*SnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceStream_ListSymbols_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSymbolsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceStreamServer).ListSymbols(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceStream_ListSymbols_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceStreamServer).ListSymbols(ctx, req.(*ListSymbolsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceStream_GetCandles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CandlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceStreamServer).GetCandles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceStream_GetCandles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceStreamServer).GetCandles(ctx, req.(*CandlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceStream_GetUsageReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsageReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceStreamServer).GetUsageReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceStream_GetUsageReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceStreamServer).GetUsageReport(ctx, req.(*UsageReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PriceStream_ServiceDesc is the grpc.ServiceDesc for PriceStream service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var PriceStream_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "price.v1.PriceStream",
 	HandlerType: (*PriceStreamServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetExchangeStatus",
+			Handler:    _PriceStream_GetExchangeStatus_Handler,
+		},
+		{
+			MethodName: "CreateAlertRule",
+			Handler:    _PriceStream_CreateAlertRule_Handler,
+		},
+		{
+			MethodName: "DeleteAlertRule",
+			Handler:    _PriceStream_DeleteAlertRule_Handler,
+		},
+		{
+			MethodName: "ListAlertRules",
+			Handler:    _PriceStream_ListAlertRules_Handler,
+		},
+		{
+			MethodName: "GetLatestPrice",
+			Handler:    _PriceStream_GetLatestPrice_Handler,
+		},
+		{
+			MethodName: "GetSnapshot",
+			Handler:    _PriceStream_GetSnapshot_Handler,
+		},
+		{
+			MethodName: "ListSymbols",
+			Handler:    _PriceStream_ListSymbols_Handler,
+		},
+		{
+			MethodName: "GetCandles",
+			Handler:    _PriceStream_GetCandles_Handler,
+		},
+		{
+			MethodName: "GetUsageReport",
+			Handler:    _PriceStream_GetUsageReport_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "StreamAggregates",
 			Handler:       _PriceStream_StreamAggregates_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamAggregateEvents",
+			Handler:       _PriceStream_StreamAggregateEvents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamExchangeStatus",
+			Handler:       _PriceStream_StreamExchangeStatus_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamOrderBook",
+			Handler:       _PriceStream_StreamOrderBook_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamQuotes",
+			Handler:       _PriceStream_StreamQuotes_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamVolumeProfile",
+			Handler:       _PriceStream_StreamVolumeProfile_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamIndicators",
+			Handler:       _PriceStream_StreamIndicators_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamFixings",
+			Handler:       _PriceStream_StreamFixings_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamAlerts",
+			Handler:       _PriceStream_StreamAlerts_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "price/v1/price.proto",
 }